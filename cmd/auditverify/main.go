@@ -0,0 +1,81 @@
+// Command auditverify checks the audit log for tampering. It recomputes
+// the hash of every entry, checks each one links to the entry before it
+// and prints the head of the chain. Record the head somewhere the database
+// can't reach and pass it back with -head on the next run: removing
+// entries from the end of the chain leaves the links intact, but not the
+// recorded head. Exits with status 1 when the chain is broken.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"testcase/config"
+	"testcase/internal/infrastructures/database"
+	auditRepositories "testcase/internal/modules/audit/repositories"
+	"testcase/internal/modules/audit/responses"
+	auditServices "testcase/internal/modules/audit/services"
+	organizationRepositories "testcase/internal/modules/organization/repositories"
+)
+
+func main() {
+	head := flag.String("head", "", "previously recorded head as SEQUENCE:HASH")
+	flag.Parse()
+
+	var anchor *responses.ChainHead
+	if *head != "" {
+		parsed, err := parseHead(*head)
+		if err != nil {
+			log.Fatalf("Invalid -head: %v", err)
+		}
+		anchor = parsed
+	}
+
+	cfg := config.LoadConfig()
+	db, err := database.NewDatabase(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	service := auditServices.NewAuditService(
+		auditRepositories.NewAuditRepository(db),
+		organizationRepositories.NewOrganizationRepository(db),
+		cfg,
+	)
+	result, err := service.Verify(context.Background(), anchor)
+	if err != nil {
+		log.Fatalf("Failed to verify audit log: %v", err)
+	}
+
+	if !result.Valid {
+		fmt.Printf("audit log broken at sequence %d: %s\n", result.BrokenAt, result.Problem)
+		fmt.Printf("last intact entry: %d %s\n", result.HeadSequence, result.HeadHash)
+		db.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("verified %d entries\n", result.Entries)
+	fmt.Printf("head: %d:%s\n", result.HeadSequence, result.HeadHash)
+}
+
+func parseHead(value string) (*responses.ChainHead, error) {
+	sequence, hash, ok := strings.Cut(value, ":")
+	if !ok || hash == "" {
+		return nil, fmt.Errorf("expected SEQUENCE:HASH, got %q", value)
+	}
+	parsed, err := strconv.ParseInt(sequence, 10, 64)
+	if err != nil || parsed < 1 {
+		return nil, fmt.Errorf("invalid sequence %q", sequence)
+	}
+
+	return &responses.ChainHead{Sequence: parsed, Hash: hash}, nil
+}
@@ -7,12 +7,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
 	"testcase/config"
 	"testcase/internal/infrastructures/database"
 	"testcase/internal/middlewares"
+	"testcase/internal/modules/review"
+	"testcase/internal/modules/stream"
 	"testcase/internal/routes"
 
 	"github.com/gin-contrib/cors"
@@ -24,6 +27,8 @@ type Server struct {
 	database *database.Database
 	router   *gin.Engine
 	server   *http.Server
+
+	stopWorkers context.CancelFunc
 }
 
 func NewServer(cfg *config.Config, db *database.Database) *Server {
@@ -34,6 +39,11 @@ func NewServer(cfg *config.Config, db *database.Database) *Server {
 	}
 
 	router := gin.New()
+	// Lockouts, sessions and the audit log key on the client IP, so
+	// X-Forwarded-For is only believed from configured proxies.
+	if err := router.SetTrustedProxies(cfg.HttpServer.TrustedProxies); err != nil {
+		log.Fatalf("Invalid HTTP_TRUSTED_PROXIES: %v", err)
+	}
 
 	setupMiddleware(router, cfg)
 
@@ -51,6 +61,7 @@ func NewServer(cfg *config.Config, db *database.Database) *Server {
 func setupMiddleware(router *gin.Engine, cfg *config.Config) {
 	router.Use(gin.Recovery())
 	router.Use(middlewares.ErrorHandlerMiddleware())
+	router.Use(middlewares.ClientInfoMiddleware())
 
 	if cfg.HttpServer.Env != "production" {
 		router.Use(gin.Logger())
@@ -62,7 +73,7 @@ func setupMiddleware(router *gin.Engine, cfg *config.Config) {
 	corsConfig.AllowAllOrigins = false
 	corsConfig.AllowOrigins = []string{"http://localhost:3000", "https://yourdomain.com"}
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-Step-Up-Token", "X-API-Key", "X-Key-Id", "X-Timestamp", "X-Nonce", "X-Signature"}
 	corsConfig.ExposeHeaders = []string{"Content-Length"}
 	corsConfig.AllowCredentials = true
 	corsConfig.MaxAge = 12 * time.Hour
@@ -71,7 +82,7 @@ func setupMiddleware(router *gin.Engine, cfg *config.Config) {
 
 	router.Use(securityHeaders())
 
-	router.Use(timeoutMiddleware(30 * time.Second))
+	router.Use(timeoutMiddleware(30*time.Second, stream.StreamPath, review.ReviewPath))
 }
 
 func (s *Server) setupRoutes() {
@@ -82,7 +93,10 @@ func (s *Server) setupRoutes() {
 		})
 	})
 
-	routes.InitHttpRoutes(s.router, s.database)
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	s.stopWorkers = stopWorkers
+
+	routes.InitHttpRoutes(workersCtx, s.router, s.database)
 }
 
 func (s *Server) healthCheck(c *gin.Context) {
@@ -109,6 +123,9 @@ func (s *Server) Start() error {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Stopping the workers closes open event streams, which would
+	// otherwise keep Shutdown waiting.
+	s.server.RegisterOnShutdown(s.stopWorkers)
 
 	go func() {
 		log.Printf("🚀 Server starting on port %s (env: %s)", s.config.HttpServer.Port, s.config.HttpServer.Env)
@@ -165,8 +182,15 @@ func securityHeaders() gin.HandlerFunc {
 	}
 }
 
-func timeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+// timeoutMiddleware bounds every request except the long-lived routes in
+// exempt.
+func timeoutMiddleware(timeout time.Duration, exempt ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if slices.Contains(exempt, c.FullPath()) {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
 
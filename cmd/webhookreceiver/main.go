@@ -0,0 +1,99 @@
+// Command webhookreceiver is a local endpoint for exercising webhooks. It
+// verifies each delivery's signature against WEBHOOK_SECRET, logs the
+// event and answers with WEBHOOK_RECEIVER_STATUS, which can be set to a
+// failing status to watch retries and dead-lettering.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"testcase/package/securities"
+)
+
+// maxTimestampSkew is how old a delivery may be before it is treated as a
+// replay.
+const maxTimestampSkew = 5 * time.Minute
+
+type receiver struct {
+	secret string
+	status int
+}
+
+func main() {
+	addr := getEnv("WEBHOOK_RECEIVER_ADDR", ":9100")
+	status, err := strconv.Atoi(getEnv("WEBHOOK_RECEIVER_STATUS", "200"))
+	if err != nil {
+		log.Fatalf("Invalid WEBHOOK_RECEIVER_STATUS: %v", err)
+	}
+
+	r := &receiver{
+		secret: os.Getenv("WEBHOOK_SECRET"),
+		status: status,
+	}
+	if r.secret == "" {
+		log.Println("⚠️  WEBHOOK_SECRET is not set, signatures will not be verified")
+	}
+
+	http.HandleFunc("/", r.receive)
+
+	log.Printf("📬 Webhook receiver listening on %s, answering %d", addr, status)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+func (r *receiver) receive(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	timestamp := req.Header.Get(securities.WebhookTimestampHeader)
+	if r.secret != "" {
+		if !fresh(timestamp) {
+			log.Printf("❌ Rejected delivery %s: stale timestamp %q", req.Header.Get(securities.WebhookDeliveryHeader), timestamp)
+			http.Error(w, "stale timestamp", http.StatusUnauthorized)
+			return
+		}
+		if !securities.VerifyWebhookSignature(r.secret, timestamp, body, req.Header.Get(securities.WebhookSignatureHeader)) {
+			log.Printf("❌ Rejected delivery %s: invalid signature", req.Header.Get(securities.WebhookDeliveryHeader))
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, body, "", "  "); err != nil {
+		pretty.Write(body)
+	}
+	log.Printf("📨 %s delivery %s\n%s", req.Header.Get(securities.WebhookEventHeader), req.Header.Get(securities.WebhookDeliveryHeader), pretty.String())
+
+	w.WriteHeader(r.status)
+}
+
+func fresh(timestamp string) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	skew := time.Since(time.Unix(seconds, 0))
+	return skew < maxTimestampSkew && skew > -maxTimestampSkew
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,11 +14,39 @@ type Config struct {
 	Auth
 	Database
 	HttpServer
+	EmailVerification
+	Mail
+	MFA
+	StepUp
+	Approval
+	Lockout
+	RequestSigning
+	OIDC
+	LDAP
+	Organization
+	PasswordPolicy
+	Branding
+	Settings
+	Outbox
+	Webhook
+	Notification
+	Stream
+	Review
 }
 
+// HttpServer configures the listener. TrustedProxies lists the proxy
+// addresses or CIDRs whose X-Forwarded-For is believed when resolving the
+// client IP; with none, the client is the connection's peer address.
 type HttpServer struct {
-	Port string
-	Env  string
+	Port           string
+	Env            string
+	TrustedProxies []string
+}
+
+// IsDevelopment reports whether the server runs as a single local
+// instance, where per-process state and built-in keys are acceptable.
+func (h HttpServer) IsDevelopment() bool {
+	return h.Env == "development"
 }
 
 type Database struct {
@@ -39,6 +68,307 @@ type Auth struct {
 	RefreshTokenSecret string
 	TokenExpiry        time.Duration
 	RefreshExpiry      time.Duration
+	SigningKeyFile     string
+	SigningKeyID       string
+	VerificationKeys   map[string]string
+	TokenIssuer        string
+	TokenAudience      []string
+	TokenLeeway        time.Duration
+	RevocationStore    string
+	PasswordBackend    string
+}
+
+const (
+	RevocationStoreMemory   = "memory"
+	RevocationStoreDatabase = "database"
+)
+
+func (a Auth) UsesDatabaseRevocation() bool {
+	return a.RevocationStore == RevocationStoreDatabase
+}
+
+const (
+	PasswordBackendLocal = "local"
+	PasswordBackendLDAP  = "ldap"
+)
+
+func (a Auth) UsesLDAPPasswords() bool {
+	return a.PasswordBackend == PasswordBackendLDAP
+}
+
+const (
+	EmailVerificationOff      = "off"
+	EmailVerificationLogin    = "login"
+	EmailVerificationApproval = "approval"
+)
+
+type EmailVerification struct {
+	Mode       string
+	LinkExpiry time.Duration
+	VerifyURL  string
+}
+
+func (e EmailVerification) BlocksLogin() bool {
+	return e.Mode == EmailVerificationLogin
+}
+
+func (e EmailVerification) BlocksApproval() bool {
+	return e.Mode == EmailVerificationLogin || e.Mode == EmailVerificationApproval
+}
+
+// Mail selects how mail leaves the application: "log" prints it, "file"
+// writes .eml files to FileDir and "smtp" sends it through the SMTP server.
+type Mail struct {
+	Driver       string
+	From         string
+	FileDir      string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPTLS      string
+	SMTPTimeout  time.Duration
+}
+
+type MFA struct {
+	Issuer            string
+	RequiredRoles     []string
+	ChallengeExpiry   time.Duration
+	RecoveryCodeCount int
+	EncryptionKey     string
+}
+
+// devMFAEncryptionKey is used in development when MFA_ENCRYPTION_KEY is
+// unset. Other environments refuse to start without a key.
+const devMFAEncryptionKey = "defaultmfaencryptionkey"
+
+func (m *MFA) UseDevelopmentKey() {
+	m.EncryptionKey = devMFAEncryptionKey
+}
+
+func (m MFA) RequiredForRole(role string) bool {
+	for _, r := range m.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type StepUp struct {
+	Expiry         time.Duration
+	ApprovalLevels []int
+}
+
+func (s StepUp) RequiredForLevel(level int) bool {
+	for _, l := range s.ApprovalLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// Approval holds the per-step approver rules keyed by step number, e.g.
+// "1=department_manager,3=role:admin3@FIN". Steps without a rule use the
+// role's approve permission. StepSLAHours is the time each step may take,
+// zero for no deadline.
+type Approval struct {
+	StepRules    map[string]string
+	StepSLAHours int
+}
+
+type Lockout struct {
+	AccountThreshold int
+	IPThreshold      int
+	Window           time.Duration
+	BaseDuration     time.Duration
+	MaxDuration      time.Duration
+}
+
+// DurationFor doubles the lockout for every failure past the threshold,
+// capped at MaxDuration.
+func (l Lockout) DurationFor(failures, threshold int) time.Duration {
+	if threshold <= 0 || failures < threshold {
+		return 0
+	}
+
+	duration := l.BaseDuration
+	for i := threshold; i < failures && duration < l.MaxDuration; i++ {
+		duration *= 2
+	}
+	if duration > l.MaxDuration {
+		duration = l.MaxDuration
+	}
+
+	return duration
+}
+
+type RequestSigning struct {
+	Window        time.Duration
+	EncryptionKey string
+}
+
+// devRequestSigningEncryptionKey is used in development when
+// REQUEST_SIGNING_ENCRYPTION_KEY is unset. Other environments refuse to
+// start without a key.
+const devRequestSigningEncryptionKey = "defaultsigningencryptionkey"
+
+func (r *RequestSigning) UseDevelopmentKey() {
+	r.EncryptionKey = devRequestSigningEncryptionKey
+}
+
+type OIDC struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	RoleMapping  map[string]string
+	DefaultRole  string
+	StateExpiry  time.Duration
+	// TrustedAMR lists authentication methods (amr claim values) that mean
+	// the provider already did MFA. Empty applies local MFA to every SSO
+	// login.
+	TrustedAMR []string
+}
+
+func (o OIDC) Enabled() bool {
+	return o.IssuerURL != "" && o.ClientID != ""
+}
+
+// TrustsMFA reports whether an ID token with these amr values stands in
+// for local MFA.
+func (o OIDC) TrustsMFA(amr []string) bool {
+	for _, method := range amr {
+		for _, trusted := range o.TrustedAMR {
+			if strings.EqualFold(method, trusted) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type LDAP struct {
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	CACertFile         string
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string
+	EmailAttribute     string
+	NameAttribute      string
+	UsernameAttribute  string
+	GroupAttribute     string
+	RoleMapping        map[string]string
+	DefaultRole        string
+	Timeout            time.Duration
+}
+
+// Encrypted reports whether binds travel over TLS, either an ldaps:// URL
+// or StartTLS. Passwords are sent in the clear otherwise.
+func (l LDAP) Encrypted() bool {
+	return l.StartTLS || strings.HasPrefix(strings.ToLower(l.URL), "ldaps://")
+}
+
+// Organization names the tenant that exists from the first start. It owns
+// data created before multi-tenancy, takes public sign-ups and SSO/LDAP
+// provisioned users, and its admins manage the platform.
+type Organization struct {
+	DefaultSlug string
+	DefaultName string
+}
+
+// PasswordPolicy is the default rule set for local passwords. Organizations
+// can tighten or relax it through their settings.
+type PasswordPolicy struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+}
+
+type Branding struct {
+	DisplayName  string
+	LogoURL      string
+	PrimaryColor string
+}
+
+// Settings controls how long organization settings are cached. Updates
+// are announced with NOTIFY so every instance drops its copy right away;
+// the TTL only bounds staleness when a notification is lost.
+type Settings struct {
+	CacheTTL time.Duration
+}
+
+// Outbox tunes the dispatcher that delivers domain events. Failed
+// deliveries are retried with a doubling delay until MaxAttempts is
+// reached; delivered events are kept for Retention (0 keeps them forever).
+// ClaimLease must cover delivering a whole batch, or other dispatchers
+// claim its tail again.
+type Outbox struct {
+	PollInterval time.Duration
+	BatchSize    int
+	ClaimLease   time.Duration
+	MaxAttempts  int
+	RetryDelay   time.Duration
+	Retention    time.Duration
+}
+
+// Webhook controls outbound webhook delivery. A delivery is retried with a
+// doubling delay, capped at MaxRetryDelay, and moved to the dead-letter
+// state after MaxAttempts. Targets on loopback and private networks are
+// refused unless AllowPrivateTargets is set.
+type Webhook struct {
+	Timeout             time.Duration
+	PollInterval        time.Duration
+	BatchSize           int
+	MaxAttempts         int
+	RetryDelay          time.Duration
+	MaxRetryDelay       time.Duration
+	AllowPrivateTargets bool
+	EncryptionKey       string
+}
+
+// devWebhookEncryptionKey is used in development when
+// WEBHOOK_ENCRYPTION_KEY is unset. Other environments refuse to start
+// without a key.
+const devWebhookEncryptionKey = "defaultwebhookencryptionkey"
+
+func (w *Webhook) UseDevelopmentKey() {
+	w.EncryptionKey = devWebhookEncryptionKey
+}
+
+// Notification configures approval emails. Users without a locale get
+// DefaultLocale; DocumentURL is the base of the document links in them.
+type Notification struct {
+	Enabled       bool
+	DefaultLocale string
+	DocumentURL   string
+}
+
+// Stream tunes the Server-Sent Events endpoint. A client that falls
+// BufferSize events behind is disconnected and resumes with Last-Event-ID;
+// a resume more than ReplayLimit events back is told to reload instead.
+type Stream struct {
+	HeartbeatInterval time.Duration
+	BufferSize        int
+	ReplayLimit       int
+}
+
+// Review tunes the document review WebSocket. Each instance re-announces
+// its viewers every PresenceInterval and forgets viewers of other
+// instances not heard from in three intervals; connections are pinged
+// every PingInterval and dropped when the pong doesn't arrive in time.
+type Review struct {
+	PresenceInterval time.Duration
+	PingInterval     time.Duration
+	BufferSize       int
 }
 
 func LoadConfig() *Config {
@@ -53,6 +383,14 @@ func LoadConfig() *Config {
 			RefreshTokenSecret: getEnv("REFRESH_TOKEN_SECRET", "defaultrefreshsecret"),
 			TokenExpiry:        getDurationEnv("TOKEN_EXPIRY", time.Minute*15),
 			RefreshExpiry:      getDurationEnv("REFRESH_EXPIRY", time.Hour*24*7),
+			SigningKeyFile:     getEnv("JWT_SIGNING_KEY_FILE", ""),
+			SigningKeyID:       getEnv("JWT_SIGNING_KEY_ID", ""),
+			VerificationKeys:   getMapEnv("JWT_VERIFICATION_KEYS"),
+			TokenIssuer:        getEnv("JWT_ISSUER", "pln-api"),
+			TokenAudience:      getSliceEnv("JWT_AUDIENCE", []string{"pln-api"}),
+			TokenLeeway:        getDurationEnv("JWT_LEEWAY", time.Second*30),
+			RevocationStore:    getEnv("TOKEN_REVOCATION_STORE", RevocationStoreDatabase),
+			PasswordBackend:    getEnv("AUTH_PASSWORD_BACKEND", PasswordBackendLocal),
 		},
 		Database: Database{
 			User:            getEnv("DB_USER", "postgres"),
@@ -68,8 +406,132 @@ func LoadConfig() *Config {
 			ConnMaxIdleTime: getDurationEnv("DB_CONN_MAX_IDLE_TIME", time.Minute*5),
 		},
 		HttpServer: HttpServer{
-			Port: getEnv("HTTP_PORT", "8080"),
-			Env:  getEnv("HTTP_ENV", "development"),
+			Port:           getEnv("HTTP_PORT", "8080"),
+			Env:            getEnv("HTTP_ENV", "development"),
+			TrustedProxies: getSliceEnv("HTTP_TRUSTED_PROXIES", nil),
+		},
+		EmailVerification: EmailVerification{
+			Mode:       getEnv("EMAIL_VERIFICATION_MODE", EmailVerificationOff),
+			LinkExpiry: getDurationEnv("EMAIL_VERIFICATION_EXPIRY", time.Hour*24),
+			VerifyURL:  getEnv("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email"),
+		},
+		Mail: Mail{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "no-reply@testcase.local"),
+			FileDir:      getEnv("MAIL_FILE_DIR", "tmp/mail"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getIntEnv("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			SMTPTLS:      getEnv("SMTP_TLS", "starttls"),
+			SMTPTimeout:  getDurationEnv("SMTP_TIMEOUT", time.Second*15),
+		},
+		MFA: MFA{
+			Issuer:            getEnv("MFA_ISSUER", "Testcase"),
+			RequiredRoles:     getSliceEnv("MFA_REQUIRED_ROLES", []string{}),
+			ChallengeExpiry:   getDurationEnv("MFA_CHALLENGE_EXPIRY", time.Minute*5),
+			RecoveryCodeCount: getIntEnv("MFA_RECOVERY_CODE_COUNT", 10),
+			EncryptionKey:     getEnv("MFA_ENCRYPTION_KEY", ""),
+		},
+		StepUp: StepUp{
+			Expiry:         getDurationEnv("STEP_UP_EXPIRY", time.Minute*5),
+			ApprovalLevels: getIntSliceEnv("STEP_UP_APPROVAL_LEVELS", []int{}),
+		},
+		Approval: Approval{
+			StepRules:    getMapEnv("APPROVAL_STEP_RULES"),
+			StepSLAHours: getIntEnv("APPROVAL_STEP_SLA_HOURS", 0),
+		},
+		Lockout: Lockout{
+			AccountThreshold: getIntEnv("LOCKOUT_ACCOUNT_THRESHOLD", 5),
+			IPThreshold:      getIntEnv("LOCKOUT_IP_THRESHOLD", 20),
+			Window:           getDurationEnv("LOCKOUT_WINDOW", time.Minute*15),
+			BaseDuration:     getDurationEnv("LOCKOUT_BASE_DURATION", time.Minute),
+			MaxDuration:      getDurationEnv("LOCKOUT_MAX_DURATION", time.Hour),
+		},
+		RequestSigning: RequestSigning{
+			Window:        getDurationEnv("REQUEST_SIGNING_WINDOW", time.Minute*5),
+			EncryptionKey: getEnv("REQUEST_SIGNING_ENCRYPTION_KEY", ""),
+		},
+		OIDC: OIDC{
+			IssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:3000/sso/callback"),
+			Scopes:       getSliceEnv("OIDC_SCOPES", []string{"openid", "email", "profile"}),
+			GroupsClaim:  getEnv("OIDC_GROUPS_CLAIM", "groups"),
+			RoleMapping:  getMapEnv("OIDC_ROLE_MAPPING"),
+			DefaultRole:  getEnv("OIDC_DEFAULT_ROLE", "user"),
+			StateExpiry:  getDurationEnv("OIDC_STATE_EXPIRY", time.Minute*10),
+			TrustedAMR:   getSliceEnv("OIDC_TRUSTED_AMR", nil),
+		},
+		LDAP: LDAP{
+			URL:                getEnv("LDAP_URL", "ldap://localhost:389"),
+			StartTLS:           getBoolEnv("LDAP_START_TLS", false),
+			InsecureSkipVerify: getBoolEnv("LDAP_INSECURE_SKIP_VERIFY", false),
+			CACertFile:         getEnv("LDAP_CA_CERT_FILE", ""),
+			BindDN:             getEnv("LDAP_BIND_DN", ""),
+			BindPassword:       getEnv("LDAP_BIND_PASSWORD", ""),
+			BaseDN:             getEnv("LDAP_BASE_DN", ""),
+			UserFilter:         getEnv("LDAP_USER_FILTER", "(&(objectClass=person)(mail={login}))"),
+			EmailAttribute:     getEnv("LDAP_EMAIL_ATTRIBUTE", "mail"),
+			NameAttribute:      getEnv("LDAP_NAME_ATTRIBUTE", "cn"),
+			UsernameAttribute:  getEnv("LDAP_USERNAME_ATTRIBUTE", "uid"),
+			GroupAttribute:     getEnv("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+			RoleMapping:        getDNMapEnv("LDAP_ROLE_MAPPING"),
+			DefaultRole:        getEnv("LDAP_DEFAULT_ROLE", "user"),
+			Timeout:            getDurationEnv("LDAP_TIMEOUT", time.Second*10),
+		},
+		Organization: Organization{
+			DefaultSlug: getEnv("DEFAULT_ORGANIZATION_SLUG", "default"),
+			DefaultName: getEnv("DEFAULT_ORGANIZATION_NAME", "Default Organization"),
+		},
+		PasswordPolicy: PasswordPolicy{
+			MinLength:        getIntEnv("PASSWORD_MIN_LENGTH", 6),
+			RequireUppercase: getBoolEnv("PASSWORD_REQUIRE_UPPERCASE", false),
+			RequireLowercase: getBoolEnv("PASSWORD_REQUIRE_LOWERCASE", false),
+			RequireDigit:     getBoolEnv("PASSWORD_REQUIRE_DIGIT", false),
+			RequireSymbol:    getBoolEnv("PASSWORD_REQUIRE_SYMBOL", false),
+		},
+		Branding: Branding{
+			DisplayName:  getEnv("BRAND_DISPLAY_NAME", "Testcase"),
+			LogoURL:      getEnv("BRAND_LOGO_URL", ""),
+			PrimaryColor: getEnv("BRAND_PRIMARY_COLOR", ""),
+		},
+		Settings: Settings{
+			CacheTTL: getDurationEnv("SETTINGS_CACHE_TTL", time.Minute),
+		},
+		Outbox: Outbox{
+			PollInterval: getDurationEnv("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:    getIntEnv("OUTBOX_BATCH_SIZE", 100),
+			ClaimLease:   getDurationEnv("OUTBOX_CLAIM_LEASE", time.Minute*5),
+			MaxAttempts:  getIntEnv("OUTBOX_MAX_ATTEMPTS", 10),
+			RetryDelay:   getDurationEnv("OUTBOX_RETRY_DELAY", time.Second*5),
+			Retention:    getDurationEnv("OUTBOX_RETENTION", time.Hour*24*7),
+		},
+		Webhook: Webhook{
+			Timeout:             getDurationEnv("WEBHOOK_TIMEOUT", time.Second*10),
+			PollInterval:        getDurationEnv("WEBHOOK_POLL_INTERVAL", time.Second*2),
+			BatchSize:           getIntEnv("WEBHOOK_BATCH_SIZE", 20),
+			MaxAttempts:         getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryDelay:          getDurationEnv("WEBHOOK_RETRY_DELAY", time.Second*30),
+			MaxRetryDelay:       getDurationEnv("WEBHOOK_MAX_RETRY_DELAY", time.Hour*6),
+			AllowPrivateTargets: getBoolEnv("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+			EncryptionKey:       getEnv("WEBHOOK_ENCRYPTION_KEY", ""),
+		},
+		Notification: Notification{
+			Enabled:       getBoolEnv("NOTIFICATION_EMAILS_ENABLED", true),
+			DefaultLocale: getEnv("NOTIFICATION_DEFAULT_LOCALE", "en"),
+			DocumentURL:   getEnv("NOTIFICATION_DOCUMENT_URL", "http://localhost:3000/documents"),
+		},
+		Stream: Stream{
+			HeartbeatInterval: getDurationEnv("STREAM_HEARTBEAT_INTERVAL", time.Second*15),
+			BufferSize:        getIntEnv("STREAM_BUFFER_SIZE", 64),
+			ReplayLimit:       getIntEnv("STREAM_REPLAY_LIMIT", 500),
+		},
+		Review: Review{
+			PresenceInterval: getDurationEnv("REVIEW_PRESENCE_INTERVAL", time.Second*30),
+			PingInterval:     getDurationEnv("REVIEW_PING_INTERVAL", time.Second*30),
+			BufferSize:       getIntEnv("REVIEW_BUFFER_SIZE", 32),
 		},
 	}
 }
@@ -96,6 +558,21 @@ func getIntEnv(key string, defaultValue int) int {
 	return intValue
 }
 
+func getBoolEnv(key string, defaultValue bool) bool {
+	strValue := os.Getenv(key)
+	if strValue == "" {
+		return defaultValue
+	}
+
+	boolValue, err := strconv.ParseBool(strValue)
+	if err != nil {
+		log.Printf("Warning: Invalid boolean value for %s, using default: %t", key, defaultValue)
+		return defaultValue
+	}
+
+	return boolValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	strValue := os.Getenv(key)
 	if strValue == "" {
@@ -110,3 +587,72 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 
 	return durationValue
 }
+
+func getSliceEnv(key string, defaultValue []string) []string {
+	strValue := os.Getenv(key)
+	if strValue == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, part := range strings.Split(strValue, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+
+	return values
+}
+
+func getIntSliceEnv(key string, defaultValue []int) []int {
+	parts := getSliceEnv(key, nil)
+	if parts == nil {
+		return defaultValue
+	}
+
+	values := make([]int, 0, len(parts))
+	for _, part := range parts {
+		intValue, err := strconv.Atoi(part)
+		if err != nil {
+			log.Printf("Warning: Invalid integer list for %s, using default: %v", key, defaultValue)
+			return defaultValue
+		}
+		values = append(values, intValue)
+	}
+
+	return values
+}
+
+// getDNMapEnv parses "dn=value" pairs separated by semicolons, since DNs
+// contain commas. Each pair is split at its last "=".
+func getDNMapEnv(key string) map[string]string {
+	values := make(map[string]string)
+	for _, part := range strings.Split(os.Getenv(key), ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		separator := strings.LastIndex(part, "=")
+		if separator < 0 {
+			log.Printf("Warning: Ignoring malformed entry %q in %s", part, key)
+			continue
+		}
+		values[strings.TrimSpace(part[:separator])] = strings.TrimSpace(part[separator+1:])
+	}
+
+	return values
+}
+
+// getMapEnv parses "key=value" pairs separated by commas.
+func getMapEnv(key string) map[string]string {
+	values := make(map[string]string)
+	for _, part := range getSliceEnv(key, nil) {
+		k, v, found := strings.Cut(part, "=")
+		if !found {
+			log.Printf("Warning: Ignoring malformed entry %q in %s", part, key)
+			continue
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return values
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutDurationFor(t *testing.T) {
+	lockout := Lockout{BaseDuration: time.Minute, MaxDuration: 10 * time.Minute}
+
+	cases := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 4, want: 0},
+		{failures: 5, want: time.Minute},
+		{failures: 6, want: 2 * time.Minute},
+		{failures: 8, want: 8 * time.Minute},
+		{failures: 9, want: 10 * time.Minute},
+		{failures: 50, want: 10 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := lockout.DurationFor(c.failures, 5); got != c.want {
+			t.Errorf("DurationFor(%d, 5) = %s, want %s", c.failures, got, c.want)
+		}
+	}
+
+	if got := lockout.DurationFor(100, 0); got != 0 {
+		t.Errorf("expected a zero threshold to disable lockouts, got %s", got)
+	}
+}
+
+func TestOIDCTrustsMFA(t *testing.T) {
+	if (OIDC{}).TrustsMFA([]string{"mfa"}) {
+		t.Error("expected no amr value to be trusted by default")
+	}
+
+	oidc := OIDC{TrustedAMR: []string{"mfa", "hwk"}}
+	if !oidc.TrustsMFA([]string{"pwd", "HWK"}) {
+		t.Error("expected a trusted method to be matched case-insensitively")
+	}
+	if oidc.TrustsMFA([]string{"pwd"}) || oidc.TrustsMFA(nil) {
+		t.Error("expected a password-only login not to be trusted")
+	}
+}
+
+func TestLDAPEncrypted(t *testing.T) {
+	cases := []struct {
+		ldap LDAP
+		want bool
+	}{
+		{ldap: LDAP{URL: "ldap://localhost:389"}, want: false},
+		{ldap: LDAP{URL: "ldap://localhost:389", StartTLS: true}, want: true},
+		{ldap: LDAP{URL: "LDAPS://ldap.example.com"}, want: true},
+	}
+	for _, c := range cases {
+		if got := c.ldap.Encrypted(); got != c.want {
+			t.Errorf("%+v: Encrypted() = %v, want %v", c.ldap, got, c.want)
+		}
+	}
+}
@@ -38,12 +38,12 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 		}
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	database, err := Open(postgres.Open(dsn), gormConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
 	}
 
-	sqlDB, err := db.DB()
+	sqlDB, err := database.DB.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
@@ -59,6 +59,21 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 
 	log.Println("✅ Database connected successfully")
 
+	return database, nil
+}
+
+// Open wraps dialector with the tenant scope registered. NewDatabase uses
+// it for postgres; tests pass a dialector over a mock connection.
+func Open(dialector gorm.Dialector, gormConfig *gorm.Config) (*Database, error) {
+	db, err := gorm.Open(dialector, gormConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := registerTenantScope(db); err != nil {
+		return nil, fmt.Errorf("failed to register tenant scope: %w", err)
+	}
+
 	return &Database{DB: db}, nil
 }
 
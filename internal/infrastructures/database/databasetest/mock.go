@@ -0,0 +1,42 @@
+// Package databasetest opens a Database over sqlmock, so repositories can
+// be tested against the SQL they send without a running postgres.
+package databasetest
+
+import (
+	"testing"
+
+	"testcase/internal/infrastructures/database"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewMock returns a Database with the tenant scope registered and the mock
+// behind it. Unmet expectations fail the test when it finishes.
+func NewMock(t *testing.T) (*database.Database, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+
+	db, err := database.Open(
+		postgres.New(postgres.Config{Conn: conn}),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)},
+	)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		conn.Close()
+	})
+
+	return db, mock
+}
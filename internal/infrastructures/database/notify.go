@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Notify sends payload to every connection listening on channel, on this
+// instance and any other sharing the database.
+func (d *Database) Notify(ctx context.Context, channel, payload string) error {
+	if err := d.DB.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", channel, payload).Error; err != nil {
+		return fmt.Errorf("failed to notify %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Listen holds a connection listening on channel and calls handle with
+// each payload until ctx is cancelled or the connection fails. handle runs
+// on the listening goroutine, so notifications arrive in order.
+func (d *Database) Listen(ctx context.Context, channel string, handle func(payload string)) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a listen connection: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+		// Close the connection on the way out rather than handing a
+		// listening connection back to the pool.
+		defer pgxConn.Close(context.Background())
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", channel, err)
+		}
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			handle(notification.Payload)
+		}
+	})
+}
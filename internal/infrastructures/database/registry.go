@@ -1,12 +1,41 @@
 package database
 
 import (
+	"fmt"
 	"log"
 
+	auditEntities "testcase/internal/modules/audit/entities"
+	commentEntities "testcase/internal/modules/comment/entities"
+	departmentEntities "testcase/internal/modules/department/entities"
 	documentEntities "testcase/internal/modules/document/entities"
+	notificationEntities "testcase/internal/modules/notification/entities"
+	organizationEntities "testcase/internal/modules/organization/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	roleEntities "testcase/internal/modules/role/entities"
+	settingEntities "testcase/internal/modules/setting/entities"
 	userEntities "testcase/internal/modules/user/entities"
+	webhookEntities "testcase/internal/modules/webhook/entities"
 )
 
+// backfill fills a column for the rows that exist when the column is
+// added. It runs once, in the migration that creates the column, so rows
+// written afterwards keep their own value.
+type backfill struct {
+	entity    interface{}
+	column    string
+	statement string
+}
+
+// backfills mark users created before email verification as verified, so
+// EMAIL_VERIFICATION_MODE=login doesn't lock every existing account out.
+var backfills = []backfill{
+	{
+		entity:    &userEntities.User{},
+		column:    "email_verified_at",
+		statement: "UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL",
+	},
+}
+
 type EntityRegistry struct {
 	entities []interface{}
 }
@@ -18,8 +47,32 @@ func NewEntityRegistry() *EntityRegistry {
 }
 
 func (er *EntityRegistry) RegisterEntities() {
+	er.addEntity(&organizationEntities.Organization{})
+	er.addEntity(&settingEntities.TenantSetting{})
+	er.addEntity(&roleEntities.Role{})
 	er.addEntity(&userEntities.User{})
+	er.addEntity(&userEntities.EmailVerificationToken{})
+	er.addEntity(&userEntities.MFARecoveryCode{})
+	er.addEntity(&userEntities.LoginLockout{})
+	er.addEntity(&userEntities.Session{})
+	er.addEntity(&userEntities.RevokedToken{})
+	er.addEntity(&userEntities.RequestNonce{})
+	er.addEntity(&userEntities.APIKey{})
+	er.addEntity(&userEntities.ExternalIdentity{})
+	er.addEntity(&userEntities.OIDCLoginState{})
+	er.addEntity(&departmentEntities.Department{})
+	er.addEntity(&departmentEntities.DepartmentMember{})
 	er.addEntity(&documentEntities.Document{})
+	er.addEntity(&outboxEntities.OutboxEvent{})
+	er.addEntity(&webhookEntities.Webhook{})
+	er.addEntity(&webhookEntities.WebhookDelivery{})
+	er.addEntity(&webhookEntities.WebhookDeliveryAttempt{})
+	er.addEntity(&notificationEntities.EmailNotification{})
+	er.addEntity(&notificationEntities.Notification{})
+	er.addEntity(&notificationEntities.NotificationPreference{})
+	er.addEntity(&commentEntities.Comment{})
+	er.addEntity(&commentEntities.CommentHistory{})
+	er.addEntity(&auditEntities.AuditLog{})
 }
 
 func (er *EntityRegistry) addEntity(entity interface{}) {
@@ -43,10 +96,24 @@ func (er *EntityRegistry) RunMigrations(db *Database) error {
 
 	log.Printf("🔄 Starting migration for %d entities...", len(er.entities))
 
+	var pending []backfill
+	for _, b := range backfills {
+		if !db.Migrator().HasColumn(b.entity, b.column) {
+			pending = append(pending, b)
+		}
+	}
+
 	if err := db.AutoMigrate(er.entities...); err != nil {
 		return err
 	}
 
+	for _, b := range pending {
+		if err := db.Exec(b.statement).Error; err != nil {
+			return fmt.Errorf("failed to backfill %s: %w", b.column, err)
+		}
+		log.Printf("✅ Backfilled %s", b.column)
+	}
+
 	log.Printf("✅ Successfully migrated %d entities", len(er.entities))
 	return nil
 }
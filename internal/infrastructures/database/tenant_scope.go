@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"testcase/internal/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// tenantField is the struct field that marks a model as tenant owned.
+const tenantField = "TenantID"
+
+var (
+	ErrTenantRequired = errors.New("tenant is missing from the query context")
+	ErrTenantMismatch = errors.New("record belongs to another tenant")
+)
+
+// TenantFromContext returns the tenant database access made with ctx is
+// scoped to.
+func TenantFromContext(ctx context.Context) (uuid.UUID, bool) {
+	tenantID, ok := ctx.Value(utils.TenantIDContextKey).(uuid.UUID)
+	return tenantID, ok && tenantID != uuid.Nil
+}
+
+func crossTenant(ctx context.Context) bool {
+	allowed, _ := ctx.Value(utils.CrossTenantContextKey).(bool)
+	return allowed
+}
+
+// registerTenantScope makes every query, update and delete on a model with
+// a TenantID field filter by the tenant in the statement context, and fills
+// the field on create. A statement without a tenant fails unless the
+// context was marked with utils.WithoutTenantScope. Raw SQL is not
+// rewritten and must filter by tenant itself.
+func registerTenantScope(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeToTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeToTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeToTenant); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:before_create").Register("tenant:create", assignTenant)
+}
+
+func tenantOwned(db *gorm.DB) (*schema.Field, bool) {
+	if db.Statement.Schema == nil {
+		return nil, false
+	}
+	field := db.Statement.Schema.LookUpField(tenantField)
+	return field, field != nil
+}
+
+func scopeToTenant(db *gorm.DB) {
+	field, ok := tenantOwned(db)
+	if !ok || db.Statement.SQL.Len() > 0 {
+		return
+	}
+
+	ctx := db.Statement.Context
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		if !crossTenant(ctx) {
+			_ = db.AddError(fmt.Errorf("%w: %s", ErrTenantRequired, db.Statement.Table))
+		}
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantID},
+	}})
+}
+
+func assignTenant(db *gorm.DB) {
+	field, ok := tenantOwned(db)
+	if !ok {
+		return
+	}
+
+	ctx := db.Statement.Context
+	tenantID, scoped := TenantFromContext(ctx)
+	if !scoped && !crossTenant(ctx) {
+		_ = db.AddError(fmt.Errorf("%w: %s", ErrTenantRequired, db.Statement.Table))
+		return
+	}
+
+	assign := func(value reflect.Value) {
+		current, zero := field.ValueOf(ctx, value)
+		switch {
+		case !zero && scoped && current != tenantID:
+			_ = db.AddError(fmt.Errorf("%w: %s", ErrTenantMismatch, db.Statement.Table))
+		case zero && scoped:
+			_ = db.AddError(field.Set(ctx, value, tenantID))
+		case zero:
+			_ = db.AddError(fmt.Errorf("%w: %s", ErrTenantRequired, db.Statement.Table))
+		}
+	}
+
+	switch db.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+			assign(reflect.Indirect(db.Statement.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		assign(db.Statement.ReflectValue)
+	}
+}
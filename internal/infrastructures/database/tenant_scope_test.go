@@ -0,0 +1,130 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/infrastructures/database/databasetest"
+	"testcase/internal/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+type note struct {
+	ID       uuid.UUID
+	TenantID uuid.UUID
+	Body     string
+}
+
+type country struct {
+	Code string `gorm:"primaryKey"`
+	Name string
+}
+
+func TestTenantScopeFiltersQueries(t *testing.T) {
+	db, mock := databasetest.NewMock(t)
+	tenantID := uuid.New()
+	ctx := utils.WithTenant(context.Background(), tenantID)
+
+	mock.ExpectQuery(`SELECT \* FROM "notes" WHERE body = \$1 AND "notes"."tenant_id" = \$2`).
+		WithArgs("hello", tenantID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "body"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "notes" SET "body"=\$1 WHERE id = \$2 AND "notes"."tenant_id" = \$3`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "notes" WHERE id = \$1 AND "notes"."tenant_id" = \$2`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var notes []note
+	if err := db.WithContext(ctx).Where("body = ?", "hello").Find(&notes).Error; err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.New()
+	if err := db.WithContext(ctx).Model(&note{}).Where("id = ?", id).Update("body", "bye").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.WithContext(ctx).Where("id = ?", id).Delete(&note{}).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTenantScopeRequiresTenant(t *testing.T) {
+	db, _ := databasetest.NewMock(t)
+	ctx := context.Background()
+
+	var notes []note
+	if err := db.WithContext(ctx).Find(&notes).Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Errorf("query: expected ErrTenantRequired, got %v", err)
+	}
+	if err := db.WithContext(ctx).Model(&note{}).Where("id = ?", uuid.New()).Update("body", "bye").Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Errorf("update: expected ErrTenantRequired, got %v", err)
+	}
+	if err := db.WithContext(ctx).Where("id = ?", uuid.New()).Delete(&note{}).Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Errorf("delete: expected ErrTenantRequired, got %v", err)
+	}
+	if err := db.WithContext(ctx).Create(&note{ID: uuid.New()}).Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Errorf("create: expected ErrTenantRequired, got %v", err)
+	}
+}
+
+func TestTenantScopeAllowsCrossTenantContext(t *testing.T) {
+	db, mock := databasetest.NewMock(t)
+	ctx := utils.WithoutTenantScope(context.Background())
+
+	mock.ExpectQuery(`SELECT \* FROM "notes" WHERE body = \$1$`).
+		WithArgs("hello").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "body"}))
+
+	var notes []note
+	if err := db.WithContext(ctx).Where("body = ?", "hello").Find(&notes).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.WithContext(ctx).Create(&note{ID: uuid.New()}).Error; !errors.Is(err, database.ErrTenantRequired) {
+		t.Errorf("expected a cross-tenant create without a tenant to fail, got %v", err)
+	}
+}
+
+func TestTenantScopeAssignsTenantOnCreate(t *testing.T) {
+	db, mock := databasetest.NewMock(t)
+	tenantID := uuid.New()
+	ctx := utils.WithTenant(context.Background(), tenantID)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "notes"`).
+		WithArgs(sqlmock.AnyArg(), tenantID, "hello").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	created := &note{ID: uuid.New(), Body: "hello"}
+	if err := db.WithContext(ctx).Create(created).Error; err != nil {
+		t.Fatal(err)
+	}
+	if created.TenantID != tenantID {
+		t.Fatalf("expected the tenant to be assigned, got %s", created.TenantID)
+	}
+
+	foreign := []note{{ID: uuid.New()}, {ID: uuid.New(), TenantID: uuid.New()}}
+	if err := db.WithContext(ctx).Create(&foreign).Error; !errors.Is(err, database.ErrTenantMismatch) {
+		t.Fatalf("expected ErrTenantMismatch, got %v", err)
+	}
+}
+
+func TestTenantScopeIgnoresSharedModels(t *testing.T) {
+	db, mock := databasetest.NewMock(t)
+
+	mock.ExpectQuery(`SELECT \* FROM "countries"$`).
+		WillReturnRows(sqlmock.NewRows([]string{"code", "name"}))
+
+	var countries []country
+	if err := db.WithContext(context.Background()).Find(&countries).Error; err != nil {
+		t.Fatal(err)
+	}
+}
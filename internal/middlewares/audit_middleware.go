@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	auditEntities "testcase/internal/modules/audit/entities"
+	auditServices "testcase/internal/modules/audit/services"
+	roleEntities "testcase/internal/modules/role/entities"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requiredPermissionsKey holds the permissions RequirePermission checked.
+const requiredPermissionsKey = "required_permissions"
+
+// adminPermissions mark the routes whose changes are admin operations.
+var adminPermissions = []string{
+	roleEntities.PermissionUserManage,
+	roleEntities.PermissionAPIKeyManage,
+	roleEntities.PermissionRoleManage,
+	roleEntities.PermissionDepartmentManage,
+	roleEntities.PermissionOrganizationManage,
+	roleEntities.PermissionSettingsManage,
+	roleEntities.PermissionWebhookManage,
+}
+
+type AuditMiddleware struct {
+	audit auditServices.AuditService
+}
+
+func NewAuditMiddleware(audit auditServices.AuditService) *AuditMiddleware {
+	return &AuditMiddleware{
+		audit: audit,
+	}
+}
+
+// AdminOperations records every change attempted on a route guarded by an
+// admin permission, including the ones refused or failed, once the request
+// is done. Reads are not recorded.
+func (am *AuditMiddleware) AdminOperations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Handlers report errors by panicking, so the entry is written on
+		// the way out, before ErrorHandlerMiddleware turns the panic into
+		// the response.
+		defer func() {
+			rec := recover()
+			am.recordAdminOperation(c, panicStatus(c, rec))
+			if rec != nil {
+				panic(rec)
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+func (am *AuditMiddleware) recordAdminOperation(c *gin.Context, status int) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+	required, _ := c.Get(requiredPermissionsKey)
+	permissions, _ := required.([]string)
+	index := slices.IndexFunc(permissions, func(p string) bool { return slices.Contains(adminPermissions, p) })
+	if index < 0 {
+		return
+	}
+
+	// The permission names the resource, such as user or webhook; the
+	// route itself can be longer than the target column allows.
+	resource, _, _ := strings.Cut(permissions[index], ".")
+	entry := &auditEntities.AuditLog{
+		Action:     auditEntities.ActionAdminOperation,
+		Outcome:    auditEntities.OutcomeSuccess,
+		TargetType: resource,
+		TargetID:   c.Param("id"),
+		Metadata: map[string]string{
+			"method": c.Request.Method,
+			"route":  c.FullPath(),
+			"status": strconv.Itoa(status),
+		},
+	}
+	if status >= http.StatusBadRequest {
+		entry.Outcome = auditEntities.OutcomeFailure
+	}
+
+	// The request's own deadline may have passed by now. Record counts and
+	// reports a failed append itself.
+	ctx := context.WithoutCancel(c.Request.Context())
+	_ = am.audit.Record(ctx, entry)
+}
+
+// panicStatus is the status ErrorHandlerMiddleware answers rec with, or
+// the written status when the handler returned normally.
+func panicStatus(c *gin.Context, rec any) int {
+	switch e := rec.(type) {
+	case nil:
+		return c.Writer.Status()
+	case *utils.AppError:
+		return e.ErrorCode.HttpStatus
+	case error:
+		return utils.ErrFetchDataError.HttpStatus
+	default:
+		return utils.ErrInternalServer.HttpStatus
+	}
+}
@@ -2,26 +2,58 @@ package middlewares
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"strings"
+	"testcase/config"
+	"testcase/internal/modules/user/entities"
 	"testcase/internal/utils"
 	"testcase/package/securities"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 )
 
 type AuthMiddleware struct {
-	jwtManager *securities.JWTManager
+	jwtManager  *securities.JWTManager
+	revocations *securities.RevocationList
+	services    securities.ServiceAuthenticator
+	nonces      securities.NonceCache
+	permissions securities.PermissionChecker
+	platformID  uuid.UUID
+	config      *config.Config
 }
 
-func NewAuthMiddleware(jwtManager *securities.JWTManager) *AuthMiddleware {
+// NewAuthMiddleware takes the ID of the platform organization, whose admins
+// may manage settings shared by every tenant.
+func NewAuthMiddleware(jwtManager *securities.JWTManager, revocations *securities.RevocationList, services securities.ServiceAuthenticator, nonces securities.NonceCache, permissions securities.PermissionChecker, platformID uuid.UUID, cfg *config.Config) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtManager: jwtManager,
+		jwtManager:  jwtManager,
+		revocations: revocations,
+		services:    services,
+		nonces:      nonces,
+		permissions: permissions,
+		platformID:  platformID,
+		config:      cfg,
 	}
 }
 
-func (am *AuthMiddleware) Auth() gin.HandlerFunc {
+// Auth accepts a Bearer access token or, when scopes are given, a service
+// credential holding all of them: an X-API-Key or an HMAC-signed request.
+// Routes without scopes are closed to service credentials so integrations
+// only reach endpoints that opted in.
+func (am *AuthMiddleware) Auth(scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if c.GetHeader(securities.SignatureHeader) != "" {
+			am.authSignedRequest(c, scopes)
+			return
+		}
+		if rawKey := c.GetHeader(securities.APIKeyHeader); rawKey != "" {
+			am.authAPIKey(c, rawKey, scopes)
+			return
+		}
+
 		token := am.extractToken(c)
 		if token == "" {
 			utils.ErrorResponse(c, utils.ErrUnauthorized, "Authorization token required")
@@ -29,22 +61,36 @@ func (am *AuthMiddleware) Auth() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := am.jwtManager.ValidateAndExtract(token, "access")
+		claims, err := am.jwtManager.ValidateAndExtract(token, securities.TokenTypeAccess)
 		if err != nil {
-			utils.ErrorResponse(c, utils.ErrUnauthorized, "Invalid or expired token")
+			tokenErrorResponse(c, err)
 			c.Abort()
 			return
 		}
 
-		c.Set(utils.UserIDContextKey, claims.UserID)
-		c.Set(utils.UsernameContextKey, claims.Username)
-		c.Set(utils.RoleContextKey, claims.Role)
-		ctx := c.Request.Context()
-		ctx = context.WithValue(ctx, utils.UserIDContextKey, claims.UserID)
-		ctx = context.WithValue(ctx, utils.UsernameContextKey, claims.Username)
-		ctx = context.WithValue(ctx, utils.RoleContextKey, string(claims.Role))
+		revoked, err := am.revocations.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			utils.ErrorResponse(c, utils.ErrInternalServer, err)
+			c.Abort()
+			return
+		}
+		if revoked {
+			utils.ErrorResponse(c, utils.ErrTokenRevoked, "Token has been revoked")
+			c.Abort()
+			return
+		}
 
-		c.Request = c.Request.WithContext(ctx)
+		setAuthContext(c, claims)
+		setAccessCheck(c, func(ctx context.Context) error {
+			revoked, err := am.revocations.IsRevoked(ctx, claims)
+			if err != nil {
+				return err
+			}
+			if revoked {
+				return utils.NewAppErrorWithMessage(utils.ErrTokenRevoked, errors.New("token revoked"), "Token has been revoked")
+			}
+			return nil
+		})
 
 		c.Next()
 	}
@@ -53,66 +99,231 @@ func (am *AuthMiddleware) Auth() gin.HandlerFunc {
 func (am *AuthMiddleware) AuthRefresh() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := am.extractToken(c)
-		fmt.Println("Refresh Token:", token)
 		if token == "" {
 			utils.ErrorResponse(c, utils.ErrUnauthorized, "Refresh token required")
 			c.Abort()
 			return
 		}
 
-		claims, err := am.jwtManager.ValidateAndExtract(token, "refresh")
+		claims, err := am.jwtManager.ValidateAndExtract(token, securities.TokenTypeRefresh)
 		if err != nil {
-			utils.ErrorResponse(c, utils.ErrUnauthorized, "Invalid or expired refresh token")
+			tokenErrorResponse(c, err)
 			c.Abort()
 			return
 		}
 
-		fmt.Println("Refresh Token Claims:", claims.Username)
+		setAuthContext(c, claims)
+		c.Next()
+	}
+}
+
+func (am *AuthMiddleware) AuthMFA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := am.extractToken(c)
+		if token == "" {
+			utils.ErrorResponse(c, utils.ErrUnauthorized, "MFA challenge token required")
+			c.Abort()
+			return
+		}
+
+		claims, err := am.jwtManager.ValidateAndExtract(token, securities.TokenTypeMFA)
+		if err != nil {
+			tokenErrorResponse(c, err)
+			c.Abort()
+			return
+		}
 
-		c.Set(utils.UserIDContextKey, claims.UserID)
-		c.Set(utils.UsernameContextKey, claims.Username)
-		c.Set(utils.RoleContextKey, claims.Role)
-		ctx := c.Request.Context()
-		ctx = context.WithValue(ctx, utils.UserIDContextKey, claims.UserID)
-		ctx = context.WithValue(ctx, utils.UsernameContextKey, claims.Username)
-		ctx = context.WithValue(ctx, utils.RoleContextKey, string(claims.Role))
-		c.Request = c.Request.WithContext(ctx)
+		setAuthContext(c, claims)
 		c.Next()
 	}
 }
 
-func (am *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
+// RequirePermission allows the request when the caller's role grants every
+// given permission. The permissions are kept on the request for the audit
+// middleware, whether or not they are granted.
+func (am *AuthMiddleware) RequirePermission(permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole, exists := c.Get("role")
+		c.Set(requiredPermissionsKey, permissions)
+
+		userRole, exists := c.Get(utils.RoleContextKey)
 		if !exists {
 			utils.ErrorResponse(c, utils.ErrUnauthorized, "User role not found in context")
 			c.Abort()
 			return
 		}
 
-		role, ok := userRole.(string)
+		role, ok := userRole.(entities.RoleEnum)
 		if !ok {
 			utils.ErrorResponse(c, utils.ErrUnauthorized, "Invalid role type")
 			c.Abort()
 			return
 		}
 
-		for _, requiredRole := range roles {
-			if role == requiredRole {
-				c.Next()
-				return
-			}
+		allowed, err := am.permissions.RoleHasPermissions(c.Request.Context(), string(role), permissions...)
+		if err != nil {
+			utils.ErrorResponse(c, utils.ErrInternalServer, err)
+			c.Abort()
+			return
+		}
+		if !allowed {
+			utils.ErrorResponse(c, utils.ErrForbiddenAccess, "Insufficient permissions")
+			c.Abort()
+			return
 		}
 
-		utils.ErrorResponse(c, utils.ErrUnauthorized, "Insufficient permissions")
-		c.Abort()
+		c.Next()
 	}
 }
 
+// RequirePlatformOrganization limits a route to members of the platform
+// organization. Use it for data shared by all tenants, such as roles and
+// login lockouts, so one tenant's admins can't affect another tenant.
+func (am *AuthMiddleware) RequirePlatformOrganization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, _ := c.Get(utils.TenantIDContextKey)
+		if tenantID != am.platformID {
+			utils.ErrorResponse(c, utils.ErrForbiddenAccess, "Only platform administrators can access this resource")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (am *AuthMiddleware) RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !am.config.EmailVerification.BlocksApproval() {
+			c.Next()
+			return
+		}
+
+		if !c.GetBool(utils.EmailVerifiedContextKey) {
+			utils.ErrorResponse(c, utils.ErrEmailNotVerified, "Email address must be verified before performing this action")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireStepUp enforces a recent re-authentication for the given scope.
+// A step-up token is bound to the document in the :id parameter and is
+// accepted only once.
+// The required callback decides per request whether step-up applies, so
+// routes can require it only for specific workflow steps.
+func (am *AuthMiddleware) RequireStepUp(scope string, required func(c *gin.Context) (bool, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		needed, err := required(c)
+		if err != nil {
+			appErrorResponse(c, err)
+			c.Abort()
+			return
+		}
+		if !needed {
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader("X-Step-Up-Token")
+		if token == "" {
+			utils.ErrorResponse(c, utils.ErrStepUpRequired, "Step-up token required for this action")
+			c.Abort()
+			return
+		}
+
+		claims, err := am.jwtManager.ValidateAndExtract(token, securities.TokenTypeStepUp)
+		if err != nil {
+			utils.ErrorResponse(c, utils.ErrStepUpRequired, "Invalid or expired step-up token")
+			c.Abort()
+			return
+		}
+
+		userID, _ := c.Get(utils.UserIDContextKey)
+		if claims.UserID != userID || claims.Scope != scope || claims.ID == "" {
+			utils.ErrorResponse(c, utils.ErrStepUpRequired, "Step-up token is not valid for this action")
+			c.Abort()
+			return
+		}
+		if claims.ResourceID == "" || claims.ResourceID != c.Param("id") {
+			utils.ErrorResponse(c, utils.ErrStepUpRequired, "Step-up token is not valid for this resource")
+			c.Abort()
+			return
+		}
+
+		// Spend the token so it can't authorize a second action. It only
+		// needs remembering until it would fail validation anyway.
+		fresh, err := am.nonces.Remember(c.Request.Context(), "step_up:"+claims.ID, time.Until(claims.ExpiresAt.Time)+am.config.TokenLeeway)
+		if err != nil {
+			utils.ErrorResponse(c, utils.ErrInternalServer, err)
+			c.Abort()
+			return
+		}
+		if !fresh {
+			utils.ErrorResponse(c, utils.ErrStepUpRequired, "Step-up token has already been used")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func appErrorResponse(c *gin.Context, err error) {
+	if appErr, ok := err.(*utils.AppError); ok {
+		utils.ErrorResponse(c, appErr.ErrorCode, appErr)
+		return
+	}
+	utils.ErrorResponse(c, utils.ErrInternalServer, err)
+}
+
+// tokenErrorResponse maps token validation failures to expired vs invalid
+// token responses with the underlying reason as the message.
+func tokenErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, securities.ErrTokenExpired):
+		utils.ErrorResponse(c, utils.ErrTokenExpired, "Token has expired")
+	case errors.Is(err, securities.ErrTokenNotActive):
+		utils.ErrorResponse(c, utils.ErrInvalidToken, "Token is not valid yet")
+	case errors.Is(err, securities.ErrTokenWrongType):
+		utils.ErrorResponse(c, utils.ErrInvalidToken, "Token type is not accepted here")
+	case errors.Is(err, securities.ErrTokenMalformed):
+		utils.ErrorResponse(c, utils.ErrInvalidToken, "Token is malformed")
+	case errors.Is(err, securities.ErrTokenSignature):
+		utils.ErrorResponse(c, utils.ErrInvalidToken, "Token signature is invalid")
+	default:
+		utils.ErrorResponse(c, utils.ErrInvalidToken, "Token is invalid")
+	}
+}
+
+func setAuthContext(c *gin.Context, claims *securities.JWTClaims) {
+	c.Set(utils.UserIDContextKey, claims.UserID)
+	c.Set(utils.TenantIDContextKey, claims.TenantID)
+	c.Set(utils.UsernameContextKey, claims.Username)
+	c.Set(utils.RoleContextKey, claims.Role)
+	c.Set(utils.EmailVerifiedContextKey, claims.EmailVerified)
+	c.Set(utils.SessionIDContextKey, claims.SessionID)
+	c.Set(utils.TokenIDContextKey, claims.ID)
+	ctx := c.Request.Context()
+	ctx = context.WithValue(ctx, utils.UserIDContextKey, claims.UserID)
+	ctx = utils.WithTenant(ctx, claims.TenantID)
+	ctx = context.WithValue(ctx, utils.UsernameContextKey, claims.Username)
+	ctx = context.WithValue(ctx, utils.RoleContextKey, string(claims.Role))
+	ctx = context.WithValue(ctx, utils.EmailVerifiedContextKey, claims.EmailVerified)
+	ctx = context.WithValue(ctx, utils.SessionIDContextKey, claims.SessionID)
+	ctx = context.WithValue(ctx, utils.TokenIDContextKey, claims.ID)
+	c.Request = c.Request.WithContext(ctx)
+}
+
+func setAccessCheck(c *gin.Context, check utils.AccessCheck) {
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), utils.AccessCheckContextKey, check))
+}
+
 func (am *AuthMiddleware) extractToken(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		return ""
+		return webSocketToken(c)
 	}
 
 	if strings.HasPrefix(authHeader, "Bearer ") {
@@ -122,6 +333,25 @@ func (am *AuthMiddleware) extractToken(c *gin.Context) string {
 	return authHeader
 }
 
+// WebSocketTokenProtocol is offered as a subprotocol by browsers, which
+// can't set headers on a WebSocket handshake, with the access token as the
+// protocol after it.
+const WebSocketTokenProtocol = "access_token"
+
+func webSocketToken(c *gin.Context) string {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		return ""
+	}
+
+	protocols := websocket.Subprotocols(c.Request)
+	for i, protocol := range protocols {
+		if protocol == WebSocketTokenProtocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+	return ""
+}
+
 func GetCurrentUserID(c *gin.Context) (uint, bool) {
 	userID, exists := c.Get(utils.UserIDContextKey)
 	if !exists {
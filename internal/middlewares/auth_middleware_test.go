@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testcase/config"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func newStepUpRouter(t *testing.T, jwtManager *securities.JWTManager, userID uuid.UUID) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	am := NewAuthMiddleware(jwtManager, nil, nil, securities.NewMemoryNonceCache(), nil, uuid.Nil, &config.Config{Auth: config.Auth{TokenLeeway: time.Second}})
+	router := gin.New()
+	router.POST("/documents/:id/action",
+		func(c *gin.Context) { c.Set(utils.UserIDContextKey, userID) },
+		am.RequireStepUp(securities.StepUpScopeDocumentAction, func(c *gin.Context) (bool, error) { return true, nil }),
+		func(c *gin.Context) { c.Status(http.StatusNoContent) },
+	)
+	return router
+}
+
+func postAction(router *gin.Engine, documentID, token string) int {
+	req := httptest.NewRequest(http.MethodPost, "/documents/"+documentID+"/action", nil)
+	if token != "" {
+		req.Header.Set("X-Step-Up-Token", token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRequireStepUp(t *testing.T) {
+	jwtManager := securities.NewJWTManager("access-secret", "refresh-secret", time.Minute, time.Hour, nil, securities.JWTOptions{})
+	userID := uuid.New()
+	router := newStepUpRouter(t, jwtManager, userID)
+	payload := &securities.JWTPayload{UserID: userID, TenantID: uuid.New()}
+	documentID := uuid.NewString()
+
+	stepUpToken := func(resourceID string) string {
+		token, err := jwtManager.GenerateStepUpToken(payload, securities.StepUpScopeDocumentAction, resourceID, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	if code := postAction(router, documentID, ""); code == http.StatusNoContent {
+		t.Fatal("expected a missing step-up token to be rejected")
+	}
+	if code := postAction(router, documentID, stepUpToken("")); code == http.StatusNoContent {
+		t.Fatal("expected a token without a resource to be rejected")
+	}
+	if code := postAction(router, documentID, stepUpToken(uuid.NewString())); code == http.StatusNoContent {
+		t.Fatal("expected a token for another document to be rejected")
+	}
+
+	token := stepUpToken(documentID)
+	if code := postAction(router, documentID, token); code != http.StatusNoContent {
+		t.Fatalf("expected the bound token to be accepted, got %d", code)
+	}
+	if code := postAction(router, documentID, token); code == http.StatusNoContent {
+		t.Fatal("expected a second use of the token to be rejected")
+	}
+
+	other := &securities.JWTPayload{UserID: uuid.New(), TenantID: payload.TenantID}
+	foreign, err := jwtManager.GenerateStepUpToken(other, securities.StepUpScopeDocumentAction, documentID, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := postAction(router, documentID, foreign); code == http.StatusNoContent {
+		t.Fatal("expected another user's token to be rejected")
+	}
+}
@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"context"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ClientInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		userAgent := c.Request.UserAgent()
+
+		c.Set(utils.IPAddressContextKey, ip)
+		c.Set(utils.UserAgentContextKey, userAgent)
+		ctx := context.WithValue(c.Request.Context(), utils.IPAddressContextKey, ip)
+		ctx = context.WithValue(ctx, utils.UserAgentContextKey, userAgent)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
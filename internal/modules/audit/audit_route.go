@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/audit/handlers"
+	roleEntities "testcase/internal/modules/role/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAuditRoutes(rg *gin.RouterGroup, h *handlers.AuditHandler, authMware *middlewares.AuthMiddleware) {
+
+	auditRoutes := rg.Group("/audit")
+	auditRoutes.Use(authMware.Auth(), authMware.RequirePermission(roleEntities.PermissionAuditRead))
+	{
+		auditRoutes.GET("", h.ListEntries)
+		auditRoutes.GET("/append-status", h.AppendStatus)
+	}
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// ListAuditQuery filters the audit log. From is inclusive and To is
+// exclusive; both are RFC 3339 timestamps.
+type ListAuditQuery struct {
+	Action     string     `form:"action" binding:"omitempty,max=100"`
+	Outcome    string     `form:"outcome" binding:"omitempty,oneof=success failure"`
+	ActorID    string     `form:"actor_id" binding:"omitempty,uuid"`
+	TargetType string     `form:"target_type" binding:"omitempty,max=50"`
+	TargetID   string     `form:"target_id" binding:"omitempty,max=255"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Actions recorded directly by the services. Document and comment actions
+// use the type of the domain event they come from, such as
+// document.approved.
+const (
+	ActionLogin          = "auth.login"
+	ActionLoginFailed    = "auth.login_failed"
+	ActionTokenIssued    = "auth.token_issued"
+	ActionUserRole       = "user.role_changed"
+	ActionRoleCreated    = "role.created"
+	ActionRoleUpdated    = "role.updated"
+	ActionRoleDeleted    = "role.deleted"
+	ActionAdminOperation = "admin.operation"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// GenesisHash is the PrevHash of the first entry.
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// AuditLog is one entry of the append-only audit log. Entries form a single
+// chain across organizations: Hash covers the entry's content and the hash
+// of the entry before it, so editing, removing or reordering an entry
+// breaks every link after it.
+//
+// OrganizationID is deliberately not called TenantID: the log is written
+// and verified as a whole, outside the per-tenant scoping of the database
+// callbacks. Entries without an organization, such as failed logins for
+// unknown emails, are platform-wide.
+type AuditLog struct {
+	ID             uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	Sequence       int64             `gorm:"not null;uniqueIndex" json:"sequence"`
+	OrganizationID *uuid.UUID        `gorm:"type:uuid;index" json:"organization_id"`
+	ActorID        *uuid.UUID        `gorm:"type:uuid;index" json:"actor_id"`
+	Action         string            `gorm:"type:varchar(100);not null;index" json:"action"`
+	Outcome        string            `gorm:"type:varchar(20);not null" json:"outcome"`
+	TargetType     string            `gorm:"type:varchar(50);index:idx_audit_logs_target" json:"target_type,omitempty"`
+	TargetID       string            `gorm:"type:varchar(255);index:idx_audit_logs_target" json:"target_id,omitempty"`
+	IPAddress      string            `gorm:"type:varchar(64)" json:"ip_address,omitempty"`
+	UserAgent      string            `gorm:"type:text" json:"user_agent,omitempty"`
+	Metadata       map[string]string `gorm:"type:jsonb;serializer:json" json:"metadata,omitempty"`
+	SourceEventID  *uuid.UUID        `gorm:"type:uuid;uniqueIndex" json:"source_event_id,omitempty"`
+	OccurredAt     time.Time         `gorm:"not null;index" json:"occurred_at"`
+	PrevHash       string            `gorm:"type:varchar(64);not null" json:"prev_hash"`
+	Hash           string            `gorm:"type:varchar(64);not null" json:"hash"`
+}
+
+func (l *AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+func (l *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// Link makes the entry the successor of prev, or the first entry of the
+// chain when prev is nil, and computes its hash. Every field the hash
+// covers is settled first, the ID included, so the stored entry hashes the
+// same when read back.
+func (l *AuditLog) Link(prev *AuditLog) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	if l.OccurredAt.IsZero() {
+		l.OccurredAt = time.Now()
+	}
+	l.OccurredAt = l.OccurredAt.UTC().Truncate(time.Microsecond)
+
+	if prev == nil {
+		l.Sequence = 1
+		l.PrevHash = GenesisHash
+	} else {
+		l.Sequence = prev.Sequence + 1
+		l.PrevHash = prev.Hash
+	}
+
+	hash, err := l.ComputeHash()
+	if err != nil {
+		return err
+	}
+	l.Hash = hash
+	return nil
+}
+
+// ComputeHash returns the SHA-256 of the entry's content and PrevHash. The
+// content is encoded as JSON with a fixed field order and sorted metadata
+// keys, and OccurredAt in UTC, so a stored entry hashes the same when read
+// back. OccurredAt must already be truncated to the microseconds PostgreSQL
+// keeps.
+func (l *AuditLog) ComputeHash() (string, error) {
+	content, err := json.Marshal(struct {
+		ID             uuid.UUID         `json:"id"`
+		Sequence       int64             `json:"sequence"`
+		OrganizationID *uuid.UUID        `json:"organization_id"`
+		ActorID        *uuid.UUID        `json:"actor_id"`
+		Action         string            `json:"action"`
+		Outcome        string            `json:"outcome"`
+		TargetType     string            `json:"target_type"`
+		TargetID       string            `json:"target_id"`
+		IPAddress      string            `json:"ip_address"`
+		UserAgent      string            `json:"user_agent"`
+		Metadata       map[string]string `json:"metadata"`
+		SourceEventID  *uuid.UUID        `json:"source_event_id"`
+		OccurredAt     string            `json:"occurred_at"`
+		PrevHash       string            `json:"prev_hash"`
+	}{
+		ID:             l.ID,
+		Sequence:       l.Sequence,
+		OrganizationID: l.OrganizationID,
+		ActorID:        l.ActorID,
+		Action:         l.Action,
+		Outcome:        l.Outcome,
+		TargetType:     l.TargetType,
+		TargetID:       l.TargetID,
+		IPAddress:      l.IPAddress,
+		UserAgent:      l.UserAgent,
+		Metadata:       l.Metadata,
+		SourceEventID:  l.SourceEventID,
+		OccurredAt:     l.OccurredAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:       l.PrevHash,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
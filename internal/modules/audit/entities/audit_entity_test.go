@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLinkHashesTheStoredEntry(t *testing.T) {
+	first := &AuditLog{Action: ActionLogin, Outcome: OutcomeSuccess, Metadata: map[string]string{"b": "2", "a": "1"}}
+	if err := first.Link(nil); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	// GORM runs BeforeCreate after Append linked the entry.
+	if err := first.BeforeCreate(nil); err != nil {
+		t.Fatalf("BeforeCreate() error = %v", err)
+	}
+
+	if first.ID == uuid.Nil {
+		t.Fatal("Link() left the ID unset")
+	}
+	if first.Sequence != 1 || first.PrevHash != GenesisHash {
+		t.Fatalf("first entry = sequence %d, prev %q; want 1 and the genesis hash", first.Sequence, first.PrevHash)
+	}
+	hash, err := first.ComputeHash()
+	if err != nil {
+		t.Fatalf("ComputeHash() error = %v", err)
+	}
+	if hash != first.Hash {
+		t.Fatalf("recomputed hash %s, stored %s", hash, first.Hash)
+	}
+
+	second := &AuditLog{Action: ActionLogin, Outcome: OutcomeFailure}
+	if err := second.Link(first); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if second.Sequence != 2 || second.PrevHash != first.Hash {
+		t.Fatalf("second entry = sequence %d, prev %q; want 2 and %q", second.Sequence, second.PrevHash, first.Hash)
+	}
+}
+
+func TestComputeHashIgnoresTimeZoneAndMetadataOrder(t *testing.T) {
+	occurredAt := time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC)
+	entry := &AuditLog{
+		ID:         uuid.New(),
+		Sequence:   7,
+		Action:     ActionAdminOperation,
+		Outcome:    OutcomeSuccess,
+		Metadata:   map[string]string{"method": "POST", "route": "/api/v1/users/members"},
+		OccurredAt: occurredAt,
+		PrevHash:   GenesisHash,
+	}
+	want, err := entry.ComputeHash()
+	if err != nil {
+		t.Fatalf("ComputeHash() error = %v", err)
+	}
+
+	readBack := *entry
+	readBack.OccurredAt = occurredAt.In(time.FixedZone("WIB", 7*60*60))
+	readBack.Metadata = map[string]string{"route": "/api/v1/users/members", "method": "POST"}
+	got, err := readBack.ComputeHash()
+	if err != nil {
+		t.Fatalf("ComputeHash() error = %v", err)
+	}
+	if got != want {
+		t.Fatalf("hash changed on read back: %s != %s", got, want)
+	}
+
+	readBack.TargetID = "tampered"
+	if tampered, _ := readBack.ComputeHash(); tampered == want {
+		t.Fatal("hash did not change with the content")
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"testcase/internal/helpers"
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/audit/dto"
+	"testcase/internal/modules/audit/services"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService services.AuditService
+}
+
+func NewAuditHandler(auditService services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	var query dto.ListAuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+	params := helpers.ParsePaginationParams(c)
+
+	list, err := h.auditService.ListEntries(c.Request.Context(), &query, params)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, list, "Audit log retrieved successfully", http.StatusOK)
+}
+
+// AppendStatus reports failed appends on the instance that answers, so
+// each replica has to be asked on its own.
+func (h *AuditHandler) AppendStatus(c *gin.Context) {
+	utils.SuccessResponse(c, h.auditService.AppendStatus(), "Audit append status retrieved successfully", http.StatusOK)
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/audit/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditFilter narrows a listing. Entries of OrganizationID are included,
+// and platform-wide entries too when IncludePlatform is set.
+type AuditFilter struct {
+	OrganizationID  uuid.UUID
+	IncludePlatform bool
+	Action          string
+	Outcome         string
+	ActorID         *uuid.UUID
+	TargetType      string
+	TargetID        string
+	From            *time.Time
+	To              *time.Time
+}
+
+type AuditRepository interface {
+	// Append links entry to the last entry of the chain and stores it.
+	// Appends are serialized with an advisory lock so concurrent writers
+	// can't fork the chain. An entry whose SourceEventID is already logged
+	// is skipped, as outbox events are delivered at least once.
+	Append(ctx context.Context, entry *entities.AuditLog) error
+	// ListEntries pages through the entries matching filter, newest first.
+	ListEntries(ctx context.Context, filter *AuditFilter, params *helpers.PaginationParams) ([]entities.AuditLog, int64, error)
+	// Walk calls visit for every entry in sequence order, loading them in
+	// batches, and stops at the first error visit returns.
+	Walk(ctx context.Context, batchSize int, visit func(entry *entities.AuditLog) error) error
+	// EnsureAppendOnly installs triggers that reject updates, deletes and
+	// truncation of the audit table.
+	EnsureAppendOnly(ctx context.Context) error
+}
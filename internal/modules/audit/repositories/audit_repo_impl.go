@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testcase/internal/helpers"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/audit/entities"
+
+	"gorm.io/gorm"
+)
+
+// appendLockKey identifies the transaction-level advisory lock that
+// serializes appends across instances.
+const appendLockKey int64 = 0x61756469742d6c6f // "audit-lo"
+
+type auditRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewAuditRepository(db *database.Database) AuditRepository {
+	return &auditRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *auditRepositoryImpl) Append(ctx context.Context, entry *entities.AuditLog) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", appendLockKey).Error; err != nil {
+			return err
+		}
+
+		if entry.SourceEventID != nil {
+			var logged int64
+			if err := tx.Model(&entities.AuditLog{}).Where("source_event_id = ?", entry.SourceEventID).Count(&logged).Error; err != nil {
+				return err
+			}
+			if logged > 0 {
+				return nil
+			}
+		}
+
+		var last entities.AuditLog
+		err := tx.Order("sequence desc").First(&last).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			err = entry.Link(nil)
+		case err == nil:
+			err = entry.Link(&last)
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *auditRepositoryImpl) ListEntries(ctx context.Context, filter *AuditFilter, params *helpers.PaginationParams) ([]entities.AuditLog, int64, error) {
+	var entries []entities.AuditLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.AuditLog{})
+	if filter.IncludePlatform {
+		query = query.Where("(organization_id = ? OR organization_id IS NULL)", filter.OrganizationID)
+	} else {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at < ?", filter.To)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	err := query.
+		Offset(params.GetOffset()).
+		Limit(params.Limit).
+		Order("sequence desc").
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+func (r *auditRepositoryImpl) Walk(ctx context.Context, batchSize int, visit func(entry *entities.AuditLog) error) error {
+	var after int64
+	for {
+		var batch []entities.AuditLog
+		err := r.db.WithContext(ctx).
+			Where("sequence > ?", after).
+			Order("sequence asc").
+			Limit(batchSize).
+			Find(&batch).Error
+		if err != nil {
+			return fmt.Errorf("failed to read audit entries: %w", err)
+		}
+
+		for i := range batch {
+			if err := visit(&batch[i]); err != nil {
+				return err
+			}
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = batch[len(batch)-1].Sequence
+	}
+}
+
+// EnsureAppendOnly only guards against changes made through the
+// application's database role and by mistake; a superuser can still drop
+// the triggers. Chain verification catches what they let through.
+func (r *auditRepositoryImpl) EnsureAppendOnly(ctx context.Context) error {
+	statements := []string{
+		`CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_logs is append-only: % is not allowed', TG_OP;
+		END;
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS audit_logs_no_modify ON audit_logs`,
+		`CREATE TRIGGER audit_logs_no_modify BEFORE UPDATE OR DELETE ON audit_logs
+			FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only()`,
+		`DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs`,
+		`CREATE TRIGGER audit_logs_no_truncate BEFORE TRUNCATE ON audit_logs
+			FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only()`,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to protect audit log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package responses
+
+import "time"
+
+// ChainHead identifies an entry by its sequence and hash.
+type ChainHead struct {
+	Sequence int64  `json:"sequence"`
+	Hash     string `json:"hash"`
+}
+
+// VerifyResult reports a walk over the audit chain. Head is the last entry
+// checked; recording it elsewhere lets a later run notice entries removed
+// from the end of the chain, which the links alone can't show.
+type VerifyResult struct {
+	Entries      int64  `json:"entries"`
+	HeadSequence int64  `json:"head_sequence"`
+	HeadHash     string `json:"head_hash"`
+	Valid        bool   `json:"valid"`
+	// BrokenAt and Problem describe the first entry that failed, if any.
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Problem  string `json:"problem,omitempty"`
+}
+
+// AppendStatus counts entries an instance could not write. Anything above
+// zero means the log misses events, which verification can't detect.
+type AppendStatus struct {
+	Failures      int64      `json:"failures"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/audit/dto"
+	"testcase/internal/modules/audit/entities"
+	"testcase/internal/modules/audit/responses"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	"testcase/internal/utils"
+)
+
+type AuditService interface {
+	// Record appends entry to the audit log. The organization, actor,
+	// client address and time come from ctx when entry leaves them unset.
+	Record(ctx context.Context, entry *entities.AuditLog) error
+	// HandleEvent records a document or comment event against its
+	// document, once per event.
+	HandleEvent(ctx context.Context, event *outboxEntities.OutboxEvent) error
+	// ListEntries pages through the entries of the organization in ctx.
+	// Members of the platform organization also see platform-wide entries.
+	ListEntries(ctx context.Context, query *dto.ListAuditQuery, params *helpers.PaginationParams) (*utils.PaginationResult, error)
+	// Verify recomputes every hash of the chain and checks the links
+	// between entries, stopping at the first broken one. When anchor is
+	// given, the chain must still hold that entry unchanged, which catches
+	// entries cut from its end.
+	Verify(ctx context.Context, anchor *responses.ChainHead) (*responses.VerifyResult, error)
+	// AppendStatus reports the entries this instance failed to append since
+	// it started.
+	AppendStatus() *responses.AppendStatus
+}
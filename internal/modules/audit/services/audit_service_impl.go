@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"testcase/config"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/audit/dto"
+	"testcase/internal/modules/audit/entities"
+	"testcase/internal/modules/audit/repositories"
+	"testcase/internal/modules/audit/responses"
+	organizationRepositories "testcase/internal/modules/organization/repositories"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	"testcase/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// verifyBatchSize is how many entries Verify loads at a time.
+const verifyBatchSize = 1000
+
+// TargetDocument is the target type of document and comment events.
+const TargetDocument = "document"
+
+var errChainBroken = errors.New("audit chain broken")
+
+type auditServiceImpl struct {
+	auditRepo        repositories.AuditRepository
+	organizationRepo organizationRepositories.OrganizationRepository
+	config           *config.Config
+	failures         atomic.Int64
+	lastFailure      atomic.Pointer[time.Time]
+}
+
+func NewAuditService(auditRepo repositories.AuditRepository, organizationRepo organizationRepositories.OrganizationRepository, cfg *config.Config) AuditService {
+	return &auditServiceImpl{
+		auditRepo:        auditRepo,
+		organizationRepo: organizationRepo,
+		config:           cfg,
+	}
+}
+
+func (s *auditServiceImpl) Record(ctx context.Context, entry *entities.AuditLog) error {
+	if entry.OrganizationID == nil {
+		if tenantID, ok := ctx.Value(utils.TenantIDContextKey).(uuid.UUID); ok && tenantID != uuid.Nil {
+			entry.OrganizationID = &tenantID
+		}
+	}
+	if entry.ActorID == nil {
+		if userID, ok := ctx.Value(utils.UserIDContextKey).(uuid.UUID); ok && userID != uuid.Nil {
+			entry.ActorID = &userID
+		}
+	}
+	if entry.IPAddress == "" {
+		entry.IPAddress, _ = ctx.Value(utils.IPAddressContextKey).(string)
+	}
+	if entry.UserAgent == "" {
+		entry.UserAgent, _ = ctx.Value(utils.UserAgentContextKey).(string)
+	}
+	if entry.Outcome == "" {
+		entry.Outcome = entities.OutcomeSuccess
+	}
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now()
+	}
+
+	return s.append(ctx, entry)
+}
+
+// eventPayload holds the fields document and comment events share, plus
+// the ones worth keeping from either.
+type eventPayload struct {
+	ActorID    *uuid.UUID `json:"actor_id"`
+	Status     string     `json:"status"`
+	Step       int        `json:"step"`
+	Revision   *int       `json:"revision"`
+	CommentID  *uuid.UUID `json:"comment_id"`
+	OccurredAt time.Time  `json:"occurred_at"`
+}
+
+func (s *auditServiceImpl) HandleEvent(ctx context.Context, event *outboxEntities.OutboxEvent) error {
+	var payload eventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", event.Type, err)
+	}
+
+	metadata := map[string]string{}
+	if payload.Status != "" {
+		metadata["status"] = payload.Status
+	}
+	if payload.Step > 0 {
+		metadata["step"] = strconv.Itoa(payload.Step)
+	}
+	if payload.Revision != nil {
+		metadata["revision"] = strconv.Itoa(*payload.Revision)
+	}
+	if payload.CommentID != nil {
+		metadata["comment_id"] = payload.CommentID.String()
+	}
+
+	entry := &entities.AuditLog{
+		ActorID:       payload.ActorID,
+		Action:        event.Type,
+		Outcome:       entities.OutcomeSuccess,
+		TargetType:    TargetDocument,
+		TargetID:      event.AggregateID.String(),
+		Metadata:      metadata,
+		SourceEventID: &event.ID,
+		OccurredAt:    payload.OccurredAt,
+	}
+	if event.TenantID != uuid.Nil {
+		entry.OrganizationID = &event.TenantID
+	}
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = event.CreatedAt
+	}
+
+	return s.append(ctx, entry)
+}
+
+// append writes entry and counts the failures. Callers can't do much about
+// a lost entry, so each one is also logged as an alert for operators.
+func (s *auditServiceImpl) append(ctx context.Context, entry *entities.AuditLog) error {
+	err := s.auditRepo.Append(ctx, entry)
+	if err != nil {
+		now := time.Now()
+		s.lastFailure.Store(&now)
+		failures := s.failures.Add(1)
+		log.Printf("🚨 ALERT: audit entry %s lost (%d failed appends since start): %v", entry.Action, failures, err)
+	}
+	return err
+}
+
+func (s *auditServiceImpl) AppendStatus() *responses.AppendStatus {
+	return &responses.AppendStatus{
+		Failures:      s.failures.Load(),
+		LastFailureAt: s.lastFailure.Load(),
+	}
+}
+
+func (s *auditServiceImpl) ListEntries(ctx context.Context, query *dto.ListAuditQuery, params *helpers.PaginationParams) (*utils.PaginationResult, error) {
+	tenantID, ok := ctx.Value(utils.TenantIDContextKey).(uuid.UUID)
+	if !ok || tenantID == uuid.Nil {
+		return nil, utils.NewAppError(utils.ErrUnauthorized, fmt.Errorf("tenant id missing from context"))
+	}
+	platform, err := s.organizationRepo.FindBySlug(ctx, s.config.Organization.DefaultSlug)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	filter := &repositories.AuditFilter{
+		OrganizationID:  tenantID,
+		IncludePlatform: platform != nil && platform.ID == tenantID,
+		Action:          query.Action,
+		Outcome:         query.Outcome,
+		TargetType:      query.TargetType,
+		TargetID:        query.TargetID,
+		From:            query.From,
+		To:              query.To,
+	}
+	if query.ActorID != "" {
+		actorID, err := uuid.Parse(query.ActorID)
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid actor ID: %w", err))
+		}
+		filter.ActorID = &actorID
+	}
+
+	entries, total, err := s.auditRepo.ListEntries(ctx, filter, params)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return helpers.CreatePaginationResult(entries, total, params), nil
+}
+
+func (s *auditServiceImpl) Verify(ctx context.Context, anchor *responses.ChainHead) (*responses.VerifyResult, error) {
+	result := &responses.VerifyResult{
+		HeadHash: entities.GenesisHash,
+		Valid:    true,
+	}
+
+	fail := func(entry *entities.AuditLog, problem string) error {
+		result.Valid = false
+		result.BrokenAt = entry.Sequence
+		result.Problem = problem
+		return errChainBroken
+	}
+
+	err := s.auditRepo.Walk(ctx, verifyBatchSize, func(entry *entities.AuditLog) error {
+		if entry.Sequence != result.HeadSequence+1 {
+			return fail(entry, fmt.Sprintf("expected sequence %d, found %d", result.HeadSequence+1, entry.Sequence))
+		}
+		if entry.PrevHash != result.HeadHash {
+			return fail(entry, "previous hash does not match the entry before it")
+		}
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			return fail(entry, "content does not match its hash")
+		}
+		if anchor != nil && entry.Sequence == anchor.Sequence && entry.Hash != anchor.Hash {
+			return fail(entry, "hash does not match the recorded head")
+		}
+
+		result.Entries++
+		result.HeadSequence = entry.Sequence
+		result.HeadHash = entry.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if result.Valid && anchor != nil && result.HeadSequence < anchor.Sequence {
+		result.Valid = false
+		result.BrokenAt = result.HeadSequence + 1
+		result.Problem = fmt.Sprintf("chain ends before the recorded head at sequence %d", anchor.Sequence)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/audit/entities"
+	"testcase/internal/modules/audit/repositories"
+	"testcase/internal/modules/audit/responses"
+	"testing"
+)
+
+// memoryAuditRepository links entries the way the database repository
+// does and keeps copies, so tests can tamper with what was stored.
+type memoryAuditRepository struct {
+	entries []entities.AuditLog
+}
+
+func (r *memoryAuditRepository) Append(ctx context.Context, entry *entities.AuditLog) error {
+	var last *entities.AuditLog
+	if len(r.entries) > 0 {
+		last = &r.entries[len(r.entries)-1]
+	}
+	if err := entry.Link(last); err != nil {
+		return err
+	}
+	if err := entry.BeforeCreate(nil); err != nil {
+		return err
+	}
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *memoryAuditRepository) ListEntries(ctx context.Context, filter *repositories.AuditFilter, params *helpers.PaginationParams) ([]entities.AuditLog, int64, error) {
+	return r.entries, int64(len(r.entries)), nil
+}
+
+func (r *memoryAuditRepository) Walk(ctx context.Context, batchSize int, visit func(entry *entities.AuditLog) error) error {
+	for i := range r.entries {
+		entry := r.entries[i]
+		if err := visit(&entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryAuditRepository) EnsureAppendOnly(ctx context.Context) error {
+	return nil
+}
+
+func appendEntries(t *testing.T, count int) (*auditServiceImpl, *memoryAuditRepository) {
+	t.Helper()
+
+	repo := &memoryAuditRepository{}
+	service := &auditServiceImpl{auditRepo: repo}
+	for i := 0; i < count; i++ {
+		entry := &entities.AuditLog{
+			Action:     entities.ActionLogin,
+			TargetType: "user",
+			Metadata:   map[string]string{"attempt": string(rune('a' + i))},
+		}
+		if err := service.Record(context.Background(), entry); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	return service, repo
+}
+
+func TestVerifyAcceptsAppendedChain(t *testing.T) {
+	service, repo := appendEntries(t, 3)
+
+	result, err := service.Verify(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("Verify() = broken at %d: %s", result.BrokenAt, result.Problem)
+	}
+	if result.Entries != 3 || result.HeadSequence != 3 || result.HeadHash != repo.entries[2].Hash {
+		t.Fatalf("Verify() = %+v, want 3 entries ending at %s", result, repo.entries[2].Hash)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name     string
+		tamper   func(entries []entities.AuditLog) []entities.AuditLog
+		brokenAt int64
+	}{
+		{
+			name: "edited content",
+			tamper: func(entries []entities.AuditLog) []entities.AuditLog {
+				entries[1].Outcome = entities.OutcomeFailure
+				return entries
+			},
+			brokenAt: 2,
+		},
+		{
+			name: "rehashed entry",
+			tamper: func(entries []entities.AuditLog) []entities.AuditLog {
+				entries[0].TargetID = "someone-else"
+				entries[0].Hash, _ = entries[0].ComputeHash()
+				return entries
+			},
+			brokenAt: 2,
+		},
+		{
+			name: "removed entry",
+			tamper: func(entries []entities.AuditLog) []entities.AuditLog {
+				return append(entries[:1], entries[2:]...)
+			},
+			brokenAt: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo := appendEntries(t, 3)
+			repo.entries = tt.tamper(repo.entries)
+
+			result, err := service.Verify(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if result.Valid || result.BrokenAt != tt.brokenAt {
+				t.Fatalf("Verify() = valid %v broken at %d, want broken at %d", result.Valid, result.BrokenAt, tt.brokenAt)
+			}
+		})
+	}
+}
+
+func TestVerifyDetectsTruncationAgainstAnchor(t *testing.T) {
+	service, repo := appendEntries(t, 3)
+	anchor := &responses.ChainHead{Sequence: 3, Hash: repo.entries[2].Hash}
+
+	repo.entries = repo.entries[:2]
+	result, err := service.Verify(context.Background(), anchor)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Valid || result.BrokenAt != 3 {
+		t.Fatalf("Verify() = valid %v broken at %d, want broken at 3", result.Valid, result.BrokenAt)
+	}
+}
@@ -0,0 +1,22 @@
+package comment
+
+import (
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/comment/handlers"
+	roleEntities "testcase/internal/modules/role/entities"
+	"testcase/package/securities"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCommentRoutes(rg *gin.RouterGroup, h *handlers.CommentHandler, authMware *middlewares.AuthMiddleware) {
+
+	commentRoutes := rg.Group("/documents/:id/comments")
+	{
+		commentRoutes.GET("", authMware.Auth(securities.APIScopeDocumentsRead), authMware.RequirePermission(roleEntities.PermissionDocumentRead), h.ListComments)
+		commentRoutes.POST("", authMware.Auth(securities.APIScopeDocumentsWrite), authMware.RequirePermission(roleEntities.PermissionDocumentRead), h.CreateComment)
+		commentRoutes.PUT("/:commentId", authMware.Auth(securities.APIScopeDocumentsWrite), authMware.RequirePermission(roleEntities.PermissionDocumentRead), h.UpdateComment)
+		commentRoutes.DELETE("/:commentId", authMware.Auth(securities.APIScopeDocumentsWrite), authMware.RequirePermission(roleEntities.PermissionDocumentRead), h.DeleteComment)
+		commentRoutes.GET("/:commentId/history", authMware.Auth(securities.APIScopeDocumentsRead), authMware.RequirePermission(roleEntities.PermissionDocumentRead), h.CommentHistory)
+	}
+}
@@ -0,0 +1,18 @@
+package dto
+
+// CreateCommentInput starts a thread, or replies to one when ParentID is
+// set. Revision anchors a new thread to a submission of the document;
+// replies take the revision of their thread.
+type CreateCommentInput struct {
+	Body     string  `json:"body" binding:"required,max=5000"`
+	ParentID *string `json:"parent_id,omitempty" binding:"omitempty,uuid"`
+	Revision *int    `json:"revision,omitempty" binding:"omitempty,min=1"`
+}
+
+type UpdateCommentInput struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+type ListCommentsQuery struct {
+	Revision *int `form:"revision" binding:"omitempty,min=1"`
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Comment is a message in a document's discussion. Top-level comments
+// start threads and replies point at them through ParentID; threads are
+// one level deep. Deleting a comment clears its body but keeps it in the
+// thread so the replies still make sense.
+type Comment struct {
+	ID         uuid.UUID   `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID   uuid.UUID   `gorm:"type:uuid;index" json:"tenant_id"`
+	DocumentID uuid.UUID   `gorm:"type:uuid;not null;index:idx_comments_document_created" json:"document_id"`
+	ParentID   *uuid.UUID  `gorm:"type:uuid;index" json:"parent_id"`
+	AuthorID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"author_id"`
+	Revision   *int        `json:"revision"`
+	Body       string      `gorm:"type:text;not null" json:"body"`
+	Mentions   []uuid.UUID `gorm:"type:jsonb;serializer:json" json:"mentions"`
+	EditedAt   *time.Time  `json:"edited_at"`
+	DeletedAt  *time.Time  `json:"deleted_at"`
+	DeletedBy  *uuid.UUID  `gorm:"type:uuid" json:"deleted_by,omitempty"`
+	CreatedAt  time.Time   `gorm:"index:idx_comments_document_created" json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+func (c *Comment) TableName() string {
+	return "comments"
+}
+
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// History actions.
+const (
+	HistoryEdited  = "edited"
+	HistoryDeleted = "deleted"
+)
+
+// CommentHistory keeps the body a comment had before an edit or delete.
+type CommentHistory struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID  uuid.UUID `gorm:"type:uuid;index" json:"tenant_id"`
+	CommentID uuid.UUID `gorm:"type:uuid;not null;index" json:"comment_id"`
+	Action    string    `gorm:"type:varchar(20);not null" json:"action"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	ActorID   uuid.UUID `gorm:"type:uuid;not null" json:"actor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (h *CommentHistory) TableName() string {
+	return "comment_history"
+}
+
+func (h *CommentHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"encoding/json"
+	"testcase/internal/modules/comment/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types raised by document discussions. Their aggregate is the
+// document, not the comment.
+const (
+	CommentCreated = "comment.created"
+	CommentEdited  = "comment.edited"
+	CommentDeleted = "comment.deleted"
+)
+
+// Types lists every comment event type.
+var Types = []string{
+	CommentCreated,
+	CommentEdited,
+	CommentDeleted,
+}
+
+// CommentEvent is the payload of every comment event. Mentioned lists the
+// users newly mentioned by this change, who are notified.
+type CommentEvent struct {
+	CommentID     uuid.UUID   `json:"comment_id"`
+	DocumentID    uuid.UUID   `json:"document_id"`
+	DocumentTitle string      `json:"document_title"`
+	ParentID      *uuid.UUID  `json:"parent_id,omitempty"`
+	Revision      *int        `json:"revision,omitempty"`
+	AuthorID      uuid.UUID   `json:"author_id"`
+	ActorID       uuid.UUID   `json:"actor_id"`
+	Body          string      `json:"body,omitempty"`
+	Mentioned     []uuid.UUID `json:"mentioned,omitempty"`
+	OccurredAt    time.Time   `json:"occurred_at"`
+}
+
+// New builds an outbox event describing comment after actorID changed it.
+func New(eventType string, comment *entities.Comment, documentTitle string, actorID uuid.UUID, mentioned []uuid.UUID) (*outboxEntities.OutboxEvent, error) {
+	payload := CommentEvent{
+		CommentID:     comment.ID,
+		DocumentID:    comment.DocumentID,
+		DocumentTitle: documentTitle,
+		ParentID:      comment.ParentID,
+		Revision:      comment.Revision,
+		AuthorID:      comment.AuthorID,
+		ActorID:       actorID,
+		Body:          comment.Body,
+		Mentioned:     mentioned,
+		OccurredAt:    time.Now(),
+	}
+
+	return outboxEntities.NewOutboxEvent(eventType, comment.DocumentID, payload)
+}
+
+// Decode reads the payload of a comment event.
+func Decode(event *outboxEntities.OutboxEvent) (*CommentEvent, error) {
+	var payload CommentEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"testcase/internal/helpers"
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/comment/dto"
+	"testcase/internal/modules/comment/services"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService services.CommentService
+}
+
+func NewCommentHandler(commentService services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var input dto.CreateCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, comment, "Comment created successfully", http.StatusCreated)
+}
+
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	var query dto.ListCommentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+	params := helpers.ParsePaginationParams(c)
+
+	list, err := h.commentService.ListComments(c.Request.Context(), c.Param("id"), query.Revision, params)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, list, "Comments retrieved successfully", http.StatusOK)
+}
+
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	var input dto.UpdateCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(c.Request.Context(), c.Param("id"), c.Param("commentId"), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, comment, "Comment updated successfully", http.StatusOK)
+}
+
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	if err := h.commentService.DeleteComment(c.Request.Context(), c.Param("id"), c.Param("commentId")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Comment deleted successfully", http.StatusOK)
+}
+
+func (h *CommentHandler) CommentHistory(c *gin.Context) {
+	history, err := h.commentService.CommentHistory(c.Request.Context(), c.Param("id"), c.Param("commentId"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, history, "Comment history retrieved successfully", http.StatusOK)
+}
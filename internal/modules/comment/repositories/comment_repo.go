@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/comment/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+
+	"github.com/google/uuid"
+)
+
+type CommentRepository interface {
+	FindComment(ctx context.Context, documentID, id uuid.UUID) (*entities.Comment, error)
+	// ListThreads pages through the top-level comments of a document,
+	// oldest first, optionally only those anchored to revision.
+	ListThreads(ctx context.Context, documentID uuid.UUID, revision *int, params *helpers.PaginationParams) ([]entities.Comment, int64, error)
+	ListReplies(ctx context.Context, parentIDs []uuid.UUID) ([]entities.Comment, error)
+	ListHistory(ctx context.Context, commentID uuid.UUID) ([]entities.CommentHistory, error)
+	// CreateComment and UpdateComment write events to the outbox in the
+	// same transaction as the comment. UpdateComment also records history.
+	CreateComment(ctx context.Context, comment *entities.Comment, events ...*outboxEntities.OutboxEvent) error
+	UpdateComment(ctx context.Context, comment *entities.Comment, history *entities.CommentHistory, events ...*outboxEntities.OutboxEvent) error
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testcase/internal/helpers"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/comment/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type commentRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewCommentRepository(db *database.Database) CommentRepository {
+	return &commentRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *commentRepositoryImpl) FindComment(ctx context.Context, documentID, id uuid.UUID) (*entities.Comment, error) {
+	var comment entities.Comment
+
+	err := r.db.WithContext(ctx).Where("id = ? AND document_id = ?", id, documentID).First(&comment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("comment with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+func (r *commentRepositoryImpl) ListThreads(ctx context.Context, documentID uuid.UUID, revision *int, params *helpers.PaginationParams) ([]entities.Comment, int64, error) {
+	var comments []entities.Comment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Comment{}).Where("document_id = ? AND parent_id IS NULL", documentID)
+	if revision != nil {
+		query = query.Where("revision = ?", *revision)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	err := query.
+		Offset(params.GetOffset()).
+		Limit(params.Limit).
+		Order("created_at asc, id asc").
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	return comments, total, nil
+}
+
+func (r *commentRepositoryImpl) ListReplies(ctx context.Context, parentIDs []uuid.UUID) ([]entities.Comment, error) {
+	var replies []entities.Comment
+	if len(parentIDs) == 0 {
+		return replies, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("parent_id IN ?", parentIDs).
+		Order("created_at asc, id asc").
+		Find(&replies).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replies: %w", err)
+	}
+
+	return replies, nil
+}
+
+func (r *commentRepositoryImpl) ListHistory(ctx context.Context, commentID uuid.UUID) ([]entities.CommentHistory, error) {
+	var history []entities.CommentHistory
+
+	err := r.db.WithContext(ctx).
+		Where("comment_id = ?", commentID).
+		Order("created_at asc").
+		Find(&history).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comment history: %w", err)
+	}
+
+	return history, nil
+}
+
+func (r *commentRepositoryImpl) CreateComment(ctx context.Context, comment *entities.Comment, events ...*outboxEntities.OutboxEvent) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return writeEvents(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *commentRepositoryImpl) UpdateComment(ctx context.Context, comment *entities.Comment, history *entities.CommentHistory, events ...*outboxEntities.OutboxEvent) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(history).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(comment).Error; err != nil {
+			return err
+		}
+		return writeEvents(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return nil
+}
+
+func writeEvents(tx *gorm.DB, events []*outboxEntities.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(events).Error
+}
@@ -0,0 +1,29 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CommentUser struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Name     string    `json:"name"`
+}
+
+// CommentResponse is a comment with its author and mentions resolved. A
+// thread carries its replies; a deleted comment has an empty body.
+type CommentResponse struct {
+	ID         uuid.UUID         `json:"id"`
+	DocumentID uuid.UUID         `json:"document_id"`
+	ParentID   *uuid.UUID        `json:"parent_id"`
+	Revision   *int              `json:"revision"`
+	Author     CommentUser       `json:"author"`
+	Body       string            `json:"body"`
+	Mentions   []CommentUser     `json:"mentions"`
+	EditedAt   *time.Time        `json:"edited_at"`
+	DeletedAt  *time.Time        `json:"deleted_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Replies    []CommentResponse `json:"replies,omitempty"`
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	documentEntities "testcase/internal/modules/document/entities"
+	"testcase/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// maxMentions bounds how many users one comment can notify.
+const maxMentions = 20
+
+// mentionPattern matches @username where usernames are alphanumeric, and
+// not inside a word such as an email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@])@([A-Za-z0-9]+)`)
+
+// mentionedUsernames lists the distinct usernames body mentions, in order.
+func mentionedUsernames(body string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentions {
+			break
+		}
+	}
+	return usernames
+}
+
+// resolveMentions turns the mentions in body into the active users who can
+// read document. Mentions of anyone else are left as plain text, so a
+// comment can't reveal the document to them.
+func (s *commentServiceImpl) resolveMentions(ctx context.Context, document *documentEntities.Document, body string) ([]uuid.UUID, error) {
+	usernames := mentionedUsernames(body)
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	users, err := s.userRepo.FindActiveByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	var mentions []uuid.UUID
+	for _, user := range users {
+		visible, err := s.documents.VisibleTo(ctx, document, user.ID, string(user.Role))
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrInternalServer, err)
+		}
+		if visible {
+			mentions = append(mentions, user.ID)
+		}
+	}
+
+	return mentions, nil
+}
+
+// newMentions returns the mentions not already in previous.
+func newMentions(mentions, previous []uuid.UUID) []uuid.UUID {
+	var added []uuid.UUID
+	for _, id := range mentions {
+		if !containsID(previous, id) {
+			added = append(added, id)
+		}
+	}
+	return added
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
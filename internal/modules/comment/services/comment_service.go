@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/comment/dto"
+	"testcase/internal/modules/comment/entities"
+	"testcase/internal/modules/comment/responses"
+	"testcase/internal/utils"
+)
+
+// CommentService manages document discussions. Every method first reads
+// the document as the caller, so comments follow the document's
+// visibility.
+type CommentService interface {
+	CreateComment(ctx context.Context, documentID string, input *dto.CreateCommentInput) (*responses.CommentResponse, error)
+	// ListComments pages through threads with their replies. A non-nil
+	// revision keeps only threads anchored to it.
+	ListComments(ctx context.Context, documentID string, revision *int, params *helpers.PaginationParams) (*utils.PaginationResult, error)
+	// UpdateComment and DeleteComment keep the previous body in the
+	// comment's history. Only the author may edit; the author or a holder
+	// of comment.moderate may delete.
+	UpdateComment(ctx context.Context, documentID, id string, input *dto.UpdateCommentInput) (*responses.CommentResponse, error)
+	DeleteComment(ctx context.Context, documentID, id string) error
+	// CommentHistory is open to the author and comment moderators.
+	CommentHistory(ctx context.Context, documentID, id string) ([]entities.CommentHistory, error)
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/comment/dto"
+	"testcase/internal/modules/comment/entities"
+	commentEvents "testcase/internal/modules/comment/events"
+	"testcase/internal/modules/comment/repositories"
+	"testcase/internal/modules/comment/responses"
+	documentEntities "testcase/internal/modules/document/entities"
+	documentServices "testcase/internal/modules/document/services"
+	roleEntities "testcase/internal/modules/role/entities"
+	userRepositories "testcase/internal/modules/user/repositories"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxBodyLength matches the HTTP binding, for callers that don't go
+// through it such as the review socket.
+const maxBodyLength = 5000
+
+type commentServiceImpl struct {
+	commentRepo repositories.CommentRepository
+	userRepo    userRepositories.UserRepository
+	documents   documentServices.DocumentService
+	permissions securities.PermissionChecker
+}
+
+func NewCommentService(commentRepo repositories.CommentRepository, userRepo userRepositories.UserRepository, documents documentServices.DocumentService, permissions securities.PermissionChecker) CommentService {
+	return &commentServiceImpl{
+		commentRepo: commentRepo,
+		userRepo:    userRepo,
+		documents:   documents,
+		permissions: permissions,
+	}
+}
+
+func (s *commentServiceImpl) CreateComment(ctx context.Context, documentID string, input *dto.CreateCommentInput) (*responses.CommentResponse, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	document, err := s.documents.FindById(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := commentBody(input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &entities.Comment{
+		ID:         uuid.New(),
+		DocumentID: document.ID,
+		AuthorID:   userID,
+		Body:       body,
+		CreatedAt:  time.Now(),
+	}
+	if input.ParentID != nil {
+		parent, err := s.findComment(ctx, document, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ParentID != nil {
+			// Replying to a reply continues the same thread.
+			if parent, err = s.findComment(ctx, document, parent.ParentID.String()); err != nil {
+				return nil, err
+			}
+		}
+		if parent.IsDeleted() {
+			return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("comment %s is deleted", parent.ID), "Cannot reply to a deleted comment")
+		}
+		comment.ParentID = &parent.ID
+		comment.Revision = parent.Revision
+	} else if input.Revision != nil {
+		if *input.Revision > document.Revision {
+			return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("revision %d does not exist", *input.Revision), fmt.Sprintf("Revision must be between 1 and %d", document.Revision))
+		}
+		comment.Revision = input.Revision
+	}
+
+	comment.Mentions, err = s.resolveMentions(ctx, document, body)
+	if err != nil {
+		return nil, err
+	}
+	created, err := commentEvents.New(commentEvents.CommentCreated, comment, document.Title, userID, comment.Mentions)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	if err := s.commentRepo.CreateComment(ctx, comment, created); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return s.single(ctx, comment)
+}
+
+func (s *commentServiceImpl) ListComments(ctx context.Context, documentID string, revision *int, params *helpers.PaginationParams) (*utils.PaginationResult, error) {
+	document, err := s.documents.FindById(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, total, err := s.commentRepo.ListThreads(ctx, document.ID, revision, params)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	threadIDs := make([]uuid.UUID, 0, len(threads))
+	for _, thread := range threads {
+		threadIDs = append(threadIDs, thread.ID)
+	}
+	replies, err := s.commentRepo.ListReplies(ctx, threadIDs)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	users, err := s.users(ctx, append(append([]entities.Comment{}, threads...), replies...))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]responses.CommentResponse, 0, len(threads))
+	for i := range threads {
+		thread := toResponse(&threads[i], users)
+		for j := range replies {
+			if *replies[j].ParentID == threads[i].ID {
+				thread.Replies = append(thread.Replies, toResponse(&replies[j], users))
+			}
+		}
+		list = append(list, thread)
+	}
+
+	return helpers.CreatePaginationResult(list, total, params), nil
+}
+
+func (s *commentServiceImpl) UpdateComment(ctx context.Context, documentID, id string, input *dto.UpdateCommentInput) (*responses.CommentResponse, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	document, err := s.documents.FindById(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.findComment(ctx, document, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrForbiddenAccess, fmt.Errorf("user %s is not the author of comment %s", userID, comment.ID), "Only the author can edit a comment")
+	}
+	if comment.IsDeleted() {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("comment %s is deleted", comment.ID), "Cannot edit a deleted comment")
+	}
+	body, err := commentBody(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	if body == comment.Body {
+		return s.single(ctx, comment)
+	}
+
+	mentions, err := s.resolveMentions(ctx, document, body)
+	if err != nil {
+		return nil, err
+	}
+	added := newMentions(mentions, comment.Mentions)
+
+	now := time.Now()
+	history := &entities.CommentHistory{
+		CommentID: comment.ID,
+		Action:    entities.HistoryEdited,
+		Body:      comment.Body,
+		ActorID:   userID,
+		CreatedAt: now,
+	}
+	comment.Body = body
+	comment.Mentions = mentions
+	comment.EditedAt = &now
+
+	edited, err := commentEvents.New(commentEvents.CommentEdited, comment, document.Title, userID, added)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	if err := s.commentRepo.UpdateComment(ctx, comment, history, edited); err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	return s.single(ctx, comment)
+}
+
+func (s *commentServiceImpl) DeleteComment(ctx context.Context, documentID, id string) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+	document, err := s.documents.FindById(ctx, documentID)
+	if err != nil {
+		return err
+	}
+	comment, err := s.findComment(ctx, document, id)
+	if err != nil {
+		return err
+	}
+	if comment.IsDeleted() {
+		return nil
+	}
+	if err := s.authorOrModerator(ctx, comment, userID, "Only the author or a moderator can delete a comment"); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	history := &entities.CommentHistory{
+		CommentID: comment.ID,
+		Action:    entities.HistoryDeleted,
+		Body:      comment.Body,
+		ActorID:   userID,
+		CreatedAt: now,
+	}
+	comment.Body = ""
+	comment.Mentions = nil
+	comment.DeletedAt = &now
+	comment.DeletedBy = &userID
+
+	deleted, err := commentEvents.New(commentEvents.CommentDeleted, comment, document.Title, userID, nil)
+	if err != nil {
+		return utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	if err := s.commentRepo.UpdateComment(ctx, comment, history, deleted); err != nil {
+		return utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	return nil
+}
+
+func (s *commentServiceImpl) CommentHistory(ctx context.Context, documentID, id string) ([]entities.CommentHistory, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	document, err := s.documents.FindById(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.findComment(ctx, document, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorOrModerator(ctx, comment, userID, "Only the author or a moderator can see a comment's history"); err != nil {
+		return nil, err
+	}
+
+	history, err := s.commentRepo.ListHistory(ctx, comment.ID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return history, nil
+}
+
+func (s *commentServiceImpl) findComment(ctx context.Context, document *documentEntities.Document, id string) (*entities.Comment, error) {
+	commentID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid comment ID: %w", err))
+	}
+
+	comment, err := s.commentRepo.FindComment(ctx, document.ID, commentID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrNotFound, err)
+	}
+
+	return comment, nil
+}
+
+func (s *commentServiceImpl) authorOrModerator(ctx context.Context, comment *entities.Comment, userID uuid.UUID, message string) error {
+	if comment.AuthorID == userID {
+		return nil
+	}
+
+	role, _ := ctx.Value(utils.RoleContextKey).(string)
+	moderator, err := s.permissions.RoleHasPermissions(ctx, role, roleEntities.PermissionCommentModerate)
+	if err != nil {
+		return utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("failed to check moderate permission: %w", err))
+	}
+	if !moderator {
+		return utils.NewAppErrorWithMessage(utils.ErrForbiddenAccess, fmt.Errorf("user %s may not manage comment %s", userID, comment.ID), message)
+	}
+
+	return nil
+}
+
+func (s *commentServiceImpl) single(ctx context.Context, comment *entities.Comment) (*responses.CommentResponse, error) {
+	users, err := s.users(ctx, []entities.Comment{*comment})
+	if err != nil {
+		return nil, err
+	}
+	response := toResponse(comment, users)
+	return &response, nil
+}
+
+// users loads the authors and mentioned users of comments by ID.
+func (s *commentServiceImpl) users(ctx context.Context, comments []entities.Comment) (map[uuid.UUID]responses.CommentUser, error) {
+	var ids []uuid.UUID
+	for _, comment := range comments {
+		ids = append(ids, comment.AuthorID)
+		ids = append(ids, comment.Mentions...)
+	}
+
+	found, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	users := make(map[uuid.UUID]responses.CommentUser, len(found))
+	for _, user := range found {
+		users[user.ID] = responses.CommentUser{ID: user.ID, Username: user.Username, Name: user.Name}
+	}
+
+	return users, nil
+}
+
+func toResponse(comment *entities.Comment, users map[uuid.UUID]responses.CommentUser) responses.CommentResponse {
+	response := responses.CommentResponse{
+		ID:         comment.ID,
+		DocumentID: comment.DocumentID,
+		ParentID:   comment.ParentID,
+		Revision:   comment.Revision,
+		Author:     commentUser(comment.AuthorID, users),
+		Body:       comment.Body,
+		Mentions:   []responses.CommentUser{},
+		EditedAt:   comment.EditedAt,
+		DeletedAt:  comment.DeletedAt,
+		CreatedAt:  comment.CreatedAt,
+	}
+	for _, id := range comment.Mentions {
+		response.Mentions = append(response.Mentions, commentUser(id, users))
+	}
+	return response
+}
+
+// commentUser falls back to the bare ID for users that were deleted.
+func commentUser(id uuid.UUID, users map[uuid.UUID]responses.CommentUser) responses.CommentUser {
+	if user, ok := users[id]; ok {
+		return user
+	}
+	return responses.CommentUser{ID: id}
+}
+
+func commentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("comment body is blank"), "Comment must not be blank")
+	}
+	if utf8.RuneCountInString(body) > maxBodyLength {
+		return "", utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("comment body too long"), fmt.Sprintf("Comment must be at most %d characters", maxBodyLength))
+	}
+	return body, nil
+}
+
+func currentUserID(ctx context.Context) (uuid.UUID, error) {
+	userID, ok := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return uuid.Nil, utils.NewAppError(utils.ErrUnauthorized, fmt.Errorf("user id missing from context"))
+	}
+	return userID, nil
+}
@@ -0,0 +1,30 @@
+package department
+
+import (
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/department/handlers"
+	roleEntities "testcase/internal/modules/role/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterDepartmentRoutes(rg *gin.RouterGroup, h *handlers.DepartmentHandler, authMware *middlewares.AuthMiddleware) {
+
+	departmentRoutes := rg.Group("/departments")
+	departmentRoutes.Use(authMware.Auth())
+	{
+		departmentRoutes.GET("", h.ListDepartments)
+		departmentRoutes.GET("/:id", h.GetDepartment)
+		departmentRoutes.GET("/:id/members", h.ListMembers)
+	}
+
+	manageRoutes := departmentRoutes.Group("")
+	manageRoutes.Use(authMware.RequirePermission(roleEntities.PermissionDepartmentManage))
+	{
+		manageRoutes.POST("", h.CreateDepartment)
+		manageRoutes.PUT("/:id", h.UpdateDepartment)
+		manageRoutes.DELETE("/:id", h.DeleteDepartment)
+		manageRoutes.POST("/:id/members", h.AddMember)
+		manageRoutes.DELETE("/:id/members/:userId", h.RemoveMember)
+	}
+}
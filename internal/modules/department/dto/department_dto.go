@@ -0,0 +1,15 @@
+package dto
+
+// DepartmentInput creates a department or replaces one on update. Leaving
+// parent_id empty makes it a root, leaving manager_id empty clears the
+// manager.
+type DepartmentInput struct {
+	Name      string  `json:"name" binding:"required,min=2,max=255"`
+	Code      string  `json:"code" binding:"required,min=2,max=50,alphanum"`
+	ParentID  *string `json:"parent_id,omitempty" binding:"omitempty,uuid"`
+	ManagerID *string `json:"manager_id,omitempty" binding:"omitempty,uuid"`
+}
+
+type AddMemberInput struct {
+	UserID string `json:"user_id" binding:"required,uuid"`
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Department is a node in the organisation tree. ManagerID need not be a
+// member; when it is empty, approvals that need a manager go to the nearest
+// ancestor that has one.
+type Department struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID  uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_departments_tenant_code" json:"tenant_id"`
+	Name      string     `gorm:"type:varchar(255);not null" json:"name"`
+	Code      string     `gorm:"type:varchar(50);uniqueIndex:idx_departments_tenant_code;not null" json:"code"`
+	ParentID  *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	ManagerID *uuid.UUID `gorm:"type:uuid;index" json:"manager_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (d *Department) TableName() string {
+	return "departments"
+}
+
+func (d *Department) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// DepartmentMember places a user in a department. A user may belong to
+// several departments.
+type DepartmentMember struct {
+	TenantID     uuid.UUID `gorm:"type:uuid;index" json:"tenant_id"`
+	DepartmentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"department_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (m *DepartmentMember) TableName() string {
+	return "department_members"
+}
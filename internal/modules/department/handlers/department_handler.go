@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/department/dto"
+	"testcase/internal/modules/department/services"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DepartmentHandler struct {
+	departmentService services.DepartmentService
+}
+
+func NewDepartmentHandler(departmentService services.DepartmentService) *DepartmentHandler {
+	return &DepartmentHandler{
+		departmentService: departmentService,
+	}
+}
+
+func (h *DepartmentHandler) ListDepartments(c *gin.Context) {
+	departments, err := h.departmentService.ListDepartments(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, departments, "Departments retrieved successfully", http.StatusOK)
+}
+
+func (h *DepartmentHandler) GetDepartment(c *gin.Context) {
+	department, err := h.departmentService.GetDepartment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, department, "Department retrieved successfully", http.StatusOK)
+}
+
+func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
+	var input dto.DepartmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	department, err := h.departmentService.CreateDepartment(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, department, "Department created successfully", http.StatusCreated)
+}
+
+func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
+	var input dto.DepartmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	department, err := h.departmentService.UpdateDepartment(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, department, "Department updated successfully", http.StatusOK)
+}
+
+func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
+	if err := h.departmentService.DeleteDepartment(c.Request.Context(), c.Param("id")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Department deleted successfully", http.StatusOK)
+}
+
+func (h *DepartmentHandler) ListMembers(c *gin.Context) {
+	members, err := h.departmentService.ListMembers(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, members, "Department members retrieved successfully", http.StatusOK)
+}
+
+func (h *DepartmentHandler) AddMember(c *gin.Context) {
+	var input dto.AddMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	member, err := h.departmentService.AddMember(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, member, "Department member added successfully", http.StatusCreated)
+}
+
+func (h *DepartmentHandler) RemoveMember(c *gin.Context) {
+	if err := h.departmentService.RemoveMember(c.Request.Context(), c.Param("id"), c.Param("userId")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Department member removed successfully", http.StatusOK)
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/department/entities"
+
+	"github.com/google/uuid"
+)
+
+type DepartmentRepository interface {
+	CreateDepartment(ctx context.Context, department *entities.Department) error
+	UpdateDepartment(ctx context.Context, department *entities.Department) error
+	DeleteDepartment(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.Department, error)
+	FindByCode(ctx context.Context, code string) (*entities.Department, error)
+	ListDepartments(ctx context.Context) ([]entities.Department, error)
+	Ancestors(ctx context.Context, id uuid.UUID) ([]entities.Department, error)
+	SubtreeIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	ManagedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	CountChildren(ctx context.Context, id uuid.UUID) (int64, error)
+	CountDocuments(ctx context.Context, id uuid.UUID) (int64, error)
+	AddMember(ctx context.Context, member *entities.DepartmentMember) error
+	RemoveMember(ctx context.Context, departmentID, userID uuid.UUID) error
+	ListMembers(ctx context.Context, departmentID uuid.UUID) ([]entities.DepartmentMember, error)
+	UserDepartmentIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
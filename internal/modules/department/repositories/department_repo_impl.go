@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/department/entities"
+	documentEntities "testcase/internal/modules/document/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxDepartmentDepth bounds the recursive queries in case a cycle slipped
+// into the tree.
+const maxDepartmentDepth = 64
+
+type departmentRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewDepartmentRepository(db *database.Database) DepartmentRepository {
+	return &departmentRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *departmentRepositoryImpl) CreateDepartment(ctx context.Context, department *entities.Department) error {
+	err := r.db.WithContext(ctx).Create(department).Error
+	if err != nil {
+		return fmt.Errorf("failed to create department: %w", err)
+	}
+
+	return nil
+}
+
+func (r *departmentRepositoryImpl) UpdateDepartment(ctx context.Context, department *entities.Department) error {
+	err := r.db.WithContext(ctx).Save(department).Error
+	if err != nil {
+		return fmt.Errorf("failed to update department: %w", err)
+	}
+
+	return nil
+}
+
+func (r *departmentRepositoryImpl) DeleteDepartment(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("department_id = ?", id).Delete(&entities.DepartmentMember{}).Error; err != nil {
+			return fmt.Errorf("failed to delete department members: %w", err)
+		}
+		if err := tx.Where("id = ?", id).Delete(&entities.Department{}).Error; err != nil {
+			return fmt.Errorf("failed to delete department: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *departmentRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*entities.Department, error) {
+	var department entities.Department
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&department).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("department with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to find department by ID: %w", err)
+	}
+
+	return &department, nil
+}
+
+// FindByCode returns nil without an error when no department has the code.
+func (r *departmentRepositoryImpl) FindByCode(ctx context.Context, code string) (*entities.Department, error) {
+	var department entities.Department
+
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&department).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find department by code: %w", err)
+	}
+
+	return &department, nil
+}
+
+func (r *departmentRepositoryImpl) ListDepartments(ctx context.Context) ([]entities.Department, error) {
+	var departments []entities.Department
+
+	err := r.db.WithContext(ctx).Order("name asc").Find(&departments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list departments: %w", err)
+	}
+
+	return departments, nil
+}
+
+// Ancestors returns the department followed by its parents up to the root.
+func (r *departmentRepositoryImpl) Ancestors(ctx context.Context, id uuid.UUID) ([]entities.Department, error) {
+	tenantID, ok := database.TenantFromContext(ctx)
+	if !ok {
+		return nil, database.ErrTenantRequired
+	}
+
+	var departments []entities.Department
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE chain AS (
+			SELECT departments.*, 0 AS depth FROM departments WHERE id = ? AND tenant_id = ?
+			UNION ALL
+			SELECT d.*, chain.depth + 1 FROM departments d
+			JOIN chain ON d.id = chain.parent_id AND d.tenant_id = chain.tenant_id
+			WHERE chain.depth < ?
+		)
+		SELECT * FROM chain ORDER BY depth`, id, tenantID, maxDepartmentDepth).
+		Scan(&departments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load department ancestors: %w", err)
+	}
+
+	return departments, nil
+}
+
+// SubtreeIDs returns the given departments and all of their descendants.
+func (r *departmentRepositoryImpl) SubtreeIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	tenantID, ok := database.TenantFromContext(ctx)
+	if !ok {
+		return nil, database.ErrTenantRequired
+	}
+
+	var subtree []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth FROM departments WHERE id IN ? AND tenant_id = ?
+			UNION ALL
+			SELECT d.id, tree.depth + 1 FROM departments d
+			JOIN tree ON d.parent_id = tree.id
+			WHERE d.tenant_id = ? AND tree.depth < ?
+		)
+		SELECT DISTINCT id FROM tree`, ids, tenantID, tenantID, maxDepartmentDepth).
+		Scan(&subtree).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load department subtree: %w", err)
+	}
+
+	return subtree, nil
+}
+
+func (r *departmentRepositoryImpl) ManagedIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	err := r.db.WithContext(ctx).
+		Model(&entities.Department{}).
+		Where("manager_id = ?", userID).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed departments: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *departmentRepositoryImpl) CountChildren(ctx context.Context, id uuid.UUID) (int64, error) {
+	var total int64
+
+	err := r.db.WithContext(ctx).Model(&entities.Department{}).Where("parent_id = ?", id).Count(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count child departments: %w", err)
+	}
+
+	return total, nil
+}
+
+func (r *departmentRepositoryImpl) CountDocuments(ctx context.Context, id uuid.UUID) (int64, error) {
+	var total int64
+
+	err := r.db.WithContext(ctx).Model(&documentEntities.Document{}).Where("department_id = ?", id).Count(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count department documents: %w", err)
+	}
+
+	return total, nil
+}
+
+func (r *departmentRepositoryImpl) AddMember(ctx context.Context, member *entities.DepartmentMember) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(member).Error
+	if err != nil {
+		return fmt.Errorf("failed to add department member: %w", err)
+	}
+
+	return nil
+}
+
+func (r *departmentRepositoryImpl) RemoveMember(ctx context.Context, departmentID, userID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Where("department_id = ? AND user_id = ?", departmentID, userID).
+		Delete(&entities.DepartmentMember{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to remove department member: %w", err)
+	}
+
+	return nil
+}
+
+func (r *departmentRepositoryImpl) ListMembers(ctx context.Context, departmentID uuid.UUID) ([]entities.DepartmentMember, error) {
+	var members []entities.DepartmentMember
+
+	err := r.db.WithContext(ctx).
+		Where("department_id = ?", departmentID).
+		Order("created_at asc").
+		Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list department members: %w", err)
+	}
+
+	return members, nil
+}
+
+func (r *departmentRepositoryImpl) UserDepartmentIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	err := r.db.WithContext(ctx).
+		Model(&entities.DepartmentMember{}).
+		Where("user_id = ?", userID).
+		Pluck("department_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user departments: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/infrastructures/database/databasetest"
+	"testcase/internal/utils"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestCountDocumentsIsScopedToTenant(t *testing.T) {
+	db, mock := databasetest.NewMock(t)
+	repo := NewDepartmentRepository(db)
+	tenantID := uuid.New()
+	departmentID := uuid.New()
+	ctx := utils.WithTenant(context.Background(), tenantID)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "documents" WHERE department_id = \$1 AND "documents"."tenant_id" = \$2`).
+		WithArgs(departmentID, tenantID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	total, err := repo.CountDocuments(ctx, departmentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2 documents, got %d", total)
+	}
+}
+
+func TestCountDocumentsRequiresTenant(t *testing.T) {
+	db, _ := databasetest.NewMock(t)
+	repo := NewDepartmentRepository(db)
+
+	if _, err := repo.CountDocuments(context.Background(), uuid.New()); !errors.Is(err, database.ErrTenantRequired) {
+		t.Fatalf("expected ErrTenantRequired, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/modules/department/dto"
+	"testcase/internal/modules/department/entities"
+
+	"github.com/google/uuid"
+)
+
+type DepartmentService interface {
+	ListDepartments(ctx context.Context) ([]entities.Department, error)
+	GetDepartment(ctx context.Context, id string) (*entities.Department, error)
+	CreateDepartment(ctx context.Context, input *dto.DepartmentInput) (*entities.Department, error)
+	UpdateDepartment(ctx context.Context, id string, input *dto.DepartmentInput) (*entities.Department, error)
+	DeleteDepartment(ctx context.Context, id string) error
+	ListMembers(ctx context.Context, id string) ([]entities.DepartmentMember, error)
+	AddMember(ctx context.Context, id string, input *dto.AddMemberInput) (*entities.DepartmentMember, error)
+	RemoveMember(ctx context.Context, id, userID string) error
+
+	FindByCode(ctx context.Context, code string) (*entities.Department, error)
+	UserDepartmentIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	VisibleDepartmentIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	IsMemberWithin(ctx context.Context, userID, departmentID uuid.UUID) (bool, error)
+	ResolveManager(ctx context.Context, departmentID uuid.UUID) (*uuid.UUID, error)
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testcase/internal/modules/department/dto"
+	"testcase/internal/modules/department/entities"
+	"testcase/internal/modules/department/repositories"
+	userRepositories "testcase/internal/modules/user/repositories"
+	"testcase/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type departmentServiceImpl struct {
+	departmentRepo repositories.DepartmentRepository
+	userRepo       userRepositories.UserRepository
+}
+
+func NewDepartmentService(departmentRepo repositories.DepartmentRepository, userRepo userRepositories.UserRepository) DepartmentService {
+	return &departmentServiceImpl{
+		departmentRepo: departmentRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (d *departmentServiceImpl) ListDepartments(ctx context.Context) ([]entities.Department, error) {
+	departments, err := d.departmentRepo.ListDepartments(ctx)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return departments, nil
+}
+
+func (d *departmentServiceImpl) GetDepartment(ctx context.Context, id string) (*entities.Department, error) {
+	departmentID, err := parseDepartmentID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	department, err := d.departmentRepo.FindByID(ctx, departmentID)
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrNotFound, err, "Department not found")
+	}
+
+	return department, nil
+}
+
+func (d *departmentServiceImpl) CreateDepartment(ctx context.Context, input *dto.DepartmentInput) (*entities.Department, error) {
+	department := &entities.Department{}
+	if err := d.applyInput(ctx, department, input); err != nil {
+		return nil, err
+	}
+
+	if err := d.departmentRepo.CreateDepartment(ctx, department); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return department, nil
+}
+
+func (d *departmentServiceImpl) UpdateDepartment(ctx context.Context, id string, input *dto.DepartmentInput) (*entities.Department, error) {
+	department, err := d.GetDepartment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.applyInput(ctx, department, input); err != nil {
+		return nil, err
+	}
+
+	if err := d.departmentRepo.UpdateDepartment(ctx, department); err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	return department, nil
+}
+
+// DeleteDepartment removes an empty leaf department. Children and documents
+// must be moved first so nothing is left pointing at it.
+func (d *departmentServiceImpl) DeleteDepartment(ctx context.Context, id string) error {
+	department, err := d.GetDepartment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	children, err := d.departmentRepo.CountChildren(ctx, department.ID)
+	if err != nil {
+		return utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if children > 0 {
+		return utils.NewAppErrorWithMessage(utils.ErrConflict, fmt.Errorf("department %s has %d child departments", department.Code, children), "Department still has child departments")
+	}
+
+	documents, err := d.departmentRepo.CountDocuments(ctx, department.ID)
+	if err != nil {
+		return utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if documents > 0 {
+		return utils.NewAppErrorWithMessage(utils.ErrConflict, fmt.Errorf("department %s has %d documents", department.Code, documents), "Department still has documents")
+	}
+
+	if err := d.departmentRepo.DeleteDepartment(ctx, department.ID); err != nil {
+		return utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return nil
+}
+
+func (d *departmentServiceImpl) ListMembers(ctx context.Context, id string) ([]entities.DepartmentMember, error) {
+	department, err := d.GetDepartment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := d.departmentRepo.ListMembers(ctx, department.ID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return members, nil
+}
+
+func (d *departmentServiceImpl) AddMember(ctx context.Context, id string, input *dto.AddMemberInput) (*entities.DepartmentMember, error) {
+	department, err := d.GetDepartment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := d.existingUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &entities.DepartmentMember{
+		DepartmentID: department.ID,
+		UserID:       userID,
+	}
+	if err := d.departmentRepo.AddMember(ctx, member); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return member, nil
+}
+
+func (d *departmentServiceImpl) RemoveMember(ctx context.Context, id, userID string) error {
+	department, err := d.GetDepartment(ctx, id)
+	if err != nil {
+		return err
+	}
+	memberID, err := uuid.Parse(userID)
+	if err != nil {
+		return utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid user ID: %w", err))
+	}
+
+	if err := d.departmentRepo.RemoveMember(ctx, department.ID, memberID); err != nil {
+		return utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return nil
+}
+
+func (d *departmentServiceImpl) FindByCode(ctx context.Context, code string) (*entities.Department, error) {
+	return d.departmentRepo.FindByCode(ctx, code)
+}
+
+func (d *departmentServiceImpl) UserDepartmentIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return d.departmentRepo.UserDepartmentIDs(ctx, userID)
+}
+
+// VisibleDepartmentIDs returns every department the user belongs to or
+// manages, together with their sub-departments.
+func (d *departmentServiceImpl) VisibleDepartmentIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	memberOf, err := d.departmentRepo.UserDepartmentIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	managed, err := d.departmentRepo.ManagedIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.departmentRepo.SubtreeIDs(ctx, append(memberOf, managed...))
+}
+
+// IsMemberWithin reports whether the user belongs to the department or to
+// one of its ancestors.
+func (d *departmentServiceImpl) IsMemberWithin(ctx context.Context, userID, departmentID uuid.UUID) (bool, error) {
+	memberOf, err := d.departmentRepo.UserDepartmentIDs(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if len(memberOf) == 0 {
+		return false, nil
+	}
+
+	chain, err := d.departmentRepo.Ancestors(ctx, departmentID)
+	if err != nil {
+		return false, err
+	}
+	for _, department := range chain {
+		for _, id := range memberOf {
+			if department.ID == id {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// ResolveManager returns the manager of the department, or of its nearest
+// ancestor that has one. It returns nil when no department up the tree has
+// a manager.
+func (d *departmentServiceImpl) ResolveManager(ctx context.Context, departmentID uuid.UUID) (*uuid.UUID, error) {
+	chain, err := d.departmentRepo.Ancestors(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, department := range chain {
+		if department.ManagerID != nil {
+			return department.ManagerID, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (d *departmentServiceImpl) applyInput(ctx context.Context, department *entities.Department, input *dto.DepartmentInput) error {
+	code := strings.ToUpper(input.Code)
+	existing, err := d.departmentRepo.FindByCode(ctx, code)
+	if err != nil {
+		return utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if existing != nil && existing.ID != department.ID {
+		return utils.NewAppErrorWithMessage(utils.ErrConflict, fmt.Errorf("department code %s already exists", code), "Department code already exists")
+	}
+
+	var parentID *uuid.UUID
+	if input.ParentID != nil && *input.ParentID != "" {
+		parent, err := d.GetDepartment(ctx, *input.ParentID)
+		if err != nil {
+			return err
+		}
+		if department.ID != uuid.Nil {
+			if err := d.checkNoCycle(ctx, department.ID, parent.ID); err != nil {
+				return err
+			}
+		}
+		parentID = &parent.ID
+	}
+
+	var managerID *uuid.UUID
+	if input.ManagerID != nil && *input.ManagerID != "" {
+		id, err := d.existingUserID(ctx, *input.ManagerID)
+		if err != nil {
+			return err
+		}
+		managerID = &id
+	}
+
+	department.Name = input.Name
+	department.Code = code
+	department.ParentID = parentID
+	department.ManagerID = managerID
+
+	return nil
+}
+
+// checkNoCycle rejects moving a department under itself or one of its
+// descendants.
+func (d *departmentServiceImpl) checkNoCycle(ctx context.Context, departmentID, parentID uuid.UUID) error {
+	subtree, err := d.departmentRepo.SubtreeIDs(ctx, []uuid.UUID{departmentID})
+	if err != nil {
+		return utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	for _, id := range subtree {
+		if id == parentID {
+			return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("department %s cannot be its own ancestor", departmentID), "A department cannot be moved under itself or its sub-departments")
+		}
+	}
+
+	return nil
+}
+
+func (d *departmentServiceImpl) existingUserID(ctx context.Context, id string) (uuid.UUID, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid user ID: %w", err))
+	}
+	if _, err := d.userRepo.FindByID(ctx, userID); err != nil {
+		return uuid.Nil, utils.NewAppError(utils.ErrUserNotFound, err)
+	}
+
+	return userID, nil
+}
+
+func parseDepartmentID(id string) (uuid.UUID, error) {
+	departmentID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid department ID: %w", err))
+	}
+
+	return departmentID, nil
+}
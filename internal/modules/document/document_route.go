@@ -3,6 +3,8 @@ package document
 import (
 	"testcase/internal/middlewares"
 	"testcase/internal/modules/document/handlers"
+	roleEntities "testcase/internal/modules/role/entities"
+	"testcase/package/securities"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,12 +12,16 @@ import (
 func RegisterDocumentRoutes(rg *gin.RouterGroup, h *handlers.DocumentHandler, authMware *middlewares.AuthMiddleware) {
 
 	documentRoutes := rg.Group("/documents")
-	documentRoutes.Use(authMware.Auth())
 	{
-		documentRoutes.POST("/", h.CreateDocument)
-		documentRoutes.POST("/:id/action", h.SubmitAction)
-		documentRoutes.GET("/:id", h.GetDocument)
-		documentRoutes.PUT("/:id", h.ResubmitAction)
-		documentRoutes.GET("/", h.ListDocuments)
+		documentRoutes.POST("/", authMware.Auth(securities.APIScopeDocumentsWrite), authMware.RequirePermission(roleEntities.PermissionDocumentCreate), h.CreateDocument)
+		documentRoutes.POST("/:id/action",
+			authMware.Auth(securities.APIScopeDocumentsAction),
+			authMware.RequireVerifiedEmail(),
+			authMware.RequireStepUp(securities.StepUpScopeDocumentAction, h.RequiresStepUp),
+			h.SubmitAction,
+		)
+		documentRoutes.GET("/:id", authMware.Auth(securities.APIScopeDocumentsRead), authMware.RequirePermission(roleEntities.PermissionDocumentRead), h.GetDocument)
+		documentRoutes.PUT("/:id", authMware.Auth(securities.APIScopeDocumentsWrite), authMware.RequirePermission(roleEntities.PermissionDocumentResubmit), h.ResubmitAction)
+		documentRoutes.GET("/", authMware.Auth(securities.APIScopeDocumentsRead), authMware.RequirePermission(roleEntities.PermissionDocumentRead), h.ListDocuments)
 	}
 }
@@ -3,7 +3,8 @@ package dto
 import "testcase/internal/modules/document/entities"
 
 type CreateDocumentDTO struct {
-	Title string `json:"title" binding:"required"`
+	Title        string  `json:"title" binding:"required"`
+	DepartmentID *string `json:"department_id,omitempty" binding:"omitempty,uuid"`
 }
 
 type UpdateDocumentDTO struct {
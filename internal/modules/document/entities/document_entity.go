@@ -24,9 +24,14 @@ const (
 
 type Document struct {
 	ID              uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID        uuid.UUID      `gorm:"type:uuid;index" json:"tenant_id"`
 	Title           string         `gorm:"not null" json:"title"`
 	Status          DocumentStatus `gorm:"default:'pending'" json:"status"`
 	CurrentApprover int            `gorm:"default:1" json:"current_approver"`
+	DepartmentID    *uuid.UUID     `gorm:"type:uuid;index" json:"department_id"`
+	SubmittedBy     *uuid.UUID     `gorm:"type:uuid;index" json:"submitted_by"`
+	// Revision counts submissions: 1 when created, one more per resubmit.
+	Revision int `gorm:"not null;default:1" json:"revision"`
 
 	Approver1Action  *DocumentAction `json:"approver1_action"`
 	Approver1Comment *string         `gorm:"type:text" json:"approver1_comment"`
@@ -42,10 +47,37 @@ type Document struct {
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// DueAt and Overdue follow from the organization's SLA and are not
+	// stored.
+	DueAt   *time.Time `gorm:"-" json:"due_at,omitempty"`
+	Overdue bool       `gorm:"-" json:"overdue"`
+}
+
+// IsOpen reports whether the document still waits on an approver.
+func (d *Document) IsOpen() bool {
+	return d.Status != StatusApproved && d.Status != StatusRejected
+}
+
+// StepStartedAt returns when the open step began: the previous approval,
+// or submission and resubmission for the first step.
+func (d *Document) StepStartedAt() time.Time {
+	switch {
+	case d.CurrentApprover == 3 && d.Approver2Date != nil:
+		return *d.Approver2Date
+	case d.CurrentApprover == 2 && d.Approver1Date != nil:
+		return *d.Approver1Date
+	case d.Status == StatusNeedRevision:
+		return d.UpdatedAt
+	default:
+		return d.CreatedAt
+	}
 }
 
 func (d *Document) BeforeCreate(tx *gorm.DB) (err error) {
-	d.ID = uuid.New()
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
 	return
 }
 
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"encoding/json"
+	"testcase/internal/modules/document/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types raised by the approval workflow. Approving the last step
+// raises StepApproved followed by DocumentApproved.
+const (
+	DocumentCreated     = "document.created"
+	StepApproved        = "document.step_approved"
+	DocumentApproved    = "document.approved"
+	DocumentRejected    = "document.rejected"
+	DocumentResubmitted = "document.resubmitted"
+)
+
+// Types lists every document event type.
+var Types = []string{
+	DocumentCreated,
+	StepApproved,
+	DocumentApproved,
+	DocumentRejected,
+	DocumentResubmitted,
+}
+
+// DocumentEvent is the payload of every document event. Step is the step
+// acted on; CurrentStep is the step the document waits on afterwards.
+type DocumentEvent struct {
+	DocumentID   uuid.UUID               `json:"document_id"`
+	Title        string                  `json:"title"`
+	Status       entities.DocumentStatus `json:"status"`
+	Step         int                     `json:"step,omitempty"`
+	CurrentStep  int                     `json:"current_step"`
+	Revision     int                     `json:"revision"`
+	DepartmentID *uuid.UUID              `json:"department_id,omitempty"`
+	SubmittedBy  *uuid.UUID              `json:"submitted_by,omitempty"`
+	ActorID      *uuid.UUID              `json:"actor_id,omitempty"`
+	Comment      *string                 `json:"comment,omitempty"`
+	OccurredAt   time.Time               `json:"occurred_at"`
+}
+
+// New builds an outbox event describing document after actorID acted on
+// step. Pass a zero step and nil comment for events not tied to a step.
+func New(eventType string, document *entities.Document, actorID uuid.UUID, step int, comment *string) (*outboxEntities.OutboxEvent, error) {
+	payload := DocumentEvent{
+		DocumentID:   document.ID,
+		Title:        document.Title,
+		Status:       document.Status,
+		Step:         step,
+		CurrentStep:  document.CurrentApprover,
+		Revision:     document.Revision,
+		DepartmentID: document.DepartmentID,
+		SubmittedBy:  document.SubmittedBy,
+		Comment:      comment,
+		OccurredAt:   time.Now(),
+	}
+	if actorID != uuid.Nil {
+		payload.ActorID = &actorID
+	}
+
+	return outboxEntities.NewOutboxEvent(eventType, document.ID, payload)
+}
+
+// Decode reads the payload of a document event.
+func Decode(event *outboxEntities.OutboxEvent) (*DocumentEvent, error) {
+	var payload DocumentEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
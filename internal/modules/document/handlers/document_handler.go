@@ -100,3 +100,7 @@ func (h *DocumentHandler) ListDocuments(c *gin.Context) {
 
 	utils.SuccessResponse(c, list, "Documents retrieved successfully", http.StatusOK)
 }
+
+func (h *DocumentHandler) RequiresStepUp(c *gin.Context) (bool, error) {
+	return h.documentService.RequiresStepUp(c.Request.Context(), c.Param("id"))
+}
@@ -2,13 +2,71 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"testcase/internal/helpers"
 	"testcase/internal/modules/document/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+
+	"github.com/google/uuid"
 )
 
 type DocumentRepo interface {
-	FindById(id string) (*entities.Document, error)
-	CreateDocument(ctx context.Context, doc *entities.Document) error
-	UpdateDocument(ctx context.Context, doc *entities.Document) error
-	ListDocuments(ctx context.Context, params *helpers.PaginationParams) ([]entities.Document, int64, error)
+	FindById(ctx context.Context, id string) (*entities.Document, error)
+	// CreateDocument and UpdateDocument write events to the outbox in the
+	// same transaction as the document. UpdateDocument only saves doc while
+	// it is still at the status and step the caller read, and returns
+	// ErrDocumentChanged without writing the events otherwise.
+	CreateDocument(ctx context.Context, doc *entities.Document, events ...*outboxEntities.OutboxEvent) error
+	UpdateDocument(ctx context.Context, doc *entities.Document, from DocumentState, events ...*outboxEntities.OutboxEvent) error
+	ListDocuments(ctx context.Context, params *helpers.PaginationParams, scope *DocumentScope) ([]entities.Document, int64, error)
+}
+
+// ErrDocumentChanged means another request moved the document on since it
+// was read.
+var ErrDocumentChanged = errors.New("document was changed by another request")
+
+// DocumentState is the part of a document a workflow action starts from.
+type DocumentState struct {
+	Status          entities.DocumentStatus
+	CurrentApprover int
+}
+
+// StateOf returns the state doc is in now, before an action changes it.
+func StateOf(doc *entities.Document) DocumentState {
+	return DocumentState{Status: doc.Status, CurrentApprover: doc.CurrentApprover}
+}
+
+// DocumentScope limits the documents a caller can see. A nil scope sees
+// every document. Otherwise the caller sees what they submitted, documents
+// without a department, documents of DepartmentIDs, and open documents
+// waiting on one of OpenSteps.
+type DocumentScope struct {
+	UserID        uuid.UUID
+	DepartmentIDs []uuid.UUID
+	OpenSteps     []int
+}
+
+func (s *DocumentScope) Allows(doc *entities.Document) bool {
+	if s == nil {
+		return true
+	}
+	if doc.SubmittedBy != nil && *doc.SubmittedBy == s.UserID {
+		return true
+	}
+	if doc.DepartmentID == nil {
+		return true
+	}
+	for _, id := range s.DepartmentIDs {
+		if id == *doc.DepartmentID {
+			return true
+		}
+	}
+	if doc.IsOpen() {
+		for _, step := range s.OpenSteps {
+			if step == doc.CurrentApprover {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -7,6 +7,7 @@ import (
 	"testcase/internal/helpers"
 	"testcase/internal/infrastructures/database"
 	"testcase/internal/modules/document/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
 
 	"gorm.io/gorm"
 )
@@ -21,10 +22,10 @@ func NewDocumentRepository(db *database.Database) DocumentRepo {
 	}
 }
 
-func (r *documentRepositoryImpl) FindById(id string) (*entities.Document, error) {
+func (r *documentRepositoryImpl) FindById(ctx context.Context, id string) (*entities.Document, error) {
 	var doc entities.Document
 
-	err := r.db.Where("id = ?", id).First(&doc).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&doc).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("document with ID %s not found", id)
@@ -35,8 +36,13 @@ func (r *documentRepositoryImpl) FindById(id string) (*entities.Document, error)
 	return &doc, nil
 }
 
-func (r *documentRepositoryImpl) CreateDocument(ctx context.Context, doc *entities.Document) error {
-	err := r.db.WithContext(ctx).Create(doc).Error
+func (r *documentRepositoryImpl) CreateDocument(ctx context.Context, doc *entities.Document, events ...*outboxEntities.OutboxEvent) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(doc).Error; err != nil {
+			return err
+		}
+		return writeEvents(tx, events)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create document: %w", err)
 	}
@@ -44,8 +50,20 @@ func (r *documentRepositoryImpl) CreateDocument(ctx context.Context, doc *entiti
 	return nil
 }
 
-func (r *documentRepositoryImpl) UpdateDocument(ctx context.Context, doc *entities.Document) error {
-	err := r.db.WithContext(ctx).Save(doc).Error
+func (r *documentRepositoryImpl) UpdateDocument(ctx context.Context, doc *entities.Document, from DocumentState, events ...*outboxEntities.OutboxEvent) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(doc).
+			Where("status = ? AND current_approver = ?", from.Status, from.CurrentApprover).
+			Select("*").
+			Updates(doc)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDocumentChanged
+		}
+		return writeEvents(tx, events)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update document: %w", err)
 	}
@@ -53,12 +71,30 @@ func (r *documentRepositoryImpl) UpdateDocument(ctx context.Context, doc *entiti
 	return nil
 }
 
-func (r *documentRepositoryImpl) ListDocuments(ctx context.Context, params *helpers.PaginationParams) ([]entities.Document, int64, error) {
+func writeEvents(tx *gorm.DB, events []*outboxEntities.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(events).Error
+}
+
+func (r *documentRepositoryImpl) ListDocuments(ctx context.Context, params *helpers.PaginationParams, scope *DocumentScope) ([]entities.Document, int64, error) {
 	var docs []entities.Document
 	var total int64
 
 	query := r.db.WithContext(ctx).Model(&entities.Document{})
 
+	if scope != nil {
+		visible := r.db.Where("submitted_by = ?", scope.UserID).Or("department_id IS NULL")
+		if len(scope.DepartmentIDs) > 0 {
+			visible = visible.Or("department_id IN ?", scope.DepartmentIDs)
+		}
+		if len(scope.OpenSteps) > 0 {
+			visible = visible.Or("(current_approver IN ? AND status NOT IN ?)", scope.OpenSteps, []entities.DocumentStatus{entities.StatusApproved, entities.StatusRejected})
+		}
+		query = query.Where(visible)
+	}
+
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count documents: %w", err)
 	}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testcase/internal/infrastructures/database/databasetest"
+	"testcase/internal/modules/document/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	"testcase/internal/utils"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func documentAfterFirstApproval(tenantID uuid.UUID) *entities.Document {
+	return &entities.Document{
+		ID:              uuid.New(),
+		TenantID:        tenantID,
+		Title:           "Budget",
+		Status:          entities.StatusPending,
+		CurrentApprover: 2,
+		Revision:        1,
+	}
+}
+
+func TestUpdateDocumentWritesEventsAfterConditionalUpdate(t *testing.T) {
+	db, mock := databasetest.NewMock(t)
+	repo := NewDocumentRepository(db)
+	tenantID := uuid.New()
+	ctx := utils.WithTenant(context.Background(), tenantID)
+	doc := documentAfterFirstApproval(tenantID)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "documents" SET .* WHERE \(status = \$\d+ AND current_approver = \$\d+\) AND "documents"."tenant_id" = \$\d+ AND "id" = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "outbox_events"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	event := &outboxEntities.OutboxEvent{Type: "document.approved"}
+	from := DocumentState{Status: entities.StatusPending, CurrentApprover: 1}
+	if err := repo.UpdateDocument(ctx, doc, from, event); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateDocumentRejectsChangedDocument(t *testing.T) {
+	db, mock := databasetest.NewMock(t)
+	repo := NewDocumentRepository(db)
+	tenantID := uuid.New()
+	ctx := utils.WithTenant(context.Background(), tenantID)
+	doc := documentAfterFirstApproval(tenantID)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "documents" SET`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	event := &outboxEntities.OutboxEvent{Type: "document.approved"}
+	from := DocumentState{Status: entities.StatusPending, CurrentApprover: 1}
+	if err := repo.UpdateDocument(ctx, doc, from, event); !errors.Is(err, ErrDocumentChanged) {
+		t.Fatalf("expected ErrDocumentChanged, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	roleEntities "testcase/internal/modules/role/entities"
+	settingEntities "testcase/internal/modules/setting/entities"
+)
+
+const (
+	// ApprovalRulePermission lets anyone whose role grants the step's
+	// approve permission act, provided they belong to the document's
+	// department or one above it.
+	ApprovalRulePermission = "permission"
+	// ApprovalRuleDepartmentManager sends the step to the manager of the
+	// document's department, walking up the tree until one is found.
+	ApprovalRuleDepartmentManager = "department_manager"
+	// ApprovalRuleDepartmentRole requires a given role within a department,
+	// written "role:<name>" or "role:<name>@<department code>".
+	ApprovalRuleDepartmentRole = "role"
+)
+
+type ApprovalRule struct {
+	Kind           string
+	Role           string
+	DepartmentCode string
+}
+
+// ApprovalRules maps an approver level to its rule.
+type ApprovalRules map[int]ApprovalRule
+
+// ParseApprovalRules reads APPROVAL_STEP_RULES entries or an organization's
+// approval.step_rules setting.
+func ParseApprovalRules(raw map[string]string) (ApprovalRules, error) {
+	rules := make(ApprovalRules, len(raw))
+	for key, value := range raw {
+		level, err := strconv.Atoi(key)
+		if err != nil || level < 1 || level > roleEntities.ApprovalSteps {
+			return nil, fmt.Errorf("invalid approval step %q", key)
+		}
+
+		rule, err := parseApprovalRule(value)
+		if err != nil {
+			return nil, fmt.Errorf("approval step %d: %w", level, err)
+		}
+		rules[level] = rule
+	}
+
+	return rules, nil
+}
+
+// ValidateApprovalSettings rejects organization settings whose step rules
+// can't be parsed.
+func ValidateApprovalSettings(settings *settingEntities.Settings) error {
+	if _, err := ParseApprovalRules(settings.Approval.StepRules); err != nil {
+		return fmt.Errorf("approval.step_rules: %w", err)
+	}
+	return nil
+}
+
+func parseApprovalRule(value string) (ApprovalRule, error) {
+	switch {
+	case value == ApprovalRulePermission:
+		return ApprovalRule{Kind: ApprovalRulePermission}, nil
+	case value == ApprovalRuleDepartmentManager:
+		return ApprovalRule{Kind: ApprovalRuleDepartmentManager}, nil
+	case strings.HasPrefix(value, ApprovalRuleDepartmentRole+":"):
+		role, code, _ := strings.Cut(strings.TrimPrefix(value, ApprovalRuleDepartmentRole+":"), "@")
+		if role == "" {
+			return ApprovalRule{}, fmt.Errorf("rule %q names no role", value)
+		}
+		return ApprovalRule{
+			Kind:           ApprovalRuleDepartmentRole,
+			Role:           role,
+			DepartmentCode: strings.ToUpper(code),
+		}, nil
+	default:
+		return ApprovalRule{}, fmt.Errorf("unknown rule %q", value)
+	}
+}
+
+// ForLevel returns the rule for the approver level, defaulting to the
+// permission rule.
+func (r ApprovalRules) ForLevel(level int) ApprovalRule {
+	if rule, ok := r[level]; ok {
+		return rule
+	}
+	return ApprovalRule{Kind: ApprovalRulePermission}
+}
@@ -5,6 +5,8 @@ import (
 	"testcase/internal/helpers"
 	"testcase/internal/modules/document/dto"
 	"testcase/internal/modules/document/entities"
+
+	"github.com/google/uuid"
 )
 
 type DocumentService interface {
@@ -13,4 +15,18 @@ type DocumentService interface {
 	SubmitAction(ctx context.Context, id string, action *dto.UpdateDocumentDTO) (*entities.Document, error)
 	ResubmitAction(ctx context.Context, id string) (*entities.Document, error)
 	PaginateDocument(ctx context.Context, params *helpers.PaginationParams) ([]entities.Document, int64, error)
+	RequiresStepUp(ctx context.Context, id string) (bool, error)
+	StepApprovers(ctx context.Context, document *entities.Document) (*StepApprovers, error)
+	// VisibleTo reports whether the user with role could read document,
+	// by the same rules FindById applies to the caller.
+	VisibleTo(ctx context.Context, document *entities.Document, userID uuid.UUID, role string) (bool, error)
+}
+
+// StepApprovers describes who may act on a document's open step: active
+// users holding one of Roles who belong to DepartmentID or a department
+// above it (any department when nil), plus the users in UserIDs.
+type StepApprovers struct {
+	Roles        []string
+	DepartmentID *uuid.UUID
+	UserIDs      []uuid.UUID
 }
@@ -2,38 +2,74 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"testcase/config"
 	"testcase/internal/helpers"
+	departmentServices "testcase/internal/modules/department/services"
 	"testcase/internal/modules/document/dto"
 	"testcase/internal/modules/document/entities"
+	documentEvents "testcase/internal/modules/document/events"
 	"testcase/internal/modules/document/repositories"
-	userEntity "testcase/internal/modules/user/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	roleEntities "testcase/internal/modules/role/entities"
+	settingServices "testcase/internal/modules/setting/services"
 	"testcase/internal/utils"
+	"testcase/package/securities"
+
+	"github.com/google/uuid"
 )
 
 type documentServiceImpl struct {
-	repo repositories.DocumentRepo
+	repo        repositories.DocumentRepo
+	permissions securities.PermissionChecker
+	departments departmentServices.DepartmentService
+	settings    settingServices.SettingService
+	config      *config.Config
 }
 
-func NewDocumentService(repo repositories.DocumentRepo) DocumentService {
+func NewDocumentService(repo repositories.DocumentRepo, permissions securities.PermissionChecker, departments departmentServices.DepartmentService, settings settingServices.SettingService, cfg *config.Config) DocumentService {
 	return &documentServiceImpl{
-		repo: repo,
+		repo:        repo,
+		permissions: permissions,
+		departments: departments,
+		settings:    settings,
+		config:      cfg,
 	}
 }
 
 func (d *documentServiceImpl) CreateDocument(ctx context.Context, input *dto.CreateDocumentDTO) (*entities.Document, error) {
+	userID, _ := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+	departmentID, err := d.submissionDepartment(ctx, userID, input.DepartmentID)
+	if err != nil {
+		return nil, err
+	}
+
 	document := &entities.Document{
+		ID:              uuid.New(),
 		Title:           input.Title,
 		Status:          entities.StatusPending,
 		CurrentApprover: 1,
+		DepartmentID:    departmentID,
+		Revision:        1,
 		CreatedAt:       time.Now(),
 	}
+	if userID != uuid.Nil {
+		document.SubmittedBy = &userID
+	}
 
-	if err := d.repo.CreateDocument(ctx, document); err != nil {
+	created, err := documentEvents.New(documentEvents.DocumentCreated, document, userID, 0, nil)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	if err := d.repo.CreateDocument(ctx, document, created); err != nil {
 		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("failed to create document: %w", err))
 	}
+	if err := d.applySLA(ctx, document); err != nil {
+		return nil, err
+	}
 
 	return document, nil
 }
@@ -43,11 +79,24 @@ func (d *documentServiceImpl) FindById(ctx context.Context, id string) (*entitie
 		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("document ID is required"))
 	}
 
-	document, err := d.repo.FindById(id)
+	document, err := d.repo.FindById(ctx, id)
 	if err != nil {
 		return nil, utils.NewAppError(utils.ErrNotFound, fmt.Errorf("document not found: %w", err))
 	}
 
+	// Documents outside the caller's scope are reported as missing so their
+	// existence is not leaked.
+	scope, err := d.documentScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.Allows(document) {
+		return nil, utils.NewAppError(utils.ErrNotFound, fmt.Errorf("document %s is outside the caller's departments", id))
+	}
+	if err := d.applySLA(ctx, document); err != nil {
+		return nil, err
+	}
+
 	return document, nil
 }
 
@@ -61,17 +110,30 @@ func (d *documentServiceImpl) SubmitAction(ctx context.Context, id string, input
 	if err != nil {
 		return nil, err
 	}
-	if !d.validateRoleApproval(document.CurrentApprover, role) {
+	allowed, err := d.canApprove(ctx, document)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("failed to check approval permission: %w", err))
+	}
+	if !allowed {
 		return nil, utils.NewAppError(utils.ErrForbiddenAccess, fmt.Errorf("user role %s not authorized for approver level %d", role, document.CurrentApprover))
 	}
 	if err := d.validateDocumentState(document); err != nil {
 		return nil, err
 	}
+	from := repositories.StateOf(document)
+	step := document.CurrentApprover
 	if err := d.processApprovalAction(document, input); err != nil {
 		return nil, err
 	}
-	if err := d.repo.UpdateDocument(ctx, document); err != nil {
-		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("failed to update document: %w", err))
+	events, err := d.actionEvents(ctx, document, step, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.repo.UpdateDocument(ctx, document, from, events...); err != nil {
+		return nil, updateDocumentError(err)
+	}
+	if err := d.applySLA(ctx, document); err != nil {
+		return nil, err
 	}
 	return document, nil
 }
@@ -86,10 +148,12 @@ func (d *documentServiceImpl) ResubmitAction(ctx context.Context, id string) (*e
 		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("only rejected documents can be resubmitted"))
 	}
 
+	from := repositories.StateOf(document)
 	now := time.Now()
 
 	document.Status = entities.StatusNeedRevision
 	document.CurrentApprover = 1
+	document.Revision++
 	document.UpdatedAt = now
 	document.Approver1Action = nil
 	document.Approver1Comment = nil
@@ -101,13 +165,30 @@ func (d *documentServiceImpl) ResubmitAction(ctx context.Context, id string) (*e
 	document.Approver3Comment = nil
 	document.Approver3Date = nil
 
-	if err := d.repo.UpdateDocument(ctx, document); err != nil {
-		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("failed to resubmit document: %w", err))
+	userID, _ := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+	resubmitted, err := documentEvents.New(documentEvents.DocumentResubmitted, document, userID, 0, nil)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	if err := d.repo.UpdateDocument(ctx, document, from, resubmitted); err != nil {
+		return nil, updateDocumentError(err)
+	}
+	if err := d.applySLA(ctx, document); err != nil {
+		return nil, err
 	}
 
 	return document, nil
 }
 
+// updateDocumentError reports a lost race with another approver as a
+// conflict so the client reloads the document.
+func updateDocumentError(err error) error {
+	if errors.Is(err, repositories.ErrDocumentChanged) {
+		return utils.NewAppErrorWithMessage(utils.ErrConflict, err, "Document was changed by someone else, reload it and try again")
+	}
+	return utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("failed to update document: %w", err))
+}
+
 func (d *documentServiceImpl) validateSubmitActionInput(id string, input *dto.UpdateDocumentDTO) error {
 	if id == "" {
 		return utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("document ID is required"))
@@ -153,19 +234,6 @@ func (d *documentServiceImpl) processApprovalAction(document *entities.Document,
 	}
 }
 
-func (d *documentServiceImpl) validateRoleApproval(currentApprover int, userRole string) bool {
-	approverRoleMap := map[int]userEntity.RoleEnum{
-		1: userEntity.RoleAdmin1,
-		2: userEntity.RoleAdmin2,
-		3: userEntity.RoleAdmin3,
-	}
-	requiredRole, exists := approverRoleMap[currentApprover]
-	if !exists {
-		return false
-	}
-	return string(requiredRole) == userRole
-}
-
 func (d *documentServiceImpl) processRejection(document *entities.Document, comment *string, timestamp *time.Time) error {
 	document.Status = entities.StatusRejected
 
@@ -230,11 +298,297 @@ func (d *documentServiceImpl) processApproval(document *entities.Document, comme
 	return nil
 }
 
+// actionEvents describes an approval action taken on step.
+func (d *documentServiceImpl) actionEvents(ctx context.Context, document *entities.Document, step int, input *dto.UpdateDocumentDTO) ([]*outboxEntities.OutboxEvent, error) {
+	userID, _ := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+
+	eventTypes := []string{documentEvents.StepApproved}
+	switch {
+	case input.Action == entities.ActionReject:
+		eventTypes = []string{documentEvents.DocumentRejected}
+	case document.Status == entities.StatusApproved:
+		eventTypes = append(eventTypes, documentEvents.DocumentApproved)
+	}
+
+	events := make([]*outboxEntities.OutboxEvent, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		event, err := documentEvents.New(eventType, document, userID, step, input.Comment)
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrInternalServer, err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
 func (d *documentServiceImpl) PaginateDocument(ctx context.Context, params *helpers.PaginationParams) ([]entities.Document, int64, error) {
-	documents, total, err := d.repo.ListDocuments(ctx, params)
+	scope, err := d.documentScope(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	documents, total, err := d.repo.ListDocuments(ctx, params, scope)
 	if err != nil {
 		return nil, 0, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("failed to paginate documents: %w", err))
 	}
+	for i := range documents {
+		if err := d.applySLA(ctx, &documents[i]); err != nil {
+			return nil, 0, err
+		}
+	}
 
 	return documents, total, nil
 }
+
+func (d *documentServiceImpl) RequiresStepUp(ctx context.Context, id string) (bool, error) {
+	settings, err := d.settings.ForTenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(settings.Approval.StepUpLevels) == 0 {
+		return false, nil
+	}
+
+	document, err := d.FindById(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return settings.Approval.StepUpRequiredForLevel(document.CurrentApprover), nil
+}
+
+// applySLA sets the deadline of the document's open step from the
+// organization's SLA. Documents stay without a deadline when no SLA is set.
+func (d *documentServiceImpl) applySLA(ctx context.Context, document *entities.Document) error {
+	settings, err := d.settings.ForTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	stepDuration := settings.SLA.ApprovalStepDuration()
+	if stepDuration <= 0 || !document.IsOpen() {
+		return nil
+	}
+
+	dueAt := document.StepStartedAt().Add(stepDuration)
+	document.DueAt = &dueAt
+	document.Overdue = time.Now().After(dueAt)
+
+	return nil
+}
+
+// approvalRules returns the approver rules of the caller's organization.
+func (d *documentServiceImpl) approvalRules(ctx context.Context) (ApprovalRules, error) {
+	settings, err := d.settings.ForTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := ParseApprovalRules(settings.Approval.StepRules)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("invalid approval rules: %w", err))
+	}
+
+	return rules, nil
+}
+
+// submissionDepartment resolves the department a new document belongs to.
+// The submitter must be a member of the requested department or one above
+// it. Without a request, a submitter in exactly one department files there.
+func (d *documentServiceImpl) submissionDepartment(ctx context.Context, userID uuid.UUID, requested *string) (*uuid.UUID, error) {
+	if requested == nil || *requested == "" {
+		memberOf, err := d.departments.UserDepartmentIDs(ctx, userID)
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+		}
+		if len(memberOf) != 1 {
+			return nil, nil
+		}
+		return &memberOf[0], nil
+	}
+
+	departmentID, err := uuid.Parse(*requested)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid department ID: %w", err))
+	}
+	member, err := d.departments.IsMemberWithin(ctx, userID, departmentID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if !member {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrForbiddenAccess, fmt.Errorf("user %s is not a member of department %s", userID, departmentID), "You can only submit documents to your own departments")
+	}
+
+	return &departmentID, nil
+}
+
+func (d *documentServiceImpl) VisibleTo(ctx context.Context, document *entities.Document, userID uuid.UUID, role string) (bool, error) {
+	canRead, err := d.permissions.RoleHasPermissions(ctx, role, roleEntities.PermissionDocumentRead)
+	if err != nil || !canRead {
+		return false, err
+	}
+
+	ctx = context.WithValue(ctx, utils.UserIDContextKey, userID)
+	ctx = context.WithValue(ctx, utils.RoleContextKey, role)
+	scope, err := d.documentScope(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return scope.Allows(document), nil
+}
+
+// documentScope returns the caller's visibility scope, or nil when their
+// role may read every document.
+func (d *documentServiceImpl) documentScope(ctx context.Context) (*repositories.DocumentScope, error) {
+	role, _ := ctx.Value(utils.RoleContextKey).(string)
+	userID, _ := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+
+	readAll, err := d.permissions.RoleHasPermissions(ctx, role, roleEntities.PermissionDocumentReadAll)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("failed to check read permission: %w", err))
+	}
+	if readAll {
+		return nil, nil
+	}
+
+	departmentIDs, err := d.departments.VisibleDepartmentIDs(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	openSteps, err := d.fixedDepartmentSteps(ctx, role, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return &repositories.DocumentScope{
+		UserID:        userID,
+		DepartmentIDs: departmentIDs,
+		OpenSteps:     openSteps,
+	}, nil
+}
+
+// fixedDepartmentSteps lists the steps the caller approves through a
+// "role:<name>@<code>" rule. Such approvers act on documents from any
+// department, so they must see those documents while the step is open.
+func (d *documentServiceImpl) fixedDepartmentSteps(ctx context.Context, role string, userID uuid.UUID) ([]int, error) {
+	approvalRules, err := d.approvalRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []int
+	for level := 1; level <= roleEntities.ApprovalSteps; level++ {
+		rule := approvalRules.ForLevel(level)
+		if rule.Kind != ApprovalRuleDepartmentRole || rule.DepartmentCode == "" || rule.Role != role {
+			continue
+		}
+		department, err := d.departments.FindByCode(ctx, rule.DepartmentCode)
+		if err != nil {
+			return nil, err
+		}
+		if department == nil {
+			continue
+		}
+		member, err := d.departments.IsMemberWithin(ctx, userID, department.ID)
+		if err != nil {
+			return nil, err
+		}
+		if member {
+			steps = append(steps, level)
+		}
+	}
+
+	return steps, nil
+}
+
+// StepApprovers inverts canApprove for the document's current step.
+func (d *documentServiceImpl) StepApprovers(ctx context.Context, document *entities.Document) (*StepApprovers, error) {
+	approvalRules, err := d.approvalRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rule := approvalRules.ForLevel(document.CurrentApprover)
+
+	switch rule.Kind {
+	case ApprovalRuleDepartmentManager:
+		if document.DepartmentID != nil {
+			managerID, err := d.departments.ResolveManager(ctx, *document.DepartmentID)
+			if err != nil || managerID == nil {
+				return &StepApprovers{}, err
+			}
+			return &StepApprovers{UserIDs: []uuid.UUID{*managerID}}, nil
+		}
+	case ApprovalRuleDepartmentRole:
+		departmentID := document.DepartmentID
+		if rule.DepartmentCode != "" {
+			department, err := d.departments.FindByCode(ctx, rule.DepartmentCode)
+			if err != nil {
+				return nil, err
+			}
+			if department == nil {
+				return nil, fmt.Errorf("approval rule names unknown department %s", rule.DepartmentCode)
+			}
+			departmentID = &department.ID
+		}
+		return &StepApprovers{Roles: []string{rule.Role}, DepartmentID: departmentID}, nil
+	}
+
+	roles, err := d.permissions.RolesWithPermission(ctx, roleEntities.ApproveStepPermission(document.CurrentApprover))
+	if err != nil {
+		return nil, err
+	}
+
+	return &StepApprovers{Roles: roles, DepartmentID: document.DepartmentID}, nil
+}
+
+// canApprove applies the configured rule for the document's current step.
+// Rules that need a department fall back to the permission rule when the
+// document has none.
+func (d *documentServiceImpl) canApprove(ctx context.Context, document *entities.Document) (bool, error) {
+	role, _ := ctx.Value(utils.RoleContextKey).(string)
+	userID, _ := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+	approvalRules, err := d.approvalRules(ctx)
+	if err != nil {
+		return false, err
+	}
+	rule := approvalRules.ForLevel(document.CurrentApprover)
+
+	switch rule.Kind {
+	case ApprovalRuleDepartmentManager:
+		if document.DepartmentID != nil {
+			managerID, err := d.departments.ResolveManager(ctx, *document.DepartmentID)
+			if err != nil {
+				return false, err
+			}
+			return managerID != nil && *managerID == userID, nil
+		}
+	case ApprovalRuleDepartmentRole:
+		if role != rule.Role {
+			return false, nil
+		}
+		departmentID := document.DepartmentID
+		if rule.DepartmentCode != "" {
+			department, err := d.departments.FindByCode(ctx, rule.DepartmentCode)
+			if err != nil {
+				return false, err
+			}
+			if department == nil {
+				return false, fmt.Errorf("approval rule names unknown department %s", rule.DepartmentCode)
+			}
+			departmentID = &department.ID
+		}
+		if departmentID == nil {
+			return true, nil
+		}
+		return d.departments.IsMemberWithin(ctx, userID, *departmentID)
+	}
+
+	allowed, err := d.permissions.RoleHasPermissions(ctx, role, roleEntities.ApproveStepPermission(document.CurrentApprover))
+	if err != nil || !allowed || document.DepartmentID == nil {
+		return allowed, err
+	}
+
+	return d.departments.IsMemberWithin(ctx, userID, *document.DepartmentID)
+}
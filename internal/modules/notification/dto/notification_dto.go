@@ -0,0 +1,13 @@
+package dto
+
+// PreferenceInput sets the channels for one notification type. A channel
+// left out keeps its current setting.
+type PreferenceInput struct {
+	Type  string `json:"type" binding:"required"`
+	Email *bool  `json:"email"`
+	InApp *bool  `json:"in_app"`
+}
+
+type UpdatePreferencesInput struct {
+	Preferences []PreferenceInput `json:"preferences" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailNotification records an email sent for an event, so a redelivered
+// event doesn't mail the same user twice.
+type EmailNotification struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID uuid.UUID `gorm:"type:uuid;index" json:"tenant_id"`
+	EventID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_email_notifications_event" json:"event_id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_email_notifications_event" json:"user_id"`
+	Template string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_email_notifications_event" json:"template"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+func (n *EmailNotification) TableName() string {
+	return "email_notifications"
+}
+
+func (n *EmailNotification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+// EmailData is what the notification templates can use.
+type EmailData struct {
+	Brand        string
+	LogoURL      string
+	PrimaryColor string
+	Name         string
+	Title        string
+	Link         string
+	Step         int
+	TotalSteps   int
+	Status       string
+	ActorName    string
+	Comment      string
+}
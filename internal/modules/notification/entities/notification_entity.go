@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification types raised by the approval workflow and document
+// discussions. Each has an email template of the same name.
+const (
+	TypeApprovalRequested = "approval_requested"
+	TypeDocumentRejected  = "document_rejected"
+	TypeDocumentApproved  = "document_approved"
+	TypeMentioned         = "mentioned"
+)
+
+// Types lists every notification type a user can set preferences for.
+var Types = []string{
+	TypeApprovalRequested,
+	TypeDocumentRejected,
+	TypeDocumentApproved,
+	TypeMentioned,
+}
+
+// IsType reports whether t is a known notification type.
+func IsType(t string) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is an entry in a user's in-app notification center. The
+// event, user and type are unique so a redelivered event is stored once.
+type Notification struct {
+	ID         uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID   uuid.UUID        `gorm:"type:uuid;index" json:"tenant_id"`
+	UserID     uuid.UUID        `gorm:"type:uuid;not null;index:idx_notifications_user_created;uniqueIndex:idx_notifications_event" json:"user_id"`
+	EventID    uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_notifications_event" json:"-"`
+	Type       string           `gorm:"type:varchar(100);not null;uniqueIndex:idx_notifications_event" json:"type"`
+	DocumentID *uuid.UUID       `gorm:"type:uuid;index" json:"document_id,omitempty"`
+	Message    string           `gorm:"type:text;not null" json:"message"`
+	Data       NotificationData `gorm:"type:jsonb;serializer:json" json:"data"`
+	ReadAt     *time.Time       `gorm:"index" json:"read_at"`
+	CreatedAt  time.Time        `gorm:"index:idx_notifications_user_created" json:"created_at"`
+}
+
+// NotificationData carries the details a client needs to render the
+// notification itself.
+type NotificationData struct {
+	DocumentTitle string     `json:"document_title,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	Step          int        `json:"step,omitempty"`
+	TotalSteps    int        `json:"total_steps,omitempty"`
+	ActorID       *uuid.UUID `json:"actor_id,omitempty"`
+	ActorName     string     `json:"actor_name,omitempty"`
+	Comment       string     `json:"comment,omitempty"`
+	Link          string     `json:"link,omitempty"`
+}
+
+func (n *Notification) TableName() string {
+	return "notifications"
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Channels a notification can be delivered through.
+const (
+	ChannelEmail = "email"
+	ChannelInApp = "in_app"
+)
+
+// NotificationPreference is a user's choice of channels for one
+// notification type. Types without a stored preference use every channel.
+type NotificationPreference struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	TenantID  uuid.UUID `gorm:"type:uuid;index" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_notification_preferences_user_type" json:"-"`
+	Type      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_notification_preferences_user_type" json:"type"`
+	Email     bool      `gorm:"not null" json:"email"`
+	InApp     bool      `gorm:"not null" json:"in_app"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// DefaultPreference is used for types the user has not configured.
+func DefaultPreference(userID uuid.UUID, notificationType string) NotificationPreference {
+	return NotificationPreference{UserID: userID, Type: notificationType, Email: true, InApp: true}
+}
+
+// Allows reports whether the preference lets channel through.
+func (p *NotificationPreference) Allows(channel string) bool {
+	switch channel {
+	case ChannelEmail:
+		return p.Email
+	case ChannelInApp:
+		return p.InApp
+	default:
+		return false
+	}
+}
+
+func (p *NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+func (p *NotificationPreference) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"testcase/internal/helpers"
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/notification/dto"
+	"testcase/internal/modules/notification/services"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	params := helpers.ParsePaginationParams(c)
+
+	list, err := h.notificationService.ListNotifications(c.Request.Context(), params)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, list, "Notifications retrieved successfully", http.StatusOK)
+}
+
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	count, err := h.notificationService.UnreadCount(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, count, "Unread notifications counted successfully", http.StatusOK)
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	notification, err := h.notificationService.MarkRead(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, notification, "Notification marked as read", http.StatusOK)
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	result, err := h.notificationService.MarkAllRead(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, result, "Notifications marked as read", http.StatusOK)
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	preferences, err := h.notificationService.GetPreferences(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, preferences, "Notification preferences retrieved successfully", http.StatusOK)
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var input dto.UpdatePreferencesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, preferences, "Notification preferences updated successfully", http.StatusOK)
+}
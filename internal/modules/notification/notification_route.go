@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/notification/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterNotificationRoutes(rg *gin.RouterGroup, h *handlers.NotificationHandler, authMware *middlewares.AuthMiddleware) {
+
+	meRoutes := rg.Group("/users/me")
+	meRoutes.Use(authMware.Auth())
+	{
+		meRoutes.GET("/notifications", h.ListNotifications)
+		meRoutes.GET("/notifications/unread-count", h.UnreadCount)
+		meRoutes.POST("/notifications/read-all", h.MarkAllRead)
+		meRoutes.POST("/notifications/:id/read", h.MarkRead)
+		meRoutes.GET("/notification-preferences", h.GetPreferences)
+		meRoutes.PUT("/notification-preferences", h.UpdatePreferences)
+	}
+}
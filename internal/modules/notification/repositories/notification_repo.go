@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/notification/entities"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	WasSent(ctx context.Context, eventID, userID uuid.UUID, template string) (bool, error)
+	MarkSent(ctx context.Context, notification *entities.EmailNotification) error
+
+	// CreateNotifications skips notifications already stored for the same
+	// event, user and type.
+	CreateNotifications(ctx context.Context, notifications []entities.Notification) error
+	ListNotifications(ctx context.Context, userID uuid.UUID, params *helpers.PaginationParams) ([]entities.Notification, int64, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	FindNotification(ctx context.Context, userID, id uuid.UUID) (*entities.Notification, error)
+	MarkRead(ctx context.Context, notification *entities.Notification) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	FindPreferences(ctx context.Context, userIDs []uuid.UUID) ([]entities.NotificationPreference, error)
+	SavePreferences(ctx context.Context, preferences []entities.NotificationPreference) error
+}
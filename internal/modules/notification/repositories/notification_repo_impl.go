@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testcase/internal/helpers"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/notification/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewNotificationRepository(db *database.Database) NotificationRepository {
+	return &notificationRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *notificationRepositoryImpl) WasSent(ctx context.Context, eventID, userID uuid.UUID, template string) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&entities.EmailNotification{}).
+		Where("event_id = ? AND user_id = ? AND template = ?", eventID, userID, template).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check email notification: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r *notificationRepositoryImpl) MarkSent(ctx context.Context, notification *entities.EmailNotification) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(notification).Error
+	if err != nil {
+		return fmt.Errorf("failed to record email notification: %w", err)
+	}
+
+	return nil
+}
+
+func (r *notificationRepositoryImpl) CreateNotifications(ctx context.Context, notifications []entities.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&notifications).Error
+	if err != nil {
+		return fmt.Errorf("failed to create notifications: %w", err)
+	}
+
+	return nil
+}
+
+func (r *notificationRepositoryImpl) ListNotifications(ctx context.Context, userID uuid.UUID, params *helpers.PaginationParams) ([]entities.Notification, int64, error) {
+	var notifications []entities.Notification
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Notification{}).Where("user_id = ?", userID)
+
+	switch params.Filter {
+	case "unread":
+		query = query.Where("read_at IS NULL")
+	case "read":
+		query = query.Where("read_at IS NOT NULL")
+	}
+
+	if params.Search != "" {
+		query = query.Where("type = ?", params.Search)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	err := query.
+		Offset(params.GetOffset()).
+		Limit(params.Limit).
+		Order("created_at desc").
+		Find(&notifications).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	return notifications, total, nil
+}
+
+func (r *notificationRepositoryImpl) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Model(&entities.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *notificationRepositoryImpl) FindNotification(ctx context.Context, userID, id uuid.UUID) (*entities.Notification, error) {
+	var notification entities.Notification
+
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("notification not found")
+		}
+		return nil, fmt.Errorf("failed to find notification: %w", err)
+	}
+
+	return &notification, nil
+}
+
+func (r *notificationRepositoryImpl) MarkRead(ctx context.Context, notification *entities.Notification) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&entities.Notification{}).
+		Where("id = ? AND read_at IS NULL", notification.ID).
+		Update("read_at", now)
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		notification.ReadAt = &now
+	}
+
+	return nil
+}
+
+func (r *notificationRepositoryImpl) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entities.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *notificationRepositoryImpl) FindPreferences(ctx context.Context, userIDs []uuid.UUID) ([]entities.NotificationPreference, error) {
+	var preferences []entities.NotificationPreference
+
+	err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&preferences).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+
+	return preferences, nil
+}
+
+func (r *notificationRepositoryImpl) SavePreferences(ctx context.Context, preferences []entities.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"email", "in_app", "updated_at"}),
+		}).
+		Create(&preferences).Error
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package responses
+
+import "testcase/internal/utils"
+
+// NotificationListResponse is a page of notifications with the number of
+// unread ones across all pages.
+type NotificationListResponse struct {
+	utils.PaginationResult
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type MarkAllReadResponse struct {
+	Marked int64 `json:"marked"`
+}
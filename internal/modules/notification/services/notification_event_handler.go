@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	commentEvents "testcase/internal/modules/comment/events"
+	documentEntities "testcase/internal/modules/document/entities"
+	documentEvents "testcase/internal/modules/document/events"
+	"testcase/internal/modules/notification/entities"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	roleEntities "testcase/internal/modules/role/entities"
+	userEntities "testcase/internal/modules/user/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *notificationServiceImpl) HandleDocumentEvent(ctx context.Context, event *outboxEntities.OutboxEvent) error {
+	payload, err := documentEvents.Decode(event)
+	if err != nil {
+		return err
+	}
+
+	var (
+		notificationType string
+		recipients       []userEntities.User
+	)
+	switch event.Type {
+	case documentEvents.DocumentCreated, documentEvents.DocumentResubmitted, documentEvents.StepApproved:
+		if payload.Status == documentEntities.StatusApproved {
+			// DocumentApproved tells the submitter about the last step.
+			return nil
+		}
+		notificationType = entities.TypeApprovalRequested
+		recipients, err = s.stepApprovers(ctx, payload, payload.CurrentStep)
+	case documentEvents.DocumentRejected:
+		notificationType = entities.TypeDocumentRejected
+		recipients, err = s.submitter(ctx, payload)
+	case documentEvents.DocumentApproved:
+		notificationType = entities.TypeDocumentApproved
+		recipients, err = s.submitter(ctx, payload)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if payload.ActorID != nil {
+		recipients = excludeUser(recipients, *payload.ActorID)
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	data, err := s.emailData(ctx, payload)
+	if err != nil {
+		return err
+	}
+	if notificationType == entities.TypeApprovalRequested {
+		data.Step = payload.CurrentStep
+	}
+
+	return s.deliver(ctx, event.ID, notificationType, recipients, payload.DocumentID, payload.ActorID, *data)
+}
+
+func (s *notificationServiceImpl) HandleCommentEvent(ctx context.Context, event *outboxEntities.OutboxEvent) error {
+	payload, err := commentEvents.Decode(event)
+	if err != nil {
+		return err
+	}
+	if len(payload.Mentioned) == 0 {
+		return nil
+	}
+
+	recipients, err := s.userRepo.FindActiveByIDs(ctx, payload.Mentioned)
+	if err != nil {
+		return err
+	}
+	recipients = excludeUser(recipients, payload.ActorID)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	data, err := s.emailData(ctx, &documentEvents.DocumentEvent{
+		DocumentID: payload.DocumentID,
+		Title:      payload.DocumentTitle,
+		ActorID:    &payload.ActorID,
+		Comment:    &payload.Body,
+	})
+	if err != nil {
+		return err
+	}
+	data.Link += "#comment-" + payload.CommentID.String()
+	data.TotalSteps = 0
+
+	return s.deliver(ctx, event.ID, entities.TypeMentioned, recipients, payload.DocumentID, &payload.ActorID, *data)
+}
+
+// deliver stores the in-app notification and sends the email of an event
+// to each recipient whose preferences allow it.
+func (s *notificationServiceImpl) deliver(ctx context.Context, eventID uuid.UUID, notificationType string, recipients []userEntities.User, documentID uuid.UUID, actorID *uuid.UUID, data entities.EmailData) error {
+	userIDs := make([]uuid.UUID, 0, len(recipients))
+	for _, recipient := range recipients {
+		userIDs = append(userIDs, recipient.ID)
+	}
+	preferences, err := s.preferencesFor(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	var (
+		notifications []entities.Notification
+		errs          []error
+	)
+	for i := range recipients {
+		preference := preferences.get(recipients[i].ID, notificationType)
+		if !preference.InApp {
+			continue
+		}
+		notification, err := s.inAppNotification(eventID, notificationType, &recipients[i], documentID, actorID, data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify %s: %w", recipients[i].Email, err))
+			continue
+		}
+		notifications = append(notifications, *notification)
+	}
+	if err := s.notificationRepo.CreateNotifications(ctx, notifications); err != nil {
+		errs = append(errs, err)
+	}
+
+	if s.config.Notification.Enabled {
+		for i := range recipients {
+			preference := preferences.get(recipients[i].ID, notificationType)
+			if !preference.Email {
+				continue
+			}
+			if err := s.send(ctx, eventID, notificationType, &recipients[i], data); err != nil {
+				errs = append(errs, fmt.Errorf("notify %s: %w", recipients[i].Email, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *notificationServiceImpl) DocumentAudience(ctx context.Context, event *outboxEntities.OutboxEvent) ([]uuid.UUID, error) {
+	payload, err := documentEvents.Decode(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var audience []uuid.UUID
+	if payload.SubmittedBy != nil {
+		audience = append(audience, *payload.SubmittedBy)
+	}
+
+	steps := []int{payload.Step}
+	document := documentEntities.Document{Status: payload.Status}
+	if document.IsOpen() && payload.CurrentStep != payload.Step {
+		steps = append(steps, payload.CurrentStep)
+	}
+	for _, step := range steps {
+		if step == 0 {
+			continue
+		}
+		approvers, err := s.stepApprovers(ctx, payload, step)
+		if err != nil {
+			return nil, err
+		}
+		for _, approver := range approvers {
+			if !slices.Contains(audience, approver.ID) {
+				audience = append(audience, approver.ID)
+			}
+		}
+	}
+
+	return audience, nil
+}
+
+// stepApprovers lists the active users who may act on step of the
+// document.
+func (s *notificationServiceImpl) stepApprovers(ctx context.Context, payload *documentEvents.DocumentEvent, step int) ([]userEntities.User, error) {
+	approvers, err := s.documents.StepApprovers(ctx, &documentEntities.Document{
+		ID:              payload.DocumentID,
+		DepartmentID:    payload.DepartmentID,
+		CurrentApprover: step,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var users []userEntities.User
+	if len(approvers.Roles) > 0 {
+		candidates, err := s.userRepo.FindActiveByRoles(ctx, approvers.Roles)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if approvers.DepartmentID != nil {
+				member, err := s.departments.IsMemberWithin(ctx, candidate.ID, *approvers.DepartmentID)
+				if err != nil {
+					return nil, err
+				}
+				if !member {
+					continue
+				}
+			}
+			users = append(users, candidate)
+		}
+	}
+	if len(approvers.UserIDs) > 0 {
+		named, err := s.userRepo.FindActiveByIDs(ctx, approvers.UserIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range named {
+			if !containsUser(users, user.ID) {
+				users = append(users, user)
+			}
+		}
+	}
+
+	return users, nil
+}
+
+func (s *notificationServiceImpl) submitter(ctx context.Context, payload *documentEvents.DocumentEvent) ([]userEntities.User, error) {
+	if payload.SubmittedBy == nil {
+		return nil, nil
+	}
+	return s.userRepo.FindActiveByIDs(ctx, []uuid.UUID{*payload.SubmittedBy})
+}
+
+// emailData fills the fields shared by every recipient of an event.
+func (s *notificationServiceImpl) emailData(ctx context.Context, payload *documentEvents.DocumentEvent) (*entities.EmailData, error) {
+	settings, err := s.settings.ForTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &entities.EmailData{
+		Brand:        settings.Branding.DisplayName,
+		LogoURL:      settings.Branding.LogoURL,
+		PrimaryColor: settings.Branding.PrimaryColor,
+		Title:        payload.Title,
+		Link:         strings.TrimSuffix(s.config.Notification.DocumentURL, "/") + "/" + payload.DocumentID.String(),
+		Step:         payload.Step,
+		TotalSteps:   roleEntities.ApprovalSteps,
+		Status:       string(payload.Status),
+	}
+	if payload.Comment != nil {
+		data.Comment = *payload.Comment
+	}
+	if payload.ActorID != nil {
+		actors, err := s.userRepo.FindActiveByIDs(ctx, []uuid.UUID{*payload.ActorID})
+		if err != nil {
+			return nil, err
+		}
+		if len(actors) > 0 {
+			data.ActorName = actors[0].Name
+		}
+	}
+
+	return data, nil
+}
+
+// inAppNotification builds the notification center entry, with its
+// message in the recipient's language.
+func (s *notificationServiceImpl) inAppNotification(eventID uuid.UUID, notificationType string, user *userEntities.User, documentID uuid.UUID, actorID *uuid.UUID, data entities.EmailData) (*entities.Notification, error) {
+	data.Name = user.Name
+	message, err := s.templates.RenderText(notificationType, user.Locale, "summary", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Notification{
+		UserID:     user.ID,
+		EventID:    eventID,
+		Type:       notificationType,
+		DocumentID: &documentID,
+		Message:    message,
+		Data: entities.NotificationData{
+			DocumentTitle: data.Title,
+			Status:        data.Status,
+			Step:          data.Step,
+			TotalSteps:    data.TotalSteps,
+			ActorID:       actorID,
+			ActorName:     data.ActorName,
+			Comment:       data.Comment,
+			Link:          data.Link,
+		},
+	}, nil
+}
+
+// send mails one recipient unless an earlier delivery of the same event
+// already did.
+func (s *notificationServiceImpl) send(ctx context.Context, eventID uuid.UUID, template string, user *userEntities.User, data entities.EmailData) error {
+	sent, err := s.notificationRepo.WasSent(ctx, eventID, user.ID, template)
+	if err != nil || sent {
+		return err
+	}
+
+	data.Name = user.Name
+	message, err := s.templates.Render(template, user.Locale, data)
+	if err != nil {
+		return err
+	}
+	message.From = s.config.Mail.From
+	message.To = []string{user.Email}
+	if err := s.mailer.Send(ctx, message); err != nil {
+		return err
+	}
+
+	return s.notificationRepo.MarkSent(ctx, &entities.EmailNotification{
+		EventID:  eventID,
+		UserID:   user.ID,
+		Template: template,
+		SentAt:   time.Now(),
+	})
+}
+
+func excludeUser(users []userEntities.User, id uuid.UUID) []userEntities.User {
+	kept := users[:0]
+	for _, user := range users {
+		if user.ID != id {
+			kept = append(kept, user)
+		}
+	}
+	return kept
+}
+
+func containsUser(users []userEntities.User, id uuid.UUID) bool {
+	for _, user := range users {
+		if user.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/notification/dto"
+	"testcase/internal/modules/notification/entities"
+	"testcase/internal/modules/notification/responses"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+
+	"github.com/google/uuid"
+)
+
+type NotificationService interface {
+	// The notification center and preferences belong to the user in ctx.
+	ListNotifications(ctx context.Context, params *helpers.PaginationParams) (*responses.NotificationListResponse, error)
+	UnreadCount(ctx context.Context) (*responses.UnreadCountResponse, error)
+	MarkRead(ctx context.Context, id string) (*entities.Notification, error)
+	MarkAllRead(ctx context.Context) (*responses.MarkAllReadResponse, error)
+	GetPreferences(ctx context.Context) ([]entities.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, input *dto.UpdatePreferencesInput) ([]entities.NotificationPreference, error)
+
+	// HandleDocumentEvent notifies the users a document event concerns: the
+	// approvers of the next step, or the submitter once the document is
+	// rejected or approved. Each user's preferences pick the channels.
+	HandleDocumentEvent(ctx context.Context, event *outboxEntities.OutboxEvent) error
+	// HandleCommentEvent notifies the users a comment newly mentions.
+	HandleCommentEvent(ctx context.Context, event *outboxEntities.OutboxEvent) error
+	// DocumentAudience lists the users who should see a document event
+	// live: the submitter and whoever may approve the step acted on or the
+	// step the document now waits on.
+	DocumentAudience(ctx context.Context, event *outboxEntities.OutboxEvent) ([]uuid.UUID, error)
+}
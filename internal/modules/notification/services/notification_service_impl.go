@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testcase/config"
+	"testcase/internal/helpers"
+	departmentServices "testcase/internal/modules/department/services"
+	documentServices "testcase/internal/modules/document/services"
+	"testcase/internal/modules/notification/dto"
+	"testcase/internal/modules/notification/entities"
+	"testcase/internal/modules/notification/repositories"
+	"testcase/internal/modules/notification/responses"
+	"testcase/internal/modules/notification/templates"
+	settingServices "testcase/internal/modules/setting/services"
+	userRepositories "testcase/internal/modules/user/repositories"
+	"testcase/internal/utils"
+	"testcase/package/mailer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type notificationServiceImpl struct {
+	notificationRepo repositories.NotificationRepository
+	userRepo         userRepositories.UserRepository
+	documents        documentServices.DocumentService
+	departments      departmentServices.DepartmentService
+	settings         settingServices.SettingService
+	mailer           mailer.Mailer
+	templates        *mailer.Templates
+	config           *config.Config
+}
+
+func NewNotificationService(notificationRepo repositories.NotificationRepository, userRepo userRepositories.UserRepository, documents documentServices.DocumentService, departments departmentServices.DepartmentService, settings settingServices.SettingService, appMailer mailer.Mailer, cfg *config.Config) (NotificationService, error) {
+	parsed, err := mailer.ParseTemplates(templates.FS, cfg.Notification.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationServiceImpl{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		documents:        documents,
+		departments:      departments,
+		settings:         settings,
+		mailer:           appMailer,
+		templates:        parsed,
+		config:           cfg,
+	}, nil
+}
+
+func (s *notificationServiceImpl) ListNotifications(ctx context.Context, params *helpers.PaginationParams) (*responses.NotificationListResponse, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, total, err := s.notificationRepo.ListNotifications(ctx, userID, params)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return &responses.NotificationListResponse{
+		PaginationResult: *helpers.CreatePaginationResult(notifications, total, params),
+		UnreadCount:      unread,
+	}, nil
+}
+
+func (s *notificationServiceImpl) UnreadCount(ctx context.Context) (*responses.UnreadCountResponse, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return &responses.UnreadCountResponse{UnreadCount: unread}, nil
+}
+
+func (s *notificationServiceImpl) MarkRead(ctx context.Context, id string) (*entities.Notification, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notificationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid notification ID: %w", err))
+	}
+
+	notification, err := s.notificationRepo.FindNotification(ctx, userID, notificationID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrNotFound, err)
+	}
+	if notification.ReadAt == nil {
+		if err := s.notificationRepo.MarkRead(ctx, notification); err != nil {
+			return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+		}
+	}
+
+	return notification, nil
+}
+
+func (s *notificationServiceImpl) MarkAllRead(ctx context.Context) (*responses.MarkAllReadResponse, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	marked, err := s.notificationRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	return &responses.MarkAllReadResponse{Marked: marked}, nil
+}
+
+func (s *notificationServiceImpl) GetPreferences(ctx context.Context) ([]entities.NotificationPreference, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := s.preferencesOf(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return preferences, nil
+}
+
+func (s *notificationServiceImpl) UpdatePreferences(ctx context.Context, input *dto.UpdatePreferencesInput) ([]entities.NotificationPreference, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := s.preferencesOf(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	var changed []entities.NotificationPreference
+	for _, update := range input.Preferences {
+		if !entities.IsType(update.Type) {
+			return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("unknown notification type %s", update.Type), fmt.Sprintf("Unknown notification type %q", update.Type))
+		}
+		for i := range preferences {
+			if preferences[i].Type != update.Type {
+				continue
+			}
+			if update.Email != nil {
+				preferences[i].Email = *update.Email
+			}
+			if update.InApp != nil {
+				preferences[i].InApp = *update.InApp
+			}
+			preferences[i].UpdatedAt = time.Now()
+			changed = append(changed, preferences[i])
+		}
+	}
+	if err := s.notificationRepo.SavePreferences(ctx, changed); err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	return preferences, nil
+}
+
+// preferencesOf returns the user's preference for every notification type,
+// filling in the defaults for types never configured.
+func (s *notificationServiceImpl) preferencesOf(ctx context.Context, userID uuid.UUID) ([]entities.NotificationPreference, error) {
+	byUser, err := s.preferencesFor(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]entities.NotificationPreference, 0, len(entities.Types))
+	for _, notificationType := range entities.Types {
+		preferences = append(preferences, byUser.get(userID, notificationType))
+	}
+	return preferences, nil
+}
+
+type preferenceSet map[uuid.UUID]map[string]entities.NotificationPreference
+
+func (p preferenceSet) get(userID uuid.UUID, notificationType string) entities.NotificationPreference {
+	if preference, ok := p[userID][notificationType]; ok {
+		return preference
+	}
+	return entities.DefaultPreference(userID, notificationType)
+}
+
+func (s *notificationServiceImpl) preferencesFor(ctx context.Context, userIDs []uuid.UUID) (preferenceSet, error) {
+	stored, err := s.notificationRepo.FindPreferences(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(preferenceSet)
+	for _, preference := range stored {
+		if set[preference.UserID] == nil {
+			set[preference.UserID] = make(map[string]entities.NotificationPreference)
+		}
+		set[preference.UserID][preference.Type] = preference
+	}
+	return set, nil
+}
+
+func currentUserID(ctx context.Context) (uuid.UUID, error) {
+	userID, ok := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return uuid.Nil, utils.NewAppError(utils.ErrUnauthorized, fmt.Errorf("user id missing from context"))
+	}
+	return userID, nil
+}
@@ -0,0 +1,9 @@
+// Package templates holds the notification emails. Add a locale by copying
+// the en directory; users whose locale has no directory get the default
+// locale.
+package templates
+
+import "embed"
+
+//go:embed layout.html */*.html
+var FS embed.FS
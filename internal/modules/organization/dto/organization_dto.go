@@ -0,0 +1,15 @@
+package dto
+
+import userDto "testcase/internal/modules/user/dto"
+
+// CreateOrganizationInput creates a tenant. When admin is given, that user
+// is created inside the new organization so it can be managed right away.
+type CreateOrganizationInput struct {
+	Name  string                   `json:"name" binding:"required,min=2,max=255"`
+	Slug  string                   `json:"slug" binding:"required,min=2,max=50,alphanum,lowercase"`
+	Admin *userDto.CreateUserInput `json:"admin,omitempty"`
+}
+
+type UpdateOrganizationInput struct {
+	Name string `json:"name" binding:"required,min=2,max=255"`
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"testcase/internal/helpers"
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/organization/dto"
+	"testcase/internal/modules/organization/services"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	organizationService services.OrganizationService
+}
+
+func NewOrganizationHandler(organizationService services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+	}
+}
+
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	params := helpers.ParsePaginationParams(c)
+
+	organizations, total, err := h.organizationService.ListOrganizations(c.Request.Context(), params)
+	if err != nil {
+		panic(err)
+	}
+
+	list := helpers.CreatePaginationResult(organizations, total, params)
+
+	utils.SuccessResponse(c, list, "Organizations retrieved successfully", http.StatusOK)
+}
+
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	organization, err := h.organizationService.GetOrganization(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, organization, "Organization retrieved successfully", http.StatusOK)
+}
+
+func (h *OrganizationHandler) CurrentOrganization(c *gin.Context) {
+	organization, err := h.organizationService.CurrentOrganization(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, organization, "Organization retrieved successfully", http.StatusOK)
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var input dto.CreateOrganizationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	created, err := h.organizationService.CreateOrganization(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, created, "Organization created successfully", http.StatusCreated)
+}
+
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	var input dto.UpdateOrganizationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	organization, err := h.organizationService.UpdateOrganization(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, organization, "Organization updated successfully", http.StatusOK)
+}
@@ -0,0 +1,27 @@
+package organization
+
+import (
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/organization/handlers"
+	roleEntities "testcase/internal/modules/role/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterOrganizationRoutes(rg *gin.RouterGroup, h *handlers.OrganizationHandler, authMware *middlewares.AuthMiddleware) {
+
+	organizationRoutes := rg.Group("/organizations")
+	organizationRoutes.Use(authMware.Auth())
+	{
+		organizationRoutes.GET("/current", h.CurrentOrganization)
+	}
+
+	platformRoutes := organizationRoutes.Group("")
+	platformRoutes.Use(authMware.RequirePlatformOrganization(), authMware.RequirePermission(roleEntities.PermissionOrganizationManage))
+	{
+		platformRoutes.GET("", h.ListOrganizations)
+		platformRoutes.GET("/:id", h.GetOrganization)
+		platformRoutes.POST("", h.CreateOrganization)
+		platformRoutes.PUT("/:id", h.UpdateOrganization)
+	}
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/organization/entities"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationRepository interface {
+	CreateOrganization(ctx context.Context, organization *entities.Organization) error
+	UpdateOrganization(ctx context.Context, organization *entities.Organization) error
+	DeleteOrganization(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.Organization, error)
+	FindBySlug(ctx context.Context, slug string) (*entities.Organization, error)
+	ListOrganizations(ctx context.Context, params *helpers.PaginationParams) ([]entities.Organization, int64, error)
+	AdoptUnassigned(ctx context.Context, id uuid.UUID) error
+}
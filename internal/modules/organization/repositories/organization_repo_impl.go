@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"testcase/internal/helpers"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/organization/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type organizationRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewOrganizationRepository(db *database.Database) OrganizationRepository {
+	return &organizationRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *organizationRepositoryImpl) CreateOrganization(ctx context.Context, organization *entities.Organization) error {
+	err := r.db.WithContext(ctx).Create(organization).Error
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return nil
+}
+
+func (r *organizationRepositoryImpl) UpdateOrganization(ctx context.Context, organization *entities.Organization) error {
+	err := r.db.WithContext(ctx).Save(organization).Error
+	if err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	return nil
+}
+
+func (r *organizationRepositoryImpl) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.Organization{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	return nil
+}
+
+func (r *organizationRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*entities.Organization, error) {
+	var organization entities.Organization
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&organization).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("organization with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to find organization by ID: %w", err)
+	}
+
+	return &organization, nil
+}
+
+// FindBySlug returns nil without an error when no organization has the slug.
+func (r *organizationRepositoryImpl) FindBySlug(ctx context.Context, slug string) (*entities.Organization, error) {
+	var organization entities.Organization
+
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&organization).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find organization by slug: %w", err)
+	}
+
+	return &organization, nil
+}
+
+func (r *organizationRepositoryImpl) ListOrganizations(ctx context.Context, params *helpers.PaginationParams) ([]entities.Organization, int64, error) {
+	var organizations []entities.Organization
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Organization{})
+
+	if params.Search != "" {
+		searchPattern := fmt.Sprintf("%%%s%%", params.Search)
+		query = query.Where("name ILIKE ? OR slug ILIKE ?", searchPattern, searchPattern)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count organizations: %w", err)
+	}
+
+	err := query.
+		Offset(params.GetOffset()).
+		Limit(params.Limit).
+		Order("name asc").
+		Find(&organizations).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	return organizations, total, nil
+}
+
+// AdoptUnassigned moves rows created before multi-tenancy, which have no
+// tenant_id yet, into the organization. Tables are discovered from the
+// schema so new tenant-owned tables are covered without changes here.
+func (r *organizationRepositoryImpl) AdoptUnassigned(ctx context.Context, id uuid.UUID) error {
+	var tables []string
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT table_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND column_name = 'tenant_id'`).
+		Scan(&tables).Error
+	if err != nil {
+		return fmt.Errorf("failed to list tenant tables: %w", err)
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			err := tx.Exec(fmt.Sprintf("UPDATE %q SET tenant_id = ? WHERE tenant_id IS NULL", table), id).Error
+			if err != nil {
+				return fmt.Errorf("failed to assign tenant on %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
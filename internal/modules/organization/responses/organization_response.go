@@ -0,0 +1,11 @@
+package responses
+
+import (
+	"testcase/internal/modules/organization/entities"
+	userEntities "testcase/internal/modules/user/entities"
+)
+
+type OrganizationCreatedResponse struct {
+	Organization entities.Organization `json:"organization"`
+	Admin        *userEntities.User    `json:"admin,omitempty"`
+}
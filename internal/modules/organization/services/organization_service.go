@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/organization/dto"
+	"testcase/internal/modules/organization/entities"
+	"testcase/internal/modules/organization/responses"
+)
+
+type OrganizationService interface {
+	ListOrganizations(ctx context.Context, params *helpers.PaginationParams) ([]entities.Organization, int64, error)
+	GetOrganization(ctx context.Context, id string) (*entities.Organization, error)
+	CurrentOrganization(ctx context.Context) (*entities.Organization, error)
+	CreateOrganization(ctx context.Context, input *dto.CreateOrganizationInput) (*responses.OrganizationCreatedResponse, error)
+	UpdateOrganization(ctx context.Context, id string, input *dto.UpdateOrganizationInput) (*entities.Organization, error)
+	EnsureDefaultOrganization(ctx context.Context) (*entities.Organization, error)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"testcase/config"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/organization/dto"
+	"testcase/internal/modules/organization/entities"
+	"testcase/internal/modules/organization/repositories"
+	"testcase/internal/modules/organization/responses"
+	userServices "testcase/internal/modules/user/services"
+	"testcase/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type organizationServiceImpl struct {
+	organizationRepo repositories.OrganizationRepository
+	userService      userServices.UserService
+	config           *config.Config
+}
+
+func NewOrganizationService(organizationRepo repositories.OrganizationRepository, userService userServices.UserService, cfg *config.Config) OrganizationService {
+	return &organizationServiceImpl{
+		organizationRepo: organizationRepo,
+		userService:      userService,
+		config:           cfg,
+	}
+}
+
+func (o *organizationServiceImpl) ListOrganizations(ctx context.Context, params *helpers.PaginationParams) ([]entities.Organization, int64, error) {
+	organizations, total, err := o.organizationRepo.ListOrganizations(ctx, params)
+	if err != nil {
+		return nil, 0, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return organizations, total, nil
+}
+
+func (o *organizationServiceImpl) GetOrganization(ctx context.Context, id string) (*entities.Organization, error) {
+	organizationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid organization ID: %w", err))
+	}
+
+	organization, err := o.organizationRepo.FindByID(ctx, organizationID)
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrNotFound, err, "Organization not found")
+	}
+
+	return organization, nil
+}
+
+func (o *organizationServiceImpl) CurrentOrganization(ctx context.Context) (*entities.Organization, error) {
+	tenantID, ok := ctx.Value(utils.TenantIDContextKey).(uuid.UUID)
+	if !ok || tenantID == uuid.Nil {
+		return nil, utils.NewAppError(utils.ErrUnauthorized, fmt.Errorf("tenant id missing from context"))
+	}
+
+	organization, err := o.organizationRepo.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrNotFound, err, "Organization not found")
+	}
+
+	return organization, nil
+}
+
+func (o *organizationServiceImpl) CreateOrganization(ctx context.Context, input *dto.CreateOrganizationInput) (*responses.OrganizationCreatedResponse, error) {
+	existing, err := o.organizationRepo.FindBySlug(ctx, input.Slug)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if existing != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrConflict, fmt.Errorf("organization %s already exists", input.Slug), "Organization slug already exists")
+	}
+
+	organization := &entities.Organization{
+		Name: input.Name,
+		Slug: input.Slug,
+	}
+	if err := o.organizationRepo.CreateOrganization(ctx, organization); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	response := &responses.OrganizationCreatedResponse{Organization: *organization}
+
+	if input.Admin != nil {
+		admin, err := o.userService.CreateUser(utils.WithTenant(ctx, organization.ID), input.Admin)
+		if err != nil {
+			// Don't leave an organization nobody can sign in to.
+			if deleteErr := o.organizationRepo.DeleteOrganization(ctx, organization.ID); deleteErr != nil {
+				log.Printf("Failed to remove organization %s after admin creation failed: %v", organization.Slug, deleteErr)
+			}
+			return nil, err
+		}
+		response.Admin = admin
+	}
+
+	return response, nil
+}
+
+func (o *organizationServiceImpl) UpdateOrganization(ctx context.Context, id string, input *dto.UpdateOrganizationInput) (*entities.Organization, error) {
+	organization, err := o.GetOrganization(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	organization.Name = input.Name
+	if err := o.organizationRepo.UpdateOrganization(ctx, organization); err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	return organization, nil
+}
+
+// EnsureDefaultOrganization creates the default organization on first start
+// and hands it every row that predates multi-tenancy.
+func (o *organizationServiceImpl) EnsureDefaultOrganization(ctx context.Context) (*entities.Organization, error) {
+	organization, err := o.organizationRepo.FindBySlug(ctx, o.config.Organization.DefaultSlug)
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		organization = &entities.Organization{
+			Name: o.config.Organization.DefaultName,
+			Slug: o.config.Organization.DefaultSlug,
+		}
+		if err := o.organizationRepo.CreateOrganization(ctx, organization); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := o.organizationRepo.AdoptUnassigned(ctx, organization.ID); err != nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the
+// change that raised it. The dispatcher delivers it afterwards, so events
+// are never lost when the process stops between commit and delivery.
+type OutboxEvent struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID      uuid.UUID       `gorm:"type:uuid;index" json:"tenant_id"`
+	Type          string          `gorm:"type:varchar(100);not null;index" json:"type"`
+	AggregateID   uuid.UUID       `gorm:"type:uuid;index" json:"aggregate_id"`
+	Payload       json.RawMessage `gorm:"type:jsonb;serializer:json;not null" json:"payload"`
+	Attempts      int             `gorm:"not null;default:0" json:"attempts"`
+	LastError     string          `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `gorm:"index" json:"next_attempt_at"`
+	DispatchedAt  *time.Time      `gorm:"index" json:"dispatched_at,omitempty"`
+	FailedAt      *time.Time      `json:"failed_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func (e *OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// NewOutboxEvent encodes payload for an event about the aggregate. The
+// tenant is filled in from the context when the event is written.
+func NewOutboxEvent(eventType string, aggregateID uuid.UUID, payload any) (*OutboxEvent, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		ID:            uuid.New(),
+		Type:          eventType,
+		AggregateID:   aggregateID,
+		Payload:       encoded,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/outbox/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxRepository interface {
+	// ClaimDue returns up to limit events that are due and pushes their
+	// next attempt back by lease, so concurrent dispatchers skip them while
+	// they are delivered outside any transaction. A dispatcher that dies
+	// mid-delivery releases its events when the lease runs out.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.OutboxEvent, error)
+	// SaveDelivery stores the delivery state recorded on a claimed event.
+	SaveDelivery(ctx context.Context, event *entities.OutboxEvent) error
+	PurgeDispatched(ctx context.Context, before time.Time) (int64, error)
+
+	// FindEvent returns nil when the event doesn't exist or was purged.
+	FindEvent(ctx context.Context, id uuid.UUID) (*entities.OutboxEvent, error)
+	// ListAfter returns up to limit events of the given types raised after
+	// event, oldest first.
+	ListAfter(ctx context.Context, event *entities.OutboxEvent, types []string, limit int) ([]entities.OutboxEvent, error)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/outbox/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewOutboxRepository(db *database.Database) OutboxRepository {
+	return &outboxRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *outboxRepositoryImpl) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.OutboxEvent, error) {
+	var events []entities.OutboxEvent
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?", now).
+			Order("created_at").
+			Limit(limit).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+
+		return tx.Model(&entities.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepositoryImpl) SaveDelivery(ctx context.Context, event *entities.OutboxEvent) error {
+	err := r.db.WithContext(ctx).Model(event).
+		Select("attempts", "last_error", "next_attempt_at", "dispatched_at", "failed_at").
+		Updates(event).Error
+	if err != nil {
+		return fmt.Errorf("failed to save outbox event %s: %w", event.ID, err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepositoryImpl) PurgeDispatched(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("dispatched_at < ?", before).
+		Delete(&entities.OutboxEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge outbox events: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *outboxRepositoryImpl) FindEvent(ctx context.Context, id uuid.UUID) (*entities.OutboxEvent, error) {
+	var event entities.OutboxEvent
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find outbox event: %w", err)
+	}
+
+	return &event, nil
+}
+
+func (r *outboxRepositoryImpl) ListAfter(ctx context.Context, event *entities.OutboxEvent, types []string, limit int) ([]entities.OutboxEvent, error) {
+	var events []entities.OutboxEvent
+
+	err := r.db.WithContext(ctx).
+		Where("type IN ?", types).
+		Where("(created_at, id) > (?, ?)", event.CreatedAt, event.ID).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"testcase/config"
+	"testcase/internal/modules/outbox/entities"
+	"testcase/internal/modules/outbox/repositories"
+	"testcase/internal/utils"
+	"time"
+)
+
+// AllEvents subscribes a handler to every event type.
+const AllEvents = "*"
+
+// Handler receives an event in the context of the organization that raised
+// it. Delivery is at least once: an event is redelivered to every
+// subscriber when any of them fails, so handlers must be idempotent, for
+// example by remembering event IDs they have processed.
+type Handler func(ctx context.Context, event *entities.OutboxEvent) error
+
+// Dispatcher polls the outbox and delivers pending events to in-process
+// subscribers. Several instances may run against the same database; events
+// are claimed with a lease so each is handled by one of them, and
+// subscribers run after the claim is committed so they hold no row locks.
+type Dispatcher struct {
+	outboxRepo  repositories.OutboxRepository
+	config      *config.Config
+	mu          sync.RWMutex
+	subscribers map[string][]Handler
+	lastPurge   time.Time
+}
+
+func NewDispatcher(outboxRepo repositories.OutboxRepository, cfg *config.Config) *Dispatcher {
+	return &Dispatcher{
+		outboxRepo:  outboxRepo,
+		config:      cfg,
+		subscribers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers handler for eventType, or for all events with
+// AllEvents. Subscribers should be registered before Run is started.
+func (d *Dispatcher) Subscribe(eventType string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscribers[eventType] = append(d.subscribers[eventType], handler)
+}
+
+// Run delivers events until ctx is cancelled. Events claimed but not yet
+// delivered at shutdown are picked up again once their lease runs out.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Outbox.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) poll(ctx context.Context) {
+	// Events of every organization are claimed here; each one is handed to
+	// its subscribers scoped to its own organization.
+	ctx = utils.WithoutTenantScope(ctx)
+
+	for ctx.Err() == nil {
+		events, err := d.outboxRepo.ClaimDue(ctx, d.config.Outbox.BatchSize, d.config.Outbox.ClaimLease)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Outbox dispatch failed: %v", err)
+			}
+			return
+		}
+
+		for i := range events {
+			if ctx.Err() != nil {
+				return
+			}
+			d.deliver(ctx, &events[i])
+			// Record what the subscribers did even when shutdown began
+			// while they ran, so the event isn't delivered again.
+			if err := d.outboxRepo.SaveDelivery(context.WithoutCancel(ctx), &events[i]); err != nil {
+				log.Printf("Outbox dispatch failed: %v", err)
+			}
+		}
+		if len(events) < d.config.Outbox.BatchSize {
+			break
+		}
+	}
+
+	d.purge(ctx)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event *entities.OutboxEvent) {
+	d.mu.RLock()
+	handlers := append(append([]Handler{}, d.subscribers[event.Type]...), d.subscribers[AllEvents]...)
+	d.mu.RUnlock()
+
+	tenantCtx := utils.WithTenant(ctx, event.TenantID)
+	var errs []error
+	for _, handler := range handlers {
+		if err := safeHandle(tenantCtx, handler, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	now := time.Now()
+	event.Attempts++
+	if err := errors.Join(errs...); err != nil {
+		event.LastError = err.Error()
+		if event.Attempts >= d.config.Outbox.MaxAttempts {
+			event.FailedAt = &now
+			log.Printf("Outbox event %s (%s) failed after %d attempts: %v", event.ID, event.Type, event.Attempts, err)
+			return
+		}
+		event.NextAttemptAt = now.Add(d.retryDelay(event.Attempts))
+		return
+	}
+
+	event.LastError = ""
+	event.DispatchedAt = &now
+}
+
+// retryDelay doubles the configured delay after every failed attempt,
+// capped at one hour.
+func (d *Dispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.config.Outbox.RetryDelay
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Hour)
+}
+
+func (d *Dispatcher) purge(ctx context.Context) {
+	retention := d.config.Outbox.Retention
+	if retention <= 0 || time.Since(d.lastPurge) < time.Hour {
+		return
+	}
+	d.lastPurge = time.Now()
+
+	if _, err := d.outboxRepo.PurgeDispatched(ctx, time.Now().Add(-retention)); err != nil {
+		log.Printf("Outbox purge failed: %v", err)
+	}
+}
+
+func safeHandle(ctx context.Context, handler Handler, event *entities.OutboxEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("subscriber panicked: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
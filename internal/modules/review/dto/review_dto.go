@@ -0,0 +1,15 @@
+package dto
+
+// Client message types.
+const (
+	ClientComment = "comment"
+)
+
+// ClientMessage is what a reviewer sends over the socket. A comment takes
+// the same fields as posting one to the comments endpoint.
+type ClientMessage struct {
+	Type     string  `json:"type"`
+	Body     string  `json:"body"`
+	ParentID *string `json:"parent_id,omitempty"`
+	Revision *int    `json:"revision,omitempty"`
+}
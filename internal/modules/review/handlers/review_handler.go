@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testcase/config"
+	"testcase/internal/middlewares"
+	commentDto "testcase/internal/modules/comment/dto"
+	"testcase/internal/modules/review/dto"
+	"testcase/internal/modules/review/services"
+	"testcase/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// readLimit bounds a client message; a comment is at most a few KB.
+const readLimit = 16 << 10
+
+// writeWait is how long a single frame may take to send.
+const writeWait = 10 * time.Second
+
+type ReviewHandler struct {
+	reviewService services.ReviewService
+	config        *config.Config
+}
+
+func NewReviewHandler(reviewService services.ReviewService, cfg *config.Config) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+		config:        cfg,
+	}
+}
+
+// socket serializes writes, since the connection allows one writer at a
+// time and both the room loop and the read loop answer the client.
+type socket struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (s *socket) write(messageType int, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return s.conn.WriteMessage(messageType, data)
+}
+
+func (s *socket) writeJSON(message services.Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return s.write(websocket.TextMessage, data)
+}
+
+func (s *socket) writeError(text string) {
+	_ = s.writeJSON(services.Message{Type: services.MessageError, Error: text})
+}
+
+func (s *socket) writeClose(code int, text string) {
+	_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(writeWait))
+}
+
+// Review upgrades to a WebSocket for the document's review room. Joining
+// happens first so a missing or hidden document is answered with the usual
+// JSON error instead of a socket that closes right away.
+func (h *ReviewHandler) Review(c *gin.Context) {
+	viewer, err := h.reviewService.Join(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		panic(err)
+	}
+	defer h.reviewService.Leave(viewer)
+
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{middlewares.WebSocketTokenProtocol},
+		// The socket is authorized by the token the client sends, never by
+		// ambient cookies, so a foreign page gains nothing by opening it.
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	conn.SetReadLimit(readLimit)
+	ws := &socket{conn: conn}
+
+	// The reader gets the request context rather than c, which gin reuses
+	// once the handler returns, and is waited for before returning.
+	ctx := c.Request.Context()
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		h.read(ctx, ws, viewer)
+	}()
+	defer func() {
+		conn.Close()
+		<-closed
+	}()
+
+	ping := time.NewTicker(h.config.Review.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			ws.writeClose(websocket.CloseNormalClosure, "")
+			return
+		case <-viewer.Done():
+			ws.writeClose(websocket.CloseGoingAway, "")
+			return
+		case message := <-viewer.Messages():
+			if err := ws.writeJSON(message); err != nil {
+				return
+			}
+		case <-ping.C:
+			// The token was checked once at the handshake; a session that
+			// has since been revoked loses the room.
+			if err := utils.CheckAccess(ctx); err != nil {
+				ws.writeClose(websocket.ClosePolicyViolation, "Access revoked")
+				return
+			}
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// read handles client messages until the connection closes or stops
+// answering pings.
+func (h *ReviewHandler) read(ctx context.Context, ws *socket, viewer *services.Viewer) {
+	wait := 2 * h.config.Review.PingInterval
+	_ = ws.conn.SetReadDeadline(time.Now().Add(wait))
+	ws.conn.SetPongHandler(func(string) error {
+		return ws.conn.SetReadDeadline(time.Now().Add(wait))
+	})
+
+	for {
+		messageType, data, err := ws.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType != websocket.TextMessage {
+			ws.writeError("Messages must be JSON text")
+			continue
+		}
+
+		var message dto.ClientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			ws.writeError("Messages must be JSON text")
+			continue
+		}
+
+		switch message.Type {
+		case dto.ClientComment:
+			input := &commentDto.CreateCommentInput{Body: message.Body, ParentID: message.ParentID, Revision: message.Revision}
+			if err := h.reviewService.Comment(ctx, viewer, input); err != nil {
+				var appErr *utils.AppError
+				if errors.As(err, &appErr) {
+					ws.writeError(appErr.GetDisplayMessage())
+				} else {
+					ws.writeError("Comment could not be posted")
+				}
+			}
+		default:
+			ws.writeError("Unknown message type")
+		}
+	}
+}
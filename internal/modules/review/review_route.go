@@ -0,0 +1,22 @@
+package review
+
+import (
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/review/handlers"
+	roleEntities "testcase/internal/modules/role/entities"
+	"testcase/package/securities"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewPath is the review WebSocket route, which the server exempts from
+// its request timeout.
+const ReviewPath = "/api/v1/documents/:id/ws"
+
+func RegisterReviewRoutes(rg *gin.RouterGroup, h *handlers.ReviewHandler, authMware *middlewares.AuthMiddleware) {
+
+	reviewRoutes := rg.Group("/documents")
+	{
+		reviewRoutes.GET("/:id/ws", authMware.Auth(securities.APIScopeDocumentsRead), authMware.RequirePermission(roleEntities.PermissionDocumentRead), h.Review)
+	}
+}
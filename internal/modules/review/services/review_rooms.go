@@ -0,0 +1,205 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type roomKey struct {
+	tenantID   uuid.UUID
+	documentID uuid.UUID
+}
+
+func keyOf(viewer *Viewer) roomKey {
+	return roomKey{tenantID: viewer.TenantID, documentID: viewer.DocumentID}
+}
+
+type remoteViewer struct {
+	Participant
+	seenAt time.Time
+}
+
+// room is one document's review as seen from this instance: its own
+// connections plus the viewers other instances announced.
+type room struct {
+	local  map[*Viewer]struct{}
+	remote map[uuid.UUID]remoteViewer
+}
+
+// reviewRooms holds the rooms this instance has connections in. Rooms are
+// dropped with their last local connection, along with what was known of
+// other instances' viewers.
+type reviewRooms struct {
+	mu      sync.Mutex
+	rooms   map[roomKey]*room
+	stopped bool
+}
+
+func newReviewRooms() *reviewRooms {
+	return &reviewRooms{
+		rooms: make(map[roomKey]*room),
+	}
+}
+
+func (r *reviewRooms) join(viewer *Viewer) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		viewer.close()
+		return false
+	}
+	key := keyOf(viewer)
+	if r.rooms[key] == nil {
+		r.rooms[key] = &room{local: make(map[*Viewer]struct{}), remote: make(map[uuid.UUID]remoteViewer)}
+	}
+	r.rooms[key].local[viewer] = struct{}{}
+	r.sendPresence(key)
+	return true
+}
+
+func (r *reviewRooms) leave(viewer *Viewer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	viewer.close()
+	key := keyOf(viewer)
+	room := r.rooms[key]
+	if room == nil {
+		return
+	}
+	delete(room.local, viewer)
+	if len(room.local) == 0 {
+		delete(r.rooms, key)
+		return
+	}
+	r.sendPresence(key)
+}
+
+func (r *reviewRooms) has(key roomKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rooms[key] != nil
+}
+
+// locals returns the connections of key, or of every room when key is nil.
+func (r *reviewRooms) locals(key *roomKey) []*Viewer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var viewers []*Viewer
+	for k, room := range r.rooms {
+		if key != nil && k != *key {
+			continue
+		}
+		for viewer := range room.local {
+			viewers = append(viewers, viewer)
+		}
+	}
+	return viewers
+}
+
+// seen records a viewer announced by another instance.
+func (r *reviewRooms) seen(key roomKey, viewerID uuid.UUID, participant Participant) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room := r.rooms[key]
+	if room == nil {
+		return
+	}
+	_, known := room.remote[viewerID]
+	room.remote[viewerID] = remoteViewer{Participant: participant, seenAt: time.Now()}
+	if !known {
+		r.sendPresence(key)
+	}
+}
+
+func (r *reviewRooms) forget(key roomKey, viewerID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	room := r.rooms[key]
+	if room == nil {
+		return
+	}
+	if _, known := room.remote[viewerID]; known {
+		delete(room.remote, viewerID)
+		r.sendPresence(key)
+	}
+}
+
+// prune forgets viewers of other instances not announced since before,
+// such as those of an instance that died.
+func (r *reviewRooms) prune(before time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, room := range r.rooms {
+		changed := false
+		for id, viewer := range room.remote {
+			if viewer.seenAt.Before(before) {
+				delete(room.remote, id)
+				changed = true
+			}
+		}
+		if changed {
+			r.sendPresence(key)
+		}
+	}
+}
+
+func (r *reviewRooms) broadcast(key roomKey, message Message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if room := r.rooms[key]; room != nil {
+		for viewer := range room.local {
+			viewer.send(message)
+		}
+	}
+}
+
+// sendPresence tells the room's connections who is viewing, one entry per
+// user however many tabs they have open. r.mu must be held.
+func (r *reviewRooms) sendPresence(key roomKey) {
+	room := r.rooms[key]
+	var participants []Participant
+	add := func(participant Participant) {
+		if !slices.ContainsFunc(participants, func(p Participant) bool { return p.UserID == participant.UserID }) {
+			participants = append(participants, participant)
+		}
+	}
+	for viewer := range room.local {
+		add(viewer.Participant)
+	}
+	for _, viewer := range room.remote {
+		add(viewer.Participant)
+	}
+	slices.SortFunc(participants, func(a, b Participant) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	message := Message{Type: MessagePresence, Viewers: participants}
+	for viewer := range room.local {
+		viewer.send(message)
+	}
+}
+
+// closeAll drops every connection so clients reconnect. With stop,
+// connections joining afterwards are refused.
+func (r *reviewRooms) closeAll(stop bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, room := range r.rooms {
+		for viewer := range room.local {
+			viewer.close()
+		}
+	}
+	r.stopped = r.stopped || stop
+}
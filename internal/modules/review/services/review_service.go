@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	commentDto "testcase/internal/modules/comment/dto"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+
+	"github.com/google/uuid"
+)
+
+type ReviewService interface {
+	// Join lets the user in ctx into the review room of a document, after
+	// the same visibility check as reading it.
+	Join(ctx context.Context, documentID string) (*Viewer, error)
+	Leave(viewer *Viewer)
+	// Comment posts a document comment as the viewer, exactly as the
+	// comments endpoint does. The room sees it as a thread message once
+	// its event is published.
+	Comment(ctx context.Context, viewer *Viewer, input *commentDto.CreateCommentInput) error
+	// Publish forwards a document or comment event to the room of its
+	// document.
+	Publish(ctx context.Context, event *outboxEntities.OutboxEvent) error
+	// Run relays room traffic between instances and keeps presence fresh
+	// until ctx is cancelled, then closes every room.
+	Run(ctx context.Context)
+}
+
+// Server message types.
+const (
+	MessagePresence = "presence"
+	MessageStatus   = "status"
+	MessageThread   = "thread"
+	MessageError    = "error"
+)
+
+// Message is sent to reviewers as a JSON text frame.
+type Message struct {
+	Type    string          `json:"type"`
+	Viewers []Participant   `json:"viewers,omitempty"`
+	Event   string          `json:"event,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type Participant struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+}
+
+// Viewer is one open review connection.
+type Viewer struct {
+	ID         uuid.UUID
+	TenantID   uuid.UUID
+	DocumentID uuid.UUID
+	Participant
+
+	messages  chan Message
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Messages delivers what the connection should send.
+func (v *Viewer) Messages() <-chan Message {
+	return v.messages
+}
+
+// Done is closed when the viewer is dropped, because it fell behind or the
+// server is stopping.
+func (v *Viewer) Done() <-chan struct{} {
+	return v.done
+}
+
+func (v *Viewer) close() {
+	v.closeOnce.Do(func() { close(v.done) })
+}
+
+// send queues message, dropping the viewer when its buffer is full.
+func (v *Viewer) send(message Message) {
+	select {
+	case v.messages <- message:
+	default:
+		v.close()
+	}
+}
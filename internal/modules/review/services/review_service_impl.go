@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"testcase/config"
+	"testcase/internal/infrastructures/database"
+	commentDto "testcase/internal/modules/comment/dto"
+	commentEvents "testcase/internal/modules/comment/events"
+	commentServices "testcase/internal/modules/comment/services"
+	documentServices "testcase/internal/modules/document/services"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	outboxRepositories "testcase/internal/modules/outbox/repositories"
+	"testcase/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// reviewChannel is the PostgreSQL channel instances share room traffic on.
+const reviewChannel = "document_reviews"
+
+// listenRetryDelay is the pause before listening again after the
+// connection failed.
+const listenRetryDelay = 5 * time.Second
+
+// Notice kinds exchanged between instances.
+const (
+	noticeJoin   = "join"
+	noticeHere   = "here"
+	noticeLeave  = "leave"
+	noticeStatus = "status"
+)
+
+type notice struct {
+	Kind       string       `json:"kind"`
+	Instance   string       `json:"instance"`
+	TenantID   uuid.UUID    `json:"tenant_id"`
+	DocumentID uuid.UUID    `json:"document_id"`
+	ViewerID   uuid.UUID    `json:"viewer_id,omitempty"`
+	Viewer     *Participant `json:"viewer,omitempty"`
+	EventID    uuid.UUID    `json:"event_id,omitempty"`
+}
+
+type reviewServiceImpl struct {
+	db         *database.Database
+	documents  documentServices.DocumentService
+	comments   commentServices.CommentService
+	outboxRepo outboxRepositories.OutboxRepository
+	rooms      *reviewRooms
+	instance   string
+	config     *config.Config
+}
+
+func NewReviewService(db *database.Database, documents documentServices.DocumentService, comments commentServices.CommentService, outboxRepo outboxRepositories.OutboxRepository, cfg *config.Config) ReviewService {
+	return &reviewServiceImpl{
+		db:         db,
+		documents:  documents,
+		comments:   comments,
+		outboxRepo: outboxRepo,
+		rooms:      newReviewRooms(),
+		instance:   uuid.NewString(),
+		config:     cfg,
+	}
+}
+
+func (s *reviewServiceImpl) Join(ctx context.Context, documentID string) (*Viewer, error) {
+	userID, ok := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return nil, utils.NewAppError(utils.ErrUnauthorized, fmt.Errorf("user id missing from context"))
+	}
+	username, _ := ctx.Value(utils.UsernameContextKey).(string)
+
+	document, err := s.documents.FindById(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	viewer := &Viewer{
+		ID:          uuid.New(),
+		TenantID:    document.TenantID,
+		DocumentID:  document.ID,
+		Participant: Participant{UserID: userID, Username: username},
+		messages:    make(chan Message, s.config.Review.BufferSize),
+		done:        make(chan struct{}),
+	}
+	if s.rooms.join(viewer) {
+		s.announce(ctx, noticeJoin, viewer)
+	}
+
+	return viewer, nil
+}
+
+func (s *reviewServiceImpl) Leave(viewer *Viewer) {
+	s.rooms.leave(viewer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	s.announce(ctx, noticeLeave, viewer)
+}
+
+func (s *reviewServiceImpl) Comment(ctx context.Context, viewer *Viewer, input *commentDto.CreateCommentInput) error {
+	_, err := s.comments.CreateComment(ctx, viewer.DocumentID.String(), input)
+	return err
+}
+
+func (s *reviewServiceImpl) Publish(ctx context.Context, event *outboxEntities.OutboxEvent) error {
+	return s.send(ctx, notice{
+		Kind:       noticeStatus,
+		TenantID:   event.TenantID,
+		DocumentID: event.AggregateID,
+		EventID:    event.ID,
+	})
+}
+
+func (s *reviewServiceImpl) Run(ctx context.Context) {
+	defer s.rooms.closeAll(true)
+
+	go s.keepPresence(ctx)
+
+	for {
+		err := s.db.Listen(ctx, reviewChannel, func(payload string) {
+			s.receive(ctx, payload)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Review listener failed: %v", err)
+
+		// Presence and comments from other instances were missed; dropping
+		// the connections makes clients rejoin with a fresh view.
+		s.rooms.closeAll(false)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// keepPresence re-announces this instance's viewers so the others keep
+// them, and forgets the viewers of instances that went quiet.
+func (s *reviewServiceImpl) keepPresence(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Review.PresenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, viewer := range s.rooms.locals(nil) {
+			s.announce(ctx, noticeHere, viewer)
+		}
+		s.rooms.prune(time.Now().Add(-3 * s.config.Review.PresenceInterval))
+	}
+}
+
+func (s *reviewServiceImpl) receive(ctx context.Context, payload string) {
+	var n notice
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		log.Printf("Review listener received a malformed notice: %v", err)
+		return
+	}
+	key := roomKey{tenantID: n.TenantID, documentID: n.DocumentID}
+	// This instance applied its own join, leave and comment notices when
+	// it sent them.
+	if n.Instance == s.instance && n.Kind != noticeStatus {
+		return
+	}
+	if !s.rooms.has(key) {
+		return
+	}
+
+	switch n.Kind {
+	case noticeJoin, noticeHere:
+		if n.Viewer == nil {
+			return
+		}
+		s.rooms.seen(key, n.ViewerID, *n.Viewer)
+		if n.Kind == noticeJoin {
+			// Tell the newcomer's instance who is already here.
+			for _, viewer := range s.rooms.locals(&key) {
+				s.announce(ctx, noticeHere, viewer)
+			}
+		}
+	case noticeLeave:
+		s.rooms.forget(key, n.ViewerID)
+	case noticeStatus:
+		event, err := s.outboxRepo.FindEvent(utils.WithTenant(ctx, n.TenantID), n.EventID)
+		if err != nil {
+			log.Printf("Review listener failed to load event %s: %v", n.EventID, err)
+			return
+		}
+		if event == nil {
+			return
+		}
+		messageType := MessageStatus
+		if slices.Contains(commentEvents.Types, event.Type) {
+			messageType = MessageThread
+		}
+		s.rooms.broadcast(key, Message{Type: messageType, Event: event.Type, Data: event.Payload})
+	}
+}
+
+func (s *reviewServiceImpl) announce(ctx context.Context, kind string, viewer *Viewer) {
+	participant := viewer.Participant
+	s.notify(ctx, notice{
+		Kind:       kind,
+		TenantID:   viewer.TenantID,
+		DocumentID: viewer.DocumentID,
+		ViewerID:   viewer.ID,
+		Viewer:     &participant,
+	})
+}
+
+// notify is send for traffic that is fine to lose; the next presence round
+// repairs a missed announcement.
+func (s *reviewServiceImpl) notify(ctx context.Context, n notice) {
+	if err := s.send(ctx, n); err != nil {
+		log.Printf("Review notice failed: %v", err)
+	}
+}
+
+func (s *reviewServiceImpl) send(ctx context.Context, n notice) error {
+	n.Instance = s.instance
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return s.db.Notify(ctx, reviewChannel, string(payload))
+}
@@ -0,0 +1,12 @@
+package dto
+
+type CreateRoleInput struct {
+	Name        string   `json:"name" binding:"required,min=2,max=50,alphanum,lowercase"`
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+type UpdateRoleInput struct {
+	Description *string  `json:"description,omitempty" binding:"omitempty,max=255"`
+	Permissions []string `json:"permissions,omitempty"`
+}
@@ -0,0 +1,129 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	PermissionDocumentCreate     = "document.create"
+	PermissionDocumentRead       = "document.read"
+	PermissionDocumentReadAll    = "document.read.all"
+	PermissionDocumentResubmit   = "document.resubmit"
+	PermissionUserManage         = "user.manage"
+	PermissionAPIKeyManage       = "apikey.manage"
+	PermissionRoleManage         = "role.manage"
+	PermissionDepartmentManage   = "department.manage"
+	PermissionOrganizationManage = "organization.manage"
+	PermissionSettingsManage     = "settings.manage"
+	PermissionWebhookManage      = "webhook.manage"
+	PermissionCommentModerate    = "comment.moderate"
+	PermissionAuditRead          = "audit.read"
+)
+
+// ApprovalSteps is the number of approver levels a document goes through.
+const ApprovalSteps = 3
+
+// ApproveStepPermission is the permission to approve or reject a document
+// at the given approver level.
+func ApproveStepPermission(level int) string {
+	return fmt.Sprintf("document.approve.step%d", level)
+}
+
+// Permissions lists every permission a role can be granted.
+var Permissions = []string{
+	PermissionDocumentCreate,
+	PermissionDocumentRead,
+	PermissionDocumentReadAll,
+	PermissionDocumentResubmit,
+	ApproveStepPermission(1),
+	ApproveStepPermission(2),
+	ApproveStepPermission(3),
+	PermissionUserManage,
+	PermissionAPIKeyManage,
+	PermissionRoleManage,
+	PermissionDepartmentManage,
+	PermissionOrganizationManage,
+	PermissionSettingsManage,
+	PermissionWebhookManage,
+	PermissionCommentModerate,
+	PermissionAuditRead,
+}
+
+func IsValidPermission(permission string) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Role is a named set of permissions. Users reference roles by name, so
+// renaming is not supported; create a new role and reassign users instead.
+// System roles are seeded on startup and can be edited but not deleted.
+type Role struct {
+	Name        string    `gorm:"type:varchar(50);primary_key" json:"name"`
+	Description string    `gorm:"type:varchar(255)" json:"description"`
+	Permissions []string  `gorm:"type:jsonb;serializer:json;not null" json:"permissions"`
+	IsSystem    bool      `gorm:"default:false" json:"is_system"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (r *Role) TableName() string {
+	return "roles"
+}
+
+func (r *Role) HasPermission(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+var documentBasics = []string{PermissionDocumentCreate, PermissionDocumentRead, PermissionDocumentResubmit}
+
+// DefaultRoles reproduce the access the built-in roles had before roles
+// were configurable. They are only inserted when missing, so later edits
+// survive restarts.
+var DefaultRoles = []Role{
+	{
+		Name:        "admin",
+		Description: "Manages users, API keys, roles and departments",
+		Permissions: append(append([]string{}, documentBasics...), PermissionDocumentReadAll, PermissionUserManage, PermissionAPIKeyManage, PermissionRoleManage, PermissionDepartmentManage, PermissionOrganizationManage, PermissionSettingsManage, PermissionWebhookManage, PermissionCommentModerate),
+		IsSystem:    true,
+	},
+	{
+		Name:        "admin1",
+		Description: "First-level document approver",
+		Permissions: append(append([]string{}, documentBasics...), ApproveStepPermission(1)),
+		IsSystem:    true,
+	},
+	{
+		Name:        "admin2",
+		Description: "Second-level document approver",
+		Permissions: append(append([]string{}, documentBasics...), ApproveStepPermission(2)),
+		IsSystem:    true,
+	},
+	{
+		Name:        "admin3",
+		Description: "Third-level document approver",
+		Permissions: append(append([]string{}, documentBasics...), ApproveStepPermission(3)),
+		IsSystem:    true,
+	},
+	{
+		Name:        "user",
+		Description: "Creates and resubmits documents",
+		Permissions: append([]string{}, documentBasics...),
+		IsSystem:    true,
+	},
+	{
+		Name:        "auditor",
+		Description: "Reads the audit log",
+		Permissions: []string{PermissionAuditRead},
+		IsSystem:    true,
+	},
+}
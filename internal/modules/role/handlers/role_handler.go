@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/role/dto"
+	"testcase/internal/modules/role/services"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	roleService services.RoleService
+}
+
+func NewRoleHandler(roleService services.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, roles, "Roles retrieved successfully", http.StatusOK)
+}
+
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	utils.SuccessResponse(c, h.roleService.ListPermissions(), "Permissions retrieved successfully", http.StatusOK)
+}
+
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	role, err := h.roleService.GetRole(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, role, "Role retrieved successfully", http.StatusOK)
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var input dto.CreateRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	role, err := h.roleService.CreateRole(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, role, "Role created successfully", http.StatusCreated)
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var input dto.UpdateRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c.Request.Context(), c.Param("name"), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, role, "Role updated successfully", http.StatusOK)
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	if err := h.roleService.DeleteRole(c.Request.Context(), c.Param("name")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Role deleted successfully", http.StatusOK)
+}
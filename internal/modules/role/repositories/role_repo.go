@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/role/entities"
+)
+
+type RoleRepository interface {
+	ListRoles(ctx context.Context) ([]entities.Role, error)
+	FindByName(ctx context.Context, name string) (*entities.Role, error)
+	CreateRole(ctx context.Context, role *entities.Role) error
+	UpdateRole(ctx context.Context, role *entities.Role) error
+	DeleteRole(ctx context.Context, name string) error
+	CountUsers(ctx context.Context, name string) (int64, error)
+	SeedRoles(ctx context.Context, roles []entities.Role) error
+}
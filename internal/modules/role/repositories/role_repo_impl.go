@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/role/entities"
+	userEntities "testcase/internal/modules/user/entities"
+	"testcase/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type roleRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewRoleRepository(db *database.Database) RoleRepository {
+	return &roleRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *roleRepositoryImpl) ListRoles(ctx context.Context) ([]entities.Role, error) {
+	var roles []entities.Role
+
+	err := r.db.WithContext(ctx).Order("name asc").Find(&roles).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// FindByName returns nil without an error when the role does not exist.
+func (r *roleRepositoryImpl) FindByName(ctx context.Context, name string) (*entities.Role, error) {
+	var role entities.Role
+
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find role by name: %w", err)
+	}
+
+	return &role, nil
+}
+
+func (r *roleRepositoryImpl) CreateRole(ctx context.Context, role *entities.Role) error {
+	err := r.db.WithContext(ctx).Create(role).Error
+	if err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepositoryImpl) UpdateRole(ctx context.Context, role *entities.Role) error {
+	err := r.db.WithContext(ctx).Save(role).Error
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleRepositoryImpl) DeleteRole(ctx context.Context, name string) error {
+	err := r.db.WithContext(ctx).Where("name = ?", name).Delete(&entities.Role{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return nil
+}
+
+// CountUsers counts the users, including soft-deleted ones, that still
+// reference the role. Roles are shared by every organization, so the count
+// crosses tenants.
+func (r *roleRepositoryImpl) CountUsers(ctx context.Context, name string) (int64, error) {
+	var total int64
+
+	err := r.db.WithContext(utils.WithoutTenantScope(ctx)).
+		Model(&userEntities.User{}).
+		Unscoped().
+		Where("role = ?", name).
+		Count(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users with role: %w", err)
+	}
+
+	return total, nil
+}
+
+// SeedRoles inserts the roles that do not exist yet and leaves existing
+// ones untouched.
+func (r *roleRepositoryImpl) SeedRoles(ctx context.Context, roles []entities.Role) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&roles).Error
+	if err != nil {
+		return fmt.Errorf("failed to seed roles: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/infrastructures/database/databasetest"
+	"testcase/internal/utils"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestCountUsersCountsEveryTenant(t *testing.T) {
+	db, mock := databasetest.NewMock(t)
+	repo := NewRoleRepository(db)
+	ctx := utils.WithTenant(context.Background(), uuid.New())
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "users" WHERE role = \$1$`).
+		WithArgs("auditor").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	total, err := repo.CountUsers(ctx, "auditor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 {
+		t.Fatalf("expected 3 users, got %d", total)
+	}
+}
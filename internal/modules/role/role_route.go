@@ -0,0 +1,23 @@
+package role
+
+import (
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/role/entities"
+	"testcase/internal/modules/role/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoleRoutes(rg *gin.RouterGroup, h *handlers.RoleHandler, authMware *middlewares.AuthMiddleware) {
+
+	roleRoutes := rg.Group("/roles")
+	roleRoutes.Use(authMware.Auth(), authMware.RequirePlatformOrganization(), authMware.RequirePermission(entities.PermissionRoleManage))
+	{
+		roleRoutes.GET("", h.ListRoles)
+		roleRoutes.GET("/permissions", h.ListPermissions)
+		roleRoutes.GET("/:name", h.GetRole)
+		roleRoutes.POST("", h.CreateRole)
+		roleRoutes.PUT("/:name", h.UpdateRole)
+		roleRoutes.DELETE("/:name", h.DeleteRole)
+	}
+}
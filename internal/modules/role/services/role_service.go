@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/modules/role/dto"
+	"testcase/internal/modules/role/entities"
+)
+
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]entities.Role, error)
+	ListPermissions() []string
+	GetRole(ctx context.Context, name string) (*entities.Role, error)
+	CreateRole(ctx context.Context, input *dto.CreateRoleInput) (*entities.Role, error)
+	UpdateRole(ctx context.Context, name string, input *dto.UpdateRoleInput) (*entities.Role, error)
+	DeleteRole(ctx context.Context, name string) error
+	RoleHasPermissions(ctx context.Context, role string, permissions ...string) (bool, error)
+	RolesWithPermission(ctx context.Context, permission string) ([]string, error)
+	EnsureDefaultRoles(ctx context.Context) error
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	auditEntities "testcase/internal/modules/audit/entities"
+	auditServices "testcase/internal/modules/audit/services"
+	"testcase/internal/modules/role/dto"
+	"testcase/internal/modules/role/entities"
+	"testcase/internal/modules/role/repositories"
+	"testcase/internal/utils"
+)
+
+type roleServiceImpl struct {
+	roleRepo repositories.RoleRepository
+	audit    auditServices.AuditService
+}
+
+func NewRoleService(roleRepo repositories.RoleRepository, audit auditServices.AuditService) RoleService {
+	return &roleServiceImpl{
+		roleRepo: roleRepo,
+		audit:    audit,
+	}
+}
+
+func (r *roleServiceImpl) ListRoles(ctx context.Context) ([]entities.Role, error) {
+	roles, err := r.roleRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return roles, nil
+}
+
+func (r *roleServiceImpl) ListPermissions() []string {
+	return entities.Permissions
+}
+
+func (r *roleServiceImpl) GetRole(ctx context.Context, name string) (*entities.Role, error) {
+	role, err := r.roleRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if role == nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrNotFound, fmt.Errorf("role %s not found", name), "Role not found")
+	}
+
+	return role, nil
+}
+
+func (r *roleServiceImpl) CreateRole(ctx context.Context, input *dto.CreateRoleInput) (*entities.Role, error) {
+	permissions, err := normalizePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := r.roleRepo.FindByName(ctx, input.Name)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if existing != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrConflict, fmt.Errorf("role %s already exists", input.Name), "Role already exists")
+	}
+
+	role := &entities.Role{
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: permissions,
+	}
+	if err := r.roleRepo.CreateRole(ctx, role); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	r.recordAudit(ctx, auditEntities.ActionRoleCreated, role.Name, map[string]string{"permissions": strings.Join(role.Permissions, ",")})
+
+	return role, nil
+}
+
+// UpdateRole changes a role's description or permissions. The change applies
+// to its users' next request, no new login needed.
+func (r *roleServiceImpl) UpdateRole(ctx context.Context, name string, input *dto.UpdateRoleInput) (*entities.Role, error) {
+	role, err := r.GetRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := strings.Join(role.Permissions, ",")
+	if input.Description != nil {
+		role.Description = *input.Description
+	}
+	if input.Permissions != nil {
+		permissions, err := normalizePermissions(input.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		// Stop admins from locking everyone, themselves included, out of
+		// role management.
+		if r.isCallerRole(ctx, name) && role.HasPermission(entities.PermissionRoleManage) && !containsPermission(permissions, entities.PermissionRoleManage) {
+			return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("cannot drop %s from own role", entities.PermissionRoleManage), "You cannot remove role.manage from your own role")
+		}
+		role.Permissions = permissions
+	}
+
+	if err := r.roleRepo.UpdateRole(ctx, role); err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+	r.recordAudit(ctx, auditEntities.ActionRoleUpdated, role.Name, map[string]string{"from": previous, "to": strings.Join(role.Permissions, ",")})
+
+	return role, nil
+}
+
+func (r *roleServiceImpl) DeleteRole(ctx context.Context, name string) error {
+	role, err := r.GetRole(ctx, name)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("role %s is a system role", name), "System roles cannot be deleted")
+	}
+
+	users, err := r.roleRepo.CountUsers(ctx, name)
+	if err != nil {
+		return utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if users > 0 {
+		return utils.NewAppErrorWithMessage(utils.ErrConflict, fmt.Errorf("role %s is assigned to %d users", name, users), "Role is still assigned to users")
+	}
+
+	if err := r.roleRepo.DeleteRole(ctx, name); err != nil {
+		return utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	r.recordAudit(ctx, auditEntities.ActionRoleDeleted, name, map[string]string{"permissions": strings.Join(role.Permissions, ",")})
+
+	return nil
+}
+
+// RoleHasPermissions reports whether the role grants every permission.
+// Unknown roles grant nothing.
+func (r *roleServiceImpl) RoleHasPermissions(ctx context.Context, roleName string, permissions ...string) (bool, error) {
+	role, err := r.roleRepo.FindByName(ctx, roleName)
+	if err != nil {
+		return false, err
+	}
+	if role == nil {
+		return false, nil
+	}
+
+	for _, permission := range permissions {
+		if !role.HasPermission(permission) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r *roleServiceImpl) RolesWithPermission(ctx context.Context, permission string) ([]string, error) {
+	roles, err := r.roleRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, role := range roles {
+		if role.HasPermission(permission) {
+			names = append(names, role.Name)
+		}
+	}
+
+	return names, nil
+}
+
+func (r *roleServiceImpl) EnsureDefaultRoles(ctx context.Context) error {
+	return r.roleRepo.SeedRoles(ctx, entities.DefaultRoles)
+}
+
+// recordAudit logs a role change. A failure is logged rather than
+// returned: the change is already saved.
+func (r *roleServiceImpl) recordAudit(ctx context.Context, action, name string, metadata map[string]string) {
+	err := r.audit.Record(ctx, &auditEntities.AuditLog{
+		Action:     action,
+		TargetType: "role",
+		TargetID:   name,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		log.Printf("Failed to record %s audit entry: %v", action, err)
+	}
+}
+
+func (r *roleServiceImpl) isCallerRole(ctx context.Context, name string) bool {
+	callerRole, _ := ctx.Value(utils.RoleContextKey).(string)
+	return callerRole == name
+}
+
+// normalizePermissions rejects unknown permissions and drops duplicates.
+func normalizePermissions(permissions []string) ([]string, error) {
+	normalized := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !entities.IsValidPermission(permission) {
+			return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("unknown permission %q", permission), fmt.Sprintf("Unknown permission %q", permission))
+		}
+		if !containsPermission(normalized, permission) {
+			normalized = append(normalized, permission)
+		}
+	}
+
+	return normalized, nil
+}
+
+func containsPermission(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "encoding/json"
+
+// UpdateSettingsInput replaces the organization's overrides. Send {} to go
+// back to the global defaults.
+type UpdateSettingsInput struct {
+	Overrides json.RawMessage `json:"overrides" binding:"required"`
+}
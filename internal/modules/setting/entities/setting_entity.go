@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantSetting holds the settings an organization changed. Overrides is a
+// partial Settings document; anything it leaves out follows the global
+// defaults, so later changes to those still reach the organization.
+type TenantSetting struct {
+	TenantID  uuid.UUID       `gorm:"type:uuid;primaryKey" json:"tenant_id"`
+	Overrides json.RawMessage `gorm:"type:jsonb;serializer:json;not null" json:"overrides"`
+	UpdatedBy *uuid.UUID      `gorm:"type:uuid" json:"updated_by,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (s *TenantSetting) TableName() string {
+	return "tenant_settings"
+}
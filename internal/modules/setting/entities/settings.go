@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	roleEntities "testcase/internal/modules/role/entities"
+)
+
+// EmailTemplateVerification is the email sent to confirm an address.
+const EmailTemplateVerification = "email_verification"
+
+// EmailTemplates lists every template an organization can override.
+var EmailTemplates = []string{
+	EmailTemplateVerification,
+}
+
+// bcrypt ignores everything past 72 bytes, so longer minimums can't be met
+// meaningfully.
+const maxPasswordMinLength = 72
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Settings is the effective configuration of an organization: the global
+// defaults with the organization's overrides applied. Values returned by
+// the settings service are shared and must not be modified.
+type Settings struct {
+	Approval       ApprovalSettings         `json:"approval"`
+	PasswordPolicy PasswordPolicy           `json:"password_policy"`
+	MFA            MFASettings              `json:"mfa"`
+	SLA            SLASettings              `json:"sla"`
+	Branding       Branding                 `json:"branding"`
+	EmailTemplates map[string]EmailTemplate `json:"email_templates"`
+}
+
+// ApprovalSettings configures the document workflow. StepRules uses the
+// APPROVAL_STEP_RULES format keyed by step number.
+type ApprovalSettings struct {
+	StepRules    map[string]string `json:"step_rules"`
+	StepUpLevels []int             `json:"step_up_levels"`
+}
+
+func (a ApprovalSettings) StepUpRequiredForLevel(level int) bool {
+	for _, l := range a.StepUpLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+type PasswordPolicy struct {
+	MinLength        int  `json:"min_length"`
+	RequireUppercase bool `json:"require_uppercase"`
+	RequireLowercase bool `json:"require_lowercase"`
+	RequireDigit     bool `json:"require_digit"`
+	RequireSymbol    bool `json:"require_symbol"`
+}
+
+// Check returns a message describing the first rule the password breaks,
+// or an empty string when it satisfies the policy.
+func (p PasswordPolicy) Check(password string) string {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	switch {
+	case len([]rune(password)) < p.MinLength:
+		return fmt.Sprintf("Password must be at least %d characters long", p.MinLength)
+	case p.RequireUppercase && !upper:
+		return "Password must contain an uppercase letter"
+	case p.RequireLowercase && !lower:
+		return "Password must contain a lowercase letter"
+	case p.RequireDigit && !digit:
+		return "Password must contain a digit"
+	case p.RequireSymbol && !symbol:
+		return "Password must contain a symbol"
+	}
+	return ""
+}
+
+type MFASettings struct {
+	RequiredRoles []string `json:"required_roles"`
+}
+
+func (m MFASettings) RequiredForRole(role string) bool {
+	for _, r := range m.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// SLASettings sets how long each approval step may take. Zero disables the
+// deadline.
+type SLASettings struct {
+	ApprovalStepHours int `json:"approval_step_hours"`
+}
+
+func (s SLASettings) ApprovalStepDuration() time.Duration {
+	return time.Duration(s.ApprovalStepHours) * time.Hour
+}
+
+type Branding struct {
+	DisplayName  string `json:"display_name"`
+	LogoURL      string `json:"logo_url"`
+	PrimaryColor string `json:"primary_color"`
+}
+
+// EmailTemplate is a text/template pair rendered with EmailData.
+type EmailTemplate struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// EmailData is available to every email template. Fields that don't apply
+// to a template are left empty.
+type EmailData struct {
+	Brand  string
+	Name   string
+	Link   string
+	Expiry string
+}
+
+func (t EmailTemplate) Render(data EmailData) (string, string, error) {
+	subject, err := renderTemplate("subject", t.Subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := renderTemplate("body", t.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	return subject, body, nil
+}
+
+// Template returns the named template. Defaults define every template, so
+// a missing one means the settings were built incorrectly.
+func (s *Settings) Template(name string) (EmailTemplate, error) {
+	tmpl, ok := s.EmailTemplates[name]
+	if !ok {
+		return EmailTemplate{}, fmt.Errorf("email template %s is not defined", name)
+	}
+	return tmpl, nil
+}
+
+// Validate checks the values a caller can't express through JSON types
+// alone.
+func (s *Settings) Validate() error {
+	var errs []error
+
+	if s.PasswordPolicy.MinLength < 1 || s.PasswordPolicy.MinLength > maxPasswordMinLength {
+		errs = append(errs, fmt.Errorf("password_policy.min_length must be between 1 and %d", maxPasswordMinLength))
+	}
+	for _, level := range s.Approval.StepUpLevels {
+		if level < 1 || level > roleEntities.ApprovalSteps {
+			errs = append(errs, fmt.Errorf("approval.step_up_levels: invalid step %d", level))
+		}
+	}
+	if s.SLA.ApprovalStepHours < 0 {
+		errs = append(errs, errors.New("sla.approval_step_hours must not be negative"))
+	}
+	if s.Branding.PrimaryColor != "" && !colorPattern.MatchString(s.Branding.PrimaryColor) {
+		errs = append(errs, errors.New("branding.primary_color must be a hex color such as #1a2b3c"))
+	}
+
+	for name, tmpl := range s.EmailTemplates {
+		if !isEmailTemplate(name) {
+			errs = append(errs, fmt.Errorf("email_templates: unknown template %q", name))
+			continue
+		}
+		if strings.TrimSpace(tmpl.Subject) == "" || strings.TrimSpace(tmpl.Body) == "" {
+			errs = append(errs, fmt.Errorf("email_templates.%s: subject and body are required", name))
+			continue
+		}
+		if _, _, err := tmpl.Render(EmailData{}); err != nil {
+			errs = append(errs, fmt.Errorf("email_templates.%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func isEmailTemplate(name string) bool {
+	for _, n := range EmailTemplates {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func renderTemplate(name, text string, data EmailData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
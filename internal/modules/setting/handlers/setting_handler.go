@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/setting/dto"
+	"testcase/internal/modules/setting/services"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingHandler struct {
+	settingService services.SettingService
+}
+
+func NewSettingHandler(settingService services.SettingService) *SettingHandler {
+	return &SettingHandler{
+		settingService: settingService,
+	}
+}
+
+func (h *SettingHandler) GetSettings(c *gin.Context) {
+	settings, err := h.settingService.GetSettings(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, settings, "Settings retrieved successfully", http.StatusOK)
+}
+
+func (h *SettingHandler) UpdateSettings(c *gin.Context) {
+	var input dto.UpdateSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	settings, err := h.settingService.UpdateSettings(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, settings, "Settings updated successfully", http.StatusOK)
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/setting/entities"
+)
+
+type SettingRepository interface {
+	FindForTenant(ctx context.Context) (*entities.TenantSetting, error)
+	SaveSetting(ctx context.Context, setting *entities.TenantSetting) error
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/setting/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type settingRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewSettingRepository(db *database.Database) SettingRepository {
+	return &settingRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindForTenant returns the overrides of the organization in ctx, or nil
+// when it has never changed a setting.
+func (r *settingRepositoryImpl) FindForTenant(ctx context.Context) (*entities.TenantSetting, error) {
+	var setting entities.TenantSetting
+
+	err := r.db.WithContext(ctx).First(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tenant settings: %w", err)
+	}
+
+	return &setting, nil
+}
+
+func (r *settingRepositoryImpl) SaveSetting(ctx context.Context, setting *entities.TenantSetting) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"overrides", "updated_by", "updated_at"}),
+		}).
+		Create(setting).Error
+	if err != nil {
+		return fmt.Errorf("failed to save tenant settings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package responses
+
+import (
+	"encoding/json"
+	"testcase/internal/modules/setting/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettingsResponse shows the effective settings next to the overrides they
+// were built from.
+type SettingsResponse struct {
+	Settings  entities.Settings `json:"settings"`
+	Overrides json.RawMessage   `json:"overrides"`
+	UpdatedBy *uuid.UUID        `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"`
+}
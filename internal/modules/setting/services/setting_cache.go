@@ -0,0 +1,66 @@
+package services
+
+import (
+	"sync"
+	"testcase/internal/modules/setting/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type cachedSettings struct {
+	settings  *entities.Settings
+	expiresAt time.Time
+}
+
+// settingCache keeps resolved settings per organization for a short while
+// so every request doesn't hit the database.
+type settingCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[uuid.UUID]cachedSettings
+}
+
+func newSettingCache(ttl time.Duration) *settingCache {
+	return &settingCache{
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]cachedSettings),
+	}
+}
+
+func (c *settingCache) get(tenantID uuid.UUID) (*entities.Settings, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[tenantID]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, tenantID)
+		return nil, false
+	}
+	return entry.settings, true
+}
+
+func (c *settingCache) put(tenantID uuid.UUID, settings *entities.Settings) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[tenantID] = cachedSettings{settings: settings, expiresAt: time.Now().Add(c.ttl)}
+}
+
+func (c *settingCache) invalidate(tenantID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, tenantID)
+}
+
+func (c *settingCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
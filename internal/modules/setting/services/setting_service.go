@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/modules/setting/dto"
+	"testcase/internal/modules/setting/entities"
+	"testcase/internal/modules/setting/responses"
+)
+
+// Validator checks settings owned by another module, such as approval
+// rules, before they are saved.
+type Validator func(settings *entities.Settings) error
+
+type SettingService interface {
+	// ForTenant returns the effective settings of the organization in ctx,
+	// or the global defaults when ctx has no organization.
+	ForTenant(ctx context.Context) (*entities.Settings, error)
+	GetSettings(ctx context.Context) (*responses.SettingsResponse, error)
+	UpdateSettings(ctx context.Context, input *dto.UpdateSettingsInput) (*responses.SettingsResponse, error)
+	// Run drops cached settings updated on other instances until ctx is
+	// cancelled.
+	Run(ctx context.Context)
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"testcase/config"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/setting/dto"
+	"testcase/internal/modules/setting/entities"
+	"testcase/internal/modules/setting/repositories"
+	"testcase/internal/modules/setting/responses"
+	"testcase/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// settingsChannel is the PostgreSQL channel instances announce updated
+// organizations on, so each drops its cached copy.
+const settingsChannel = "setting_updates"
+
+// listenRetryDelay is the pause before listening again after the
+// connection failed.
+const listenRetryDelay = 5 * time.Second
+
+type settingServiceImpl struct {
+	db          *database.Database
+	settingRepo repositories.SettingRepository
+	defaults    entities.Settings
+	validators  []Validator
+	cache       *settingCache
+}
+
+// NewSettingService builds the global defaults from cfg. Validators run on
+// every update in addition to the settings' own checks.
+func NewSettingService(db *database.Database, settingRepo repositories.SettingRepository, cfg *config.Config, validators ...Validator) SettingService {
+	return &settingServiceImpl{
+		db:          db,
+		settingRepo: settingRepo,
+		defaults:    defaultSettings(cfg),
+		validators:  validators,
+		cache:       newSettingCache(cfg.Settings.CacheTTL),
+	}
+}
+
+func defaultSettings(cfg *config.Config) entities.Settings {
+	return entities.Settings{
+		Approval: entities.ApprovalSettings{
+			StepRules:    cfg.Approval.StepRules,
+			StepUpLevels: cfg.StepUp.ApprovalLevels,
+		},
+		PasswordPolicy: entities.PasswordPolicy{
+			MinLength:        cfg.PasswordPolicy.MinLength,
+			RequireUppercase: cfg.PasswordPolicy.RequireUppercase,
+			RequireLowercase: cfg.PasswordPolicy.RequireLowercase,
+			RequireDigit:     cfg.PasswordPolicy.RequireDigit,
+			RequireSymbol:    cfg.PasswordPolicy.RequireSymbol,
+		},
+		MFA: entities.MFASettings{
+			RequiredRoles: cfg.MFA.RequiredRoles,
+		},
+		SLA: entities.SLASettings{
+			ApprovalStepHours: cfg.Approval.StepSLAHours,
+		},
+		Branding: entities.Branding{
+			DisplayName:  cfg.Branding.DisplayName,
+			LogoURL:      cfg.Branding.LogoURL,
+			PrimaryColor: cfg.Branding.PrimaryColor,
+		},
+		EmailTemplates: map[string]entities.EmailTemplate{
+			entities.EmailTemplateVerification: {
+				Subject: "Verify your email address",
+				Body:    "Hi {{.Name}},\n\nPlease verify your email address by opening the link below:\n{{.Link}}\n\nThe link expires in {{.Expiry}}.",
+			},
+		},
+	}
+}
+
+func (s *settingServiceImpl) ForTenant(ctx context.Context) (*entities.Settings, error) {
+	tenantID, ok := ctx.Value(utils.TenantIDContextKey).(uuid.UUID)
+	if !ok || tenantID == uuid.Nil {
+		return s.resolve(nil)
+	}
+	if settings, ok := s.cache.get(tenantID); ok {
+		return settings, nil
+	}
+
+	stored, err := s.settingRepo.FindForTenant(ctx)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	var overrides json.RawMessage
+	if stored != nil {
+		overrides = stored.Overrides
+	}
+
+	settings, err := s.resolve(overrides)
+	if err != nil {
+		// Stored overrides were valid when saved; failing here means the
+		// schema changed underneath them.
+		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("tenant %s settings: %w", tenantID, err))
+	}
+	s.cache.put(tenantID, settings)
+
+	return settings, nil
+}
+
+func (s *settingServiceImpl) GetSettings(ctx context.Context) (*responses.SettingsResponse, error) {
+	settings, err := s.ForTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.settingRepo.FindForTenant(ctx)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	response := &responses.SettingsResponse{
+		Settings:  *settings,
+		Overrides: json.RawMessage("{}"),
+	}
+	if stored != nil {
+		response.Overrides = stored.Overrides
+		response.UpdatedBy = stored.UpdatedBy
+		response.UpdatedAt = &stored.UpdatedAt
+	}
+
+	return response, nil
+}
+
+func (s *settingServiceImpl) UpdateSettings(ctx context.Context, input *dto.UpdateSettingsInput) (*responses.SettingsResponse, error) {
+	tenantID, ok := ctx.Value(utils.TenantIDContextKey).(uuid.UUID)
+	if !ok || tenantID == uuid.Nil {
+		return nil, utils.NewAppError(utils.ErrUnauthorized, fmt.Errorf("tenant id missing from context"))
+	}
+
+	overrides := bytes.TrimSpace(input.Overrides)
+	if len(overrides) == 0 || overrides[0] != '{' {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("overrides is not a JSON object"), "Overrides must be a JSON object")
+	}
+
+	settings, err := s.resolve(overrides)
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, err, fmt.Sprintf("Invalid settings: %v", err))
+	}
+	if err := s.validate(settings); err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, err, fmt.Sprintf("Invalid settings: %v", err))
+	}
+
+	setting := &entities.TenantSetting{
+		TenantID:  tenantID,
+		Overrides: overrides,
+		UpdatedAt: time.Now(),
+	}
+	if userID, ok := ctx.Value(utils.UserIDContextKey).(uuid.UUID); ok {
+		setting.UpdatedBy = &userID
+	}
+	if err := s.settingRepo.SaveSetting(ctx, setting); err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+	s.cache.invalidate(tenantID)
+	// The update is saved either way; other instances pick it up within
+	// the cache TTL if the notification is lost.
+	if err := s.db.Notify(ctx, settingsChannel, tenantID.String()); err != nil {
+		log.Printf("Failed to announce settings update of %s: %v", tenantID, err)
+	}
+
+	return &responses.SettingsResponse{
+		Settings:  *settings,
+		Overrides: setting.Overrides,
+		UpdatedBy: setting.UpdatedBy,
+		UpdatedAt: &setting.UpdatedAt,
+	}, nil
+}
+
+func (s *settingServiceImpl) Run(ctx context.Context) {
+	for {
+		err := s.db.Listen(ctx, settingsChannel, func(payload string) {
+			tenantID, err := uuid.Parse(payload)
+			if err != nil {
+				log.Printf("Ignoring malformed settings notification %q: %v", payload, err)
+				return
+			}
+			s.cache.invalidate(tenantID)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Settings listener failed: %v", err)
+
+		// Updates announced while nobody listened are missed, so nothing
+		// cached can be trusted any more.
+		s.cache.clear()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// resolve applies overrides on top of a copy of the defaults. Objects are
+// merged key by key, while lists and scalar values replace the default.
+// Unknown keys are rejected so typos don't silently fall back.
+func (s *settingServiceImpl) resolve(overrides json.RawMessage) (*entities.Settings, error) {
+	defaults, err := json.Marshal(s.defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings entities.Settings
+	if err := json.Unmarshal(defaults, &settings); err != nil {
+		return nil, err
+	}
+	if len(overrides) == 0 {
+		return &settings, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(overrides))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (s *settingServiceImpl) validate(settings *entities.Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	for _, validator := range s.validators {
+		if err := validator(settings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
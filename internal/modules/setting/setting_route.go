@@ -0,0 +1,19 @@
+package setting
+
+import (
+	"testcase/internal/middlewares"
+	roleEntities "testcase/internal/modules/role/entities"
+	"testcase/internal/modules/setting/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSettingRoutes(rg *gin.RouterGroup, h *handlers.SettingHandler, authMware *middlewares.AuthMiddleware) {
+
+	settingRoutes := rg.Group("/settings")
+	settingRoutes.Use(authMware.Auth(), authMware.RequirePermission(roleEntities.PermissionSettingsManage))
+	{
+		settingRoutes.GET("", h.GetSettings)
+		settingRoutes.PUT("", h.UpdateSettings)
+	}
+}
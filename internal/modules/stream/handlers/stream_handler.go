@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testcase/internal/modules/stream/services"
+	"testcase/internal/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StreamHandler struct {
+	streamService services.StreamService
+}
+
+func NewStreamHandler(streamService services.StreamService) *StreamHandler {
+	return &StreamHandler{
+		streamService: streamService,
+	}
+}
+
+// Stream keeps the connection open and writes document events as
+// Server-Sent Events, with a comment line every heartbeat so proxies don't
+// close it as idle. Each heartbeat also checks that the credential is still
+// valid and ends the stream once it was revoked.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	stream, err := h.streamService.Open(c.Request.Context(), c.GetHeader("Last-Event-ID"))
+	if err != nil {
+		panic(err)
+	}
+	defer h.streamService.Close(stream)
+
+	// The server's write timeout is meant for ordinary requests.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if stream.Reset {
+		writeMessage(c.Writer, services.Message{Event: "reset", Data: []byte("{}")})
+	}
+	for _, message := range stream.Replay {
+		writeMessage(c.Writer, message)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(stream.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-stream.Done():
+			return
+		case message := <-stream.Events():
+			if stream.Replayed(message.ID) {
+				continue
+			}
+			writeMessage(c.Writer, message)
+		case <-heartbeat.C:
+			if err := utils.CheckAccess(c.Request.Context()); err != nil {
+				writeMessage(c.Writer, services.Message{Event: "revoked", Data: []byte("{}")})
+				c.Writer.Flush()
+				return
+			}
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeMessage(w io.Writer, message services.Message) {
+	if message.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", message.ID)
+	}
+	fmt.Fprintf(w, "event: %s\n", message.Event)
+	for _, line := range bytes.Split(message.Data, []byte("\n")) {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// streamHub holds the streams connected to this instance, by organization.
+type streamHub struct {
+	mu      sync.RWMutex
+	streams map[uuid.UUID]map[*Stream]struct{}
+	stopped bool
+}
+
+func newStreamHub() *streamHub {
+	return &streamHub{
+		streams: make(map[uuid.UUID]map[*Stream]struct{}),
+	}
+}
+
+func (h *streamHub) subscribe(stream *Stream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stopped {
+		stream.close()
+		return
+	}
+	if h.streams[stream.TenantID] == nil {
+		h.streams[stream.TenantID] = make(map[*Stream]struct{})
+	}
+	h.streams[stream.TenantID][stream] = struct{}{}
+}
+
+func (h *streamHub) unsubscribe(stream *Stream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.streams[stream.TenantID], stream)
+	if len(h.streams[stream.TenantID]) == 0 {
+		delete(h.streams, stream.TenantID)
+	}
+	stream.close()
+}
+
+func (h *streamHub) hasStreams(tenantID uuid.UUID) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.streams[tenantID]) > 0
+}
+
+// broadcast hands message to the organization's streams whose user is in
+// audience. A stream whose buffer is full is dropped rather than holding
+// up the others.
+func (h *streamHub) broadcast(tenantID uuid.UUID, audience []uuid.UUID, message Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for stream := range h.streams[tenantID] {
+		if !slices.Contains(audience, stream.UserID) {
+			continue
+		}
+		select {
+		case stream.events <- message:
+		default:
+			stream.close()
+		}
+	}
+}
+
+// closeAll drops every stream so clients reconnect and replay. With stop,
+// streams opened afterwards are closed straight away.
+func (h *streamHub) closeAll(stop bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, streams := range h.streams {
+		for stream := range streams {
+			stream.close()
+		}
+	}
+	h.stopped = h.stopped || stop
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AudienceFunc lists the users who may see an event.
+type AudienceFunc func(ctx context.Context, event *outboxEntities.OutboxEvent) ([]uuid.UUID, error)
+
+type StreamService interface {
+	// Publish announces an outbox event to every instance, which forwards
+	// it to the streams of its audience.
+	Publish(ctx context.Context, event *outboxEntities.OutboxEvent) error
+	// Run listens for published events until ctx is cancelled, then closes
+	// every open stream.
+	Run(ctx context.Context)
+	// Open starts a stream for the user in ctx. With lastEventID it first
+	// replays what the user missed since that event.
+	Open(ctx context.Context, lastEventID string) (*Stream, error)
+	Close(stream *Stream)
+}
+
+// Message is one Server-Sent Event.
+type Message struct {
+	ID    string
+	Event string
+	Data  json.RawMessage
+}
+
+// Stream is one connected client. Replay holds the events missed before
+// it connected; Reset is set when they can't be replayed and the client
+// should reload its state instead.
+type Stream struct {
+	TenantID  uuid.UUID
+	UserID    uuid.UUID
+	Heartbeat time.Duration
+	Replay    []Message
+	Reset     bool
+
+	replayed  map[string]struct{}
+	events    chan Message
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newStream(tenantID, userID uuid.UUID, bufferSize int, heartbeat time.Duration) *Stream {
+	return &Stream{
+		TenantID:  tenantID,
+		UserID:    userID,
+		Heartbeat: heartbeat,
+		replayed:  make(map[string]struct{}),
+		events:    make(chan Message, bufferSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// Events delivers live events.
+func (s *Stream) Events() <-chan Message {
+	return s.events
+}
+
+// Done is closed when the stream is dropped, either because the client
+// fell behind or the server is stopping. The client should reconnect with
+// the last event ID it saw.
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Replayed reports whether the event with id was already sent as part of
+// Replay; it can arrive live too when it was published during the replay.
+func (s *Stream) Replayed(id string) bool {
+	_, ok := s.replayed[id]
+	return ok
+}
+
+func (s *Stream) close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"testcase/config"
+	"testcase/internal/infrastructures/database"
+	documentEvents "testcase/internal/modules/document/events"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	outboxRepositories "testcase/internal/modules/outbox/repositories"
+	"testcase/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// streamChannel is the PostgreSQL channel instances announce events on.
+const streamChannel = "document_events"
+
+// listenRetryDelay is the pause before listening again after the
+// connection failed.
+const listenRetryDelay = 5 * time.Second
+
+// notice is the NOTIFY payload. It only names the event, since payloads
+// are limited to 8000 bytes; instances load the event themselves.
+type notice struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+}
+
+type streamServiceImpl struct {
+	db         *database.Database
+	outboxRepo outboxRepositories.OutboxRepository
+	audience   AudienceFunc
+	hub        *streamHub
+	config     *config.Config
+}
+
+func NewStreamService(db *database.Database, outboxRepo outboxRepositories.OutboxRepository, audience AudienceFunc, cfg *config.Config) StreamService {
+	return &streamServiceImpl{
+		db:         db,
+		outboxRepo: outboxRepo,
+		audience:   audience,
+		hub:        newStreamHub(),
+		config:     cfg,
+	}
+}
+
+func (s *streamServiceImpl) Publish(ctx context.Context, event *outboxEntities.OutboxEvent) error {
+	payload, err := json.Marshal(notice{ID: event.ID, TenantID: event.TenantID})
+	if err != nil {
+		return err
+	}
+	return s.db.Notify(ctx, streamChannel, string(payload))
+}
+
+func (s *streamServiceImpl) Run(ctx context.Context) {
+	defer s.hub.closeAll(true)
+
+	for {
+		err := s.db.Listen(ctx, streamChannel, func(payload string) {
+			s.deliver(ctx, payload)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Event stream listener failed: %v", err)
+
+		// Events published while nobody listened are lost to the open
+		// streams; dropping them makes clients resume from Last-Event-ID.
+		s.hub.closeAll(false)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (s *streamServiceImpl) deliver(ctx context.Context, payload string) {
+	var n notice
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		log.Printf("Event stream received a malformed notice: %v", err)
+		return
+	}
+	if !s.hub.hasStreams(n.TenantID) {
+		return
+	}
+
+	ctx = utils.WithTenant(ctx, n.TenantID)
+	event, err := s.outboxRepo.FindEvent(ctx, n.ID)
+	if err != nil || event == nil {
+		if err != nil {
+			log.Printf("Event stream failed to load event %s: %v", n.ID, err)
+		}
+		return
+	}
+
+	audience, err := s.audience(ctx, event)
+	if err != nil {
+		log.Printf("Event stream failed to resolve the audience of event %s: %v", n.ID, err)
+		return
+	}
+	s.hub.broadcast(n.TenantID, audience, toMessage(event))
+}
+
+func (s *streamServiceImpl) Open(ctx context.Context, lastEventID string) (*Stream, error) {
+	userID, ok := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return nil, utils.NewAppError(utils.ErrUnauthorized, fmt.Errorf("user id missing from context"))
+	}
+	tenantID, _ := ctx.Value(utils.TenantIDContextKey).(uuid.UUID)
+
+	// Subscribe before replaying so nothing published in between is lost.
+	stream := newStream(tenantID, userID, s.config.Stream.BufferSize, s.config.Stream.HeartbeatInterval)
+	s.hub.subscribe(stream)
+
+	if lastEventID != "" {
+		if err := s.replay(ctx, stream, lastEventID); err != nil {
+			s.hub.unsubscribe(stream)
+			return nil, err
+		}
+	}
+
+	return stream, nil
+}
+
+func (s *streamServiceImpl) Close(stream *Stream) {
+	s.hub.unsubscribe(stream)
+}
+
+func (s *streamServiceImpl) replay(ctx context.Context, stream *Stream, lastEventID string) error {
+	id, err := uuid.Parse(lastEventID)
+	if err != nil {
+		stream.Reset = true
+		return nil
+	}
+
+	last, err := s.outboxRepo.FindEvent(ctx, id)
+	if err != nil {
+		return utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if last == nil || !slices.Contains(documentEvents.Types, last.Type) {
+		// Purged or never ours; the client has to reload.
+		stream.Reset = true
+		return nil
+	}
+
+	limit := s.config.Stream.ReplayLimit
+	events, err := s.outboxRepo.ListAfter(ctx, last, documentEvents.Types, limit+1)
+	if err != nil {
+		return utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if len(events) > limit {
+		stream.Reset = true
+		return nil
+	}
+
+	for i := range events {
+		audience, err := s.audience(ctx, &events[i])
+		if err != nil {
+			return utils.NewAppError(utils.ErrFetchDataError, err)
+		}
+		if !slices.Contains(audience, stream.UserID) {
+			continue
+		}
+		message := toMessage(&events[i])
+		stream.Replay = append(stream.Replay, message)
+		stream.replayed[message.ID] = struct{}{}
+	}
+
+	return nil
+}
+
+func toMessage(event *outboxEntities.OutboxEvent) Message {
+	return Message{
+		ID:    event.ID.String(),
+		Event: event.Type,
+		Data:  event.Payload,
+	}
+}
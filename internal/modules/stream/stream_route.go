@@ -0,0 +1,21 @@
+package stream
+
+import (
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/stream/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamPath is the event stream route, which the server exempts from its
+// request timeout.
+const StreamPath = "/api/v1/events/stream"
+
+func RegisterStreamRoutes(rg *gin.RouterGroup, h *handlers.StreamHandler, authMware *middlewares.AuthMiddleware) {
+
+	eventRoutes := rg.Group("/events")
+	eventRoutes.Use(authMware.Auth())
+	{
+		eventRoutes.GET("/stream", h.Stream)
+	}
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "testcase/internal/modules/user/entities"
+import (
+	"testcase/internal/modules/user/entities"
+	"time"
+)
 
 type CreateUserInput struct {
 	Name     string            `json:"name" binding:"required"`
@@ -9,6 +12,18 @@ type CreateUserInput struct {
 	Password string            `json:"password" binding:"required,min=6"`
 	Phone    string            `json:"phone,omitempty"`
 	Role     entities.RoleEnum `json:"role" binding:"required"`
+	Locale   string            `json:"locale,omitempty" binding:"omitempty,bcp47_language_tag"`
+}
+
+// RegisterUserInput is a self-registration. It has no role; new users
+// always start with the default one.
+type RegisterUserInput struct {
+	Name     string `json:"name" binding:"required"`
+	Username string `json:"username" binding:"required,alphanum,min=3,max=100"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+	Phone    string `json:"phone,omitempty"`
+	Locale   string `json:"locale,omitempty" binding:"omitempty,bcp47_language_tag"`
 }
 
 type UpdateUserInput struct {
@@ -19,6 +34,12 @@ type UpdateUserInput struct {
 	Phone    *string            `json:"phone,omitempty"`
 	Role     *entities.RoleEnum `json:"role,omitempty"`
 	IsActive *bool              `json:"is_active,omitempty"`
+	Locale   *string            `json:"locale,omitempty" binding:"omitempty,bcp47_language_tag"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,nefield=CurrentPassword"`
 }
 
 type LoginUserInput struct {
@@ -29,3 +50,44 @@ type LoginUserInput struct {
 type RefreshTokenInput struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
+
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type MFACodeInput struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type MFALoginInput struct {
+	Code         string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+type StepUpInput struct {
+	Scope      string `json:"scope" binding:"required,oneof=document.action"`
+	ResourceID string `json:"resource_id" binding:"required,uuid"`
+	Password   string `json:"password" binding:"required_without=Code"`
+	Code       string `json:"code" binding:"required_without=Password"`
+}
+
+type CreateAPIKeyInput struct {
+	Name      string     `json:"name" binding:"required,max=255"`
+	Kind      string     `json:"kind,omitempty" binding:"omitempty,oneof=api_key hmac"`
+	UserID    string     `json:"user_id,omitempty" binding:"omitempty,uuid"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// SSOCallbackInput carries what the provider redirected back with.
+// BrowserState is the state from the cookie set by authorize, so a login
+// can only be completed in the browser that started it.
+type SSOCallbackInput struct {
+	Code         string `json:"code" binding:"required"`
+	State        string `json:"state" binding:"required"`
+	BrowserState string `json:"-"`
+}
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type APIKeyKind string
+
+const (
+	// APIKeyKindKey is sent as-is in X-API-Key and stored only as a hash.
+	APIKeyKindKey APIKeyKind = "api_key"
+	// APIKeyKindHMAC signs requests with a shared secret that never travels
+	// with the request, so the secret is stored encrypted instead of hashed.
+	APIKeyKindHMAC APIKeyKind = "hmac"
+)
+
+// APIKey lets an integration act as UserID without a login. Prefix
+// identifies the key in listings and logs and is the key id for HMAC keys.
+type APIKey struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID      uuid.UUID  `gorm:"type:uuid;index" json:"tenant_id"`
+	Name          string     `gorm:"type:varchar(255);not null" json:"name"`
+	Kind          APIKeyKind `gorm:"type:varchar(20);not null;default:'api_key'" json:"kind"`
+	Prefix        string     `gorm:"type:varchar(16);uniqueIndex;not null" json:"prefix"`
+	KeyHash       string     `gorm:"type:varchar(64)" json:"-"`
+	SigningSecret string     `gorm:"type:text" json:"-"`
+	UserID        uuid.UUID  `gorm:"type:uuid;index;not null" json:"user_id"`
+	Scopes        []string   `gorm:"type:jsonb;serializer:json;not null" json:"scopes"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy     uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (k *APIKey) TableName() string {
+	return "api_keys"
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+func (k *APIKey) IsUsable(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExternalIdentity links a user to an account at an OpenID Connect
+// provider. Issuer and Subject together identify the provider account.
+type ExternalIdentity struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID    uuid.UUID  `gorm:"type:uuid;index" json:"tenant_id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;index;not null" json:"user_id"`
+	Issuer      string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_external_identities_issuer_subject" json:"issuer"`
+	Subject     string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_external_identities_issuer_subject" json:"subject"`
+	Email       string     `gorm:"type:varchar(255)" json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (e *ExternalIdentity) TableName() string {
+	return "external_identities"
+}
+
+func (e *ExternalIdentity) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// OIDCLoginState carries state, nonce and PKCE verifier from the start of
+// an SSO login to its callback. Rows are single-use.
+type OIDCLoginState struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	StateHash    string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Nonce        string    `gorm:"type:varchar(128);not null" json:"-"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (s *OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+func (s *OIDCLoginState) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LockoutKind string
+
+const (
+	LockoutKindAccount LockoutKind = "account"
+	LockoutKindIP      LockoutKind = "ip"
+)
+
+type LoginLockout struct {
+	ID             uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	Kind           LockoutKind `gorm:"type:varchar(20);not null;uniqueIndex:idx_login_lockouts_kind_identifier" json:"kind"`
+	Identifier     string      `gorm:"type:varchar(255);not null;uniqueIndex:idx_login_lockouts_kind_identifier" json:"identifier"`
+	FailedAttempts int         `gorm:"not null;default:0" json:"failed_attempts"`
+	LastFailedAt   time.Time   `json:"last_failed_at"`
+	LockedUntil    *time.Time  `gorm:"index" json:"locked_until,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+func (l *LoginLockout) TableName() string {
+	return "login_lockouts"
+}
+
+func (l *LoginLockout) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+func (l *LoginLockout) IsLocked(now time.Time) bool {
+	return l.LockedUntil != nil && l.LockedUntil.After(now)
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// RequestNonce records a signed request nonce so replays are rejected on
+// every instance. Hash is the SHA-256 of the key ID and nonce; rows are
+// useless once ExpiresAt has passed.
+type RequestNonce struct {
+	Hash      string    `gorm:"type:varchar(64);primary_key" json:"hash"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *RequestNonce) TableName() string {
+	return "request_nonces"
+}
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// RevokedToken is a persisted access token revocation entry. Key is a session
+// or user reference; rows are useless once ExpiresAt has passed.
+type RevokedToken struct {
+	Key       string    `gorm:"type:varchar(255);primary_key" json:"key"`
+	RevokedAt time.Time `gorm:"not null" json:"revoked_at"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r *RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session stores one issued refresh token. Rotating a refresh token creates
+// a new row in the same family; FamilyID identifies the login session.
+type Session struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID   uuid.UUID  `gorm:"type:uuid;index" json:"tenant_id"`
+	FamilyID   uuid.UUID  `gorm:"type:uuid;index;not null" json:"family_id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;index;not null" json:"user_id"`
+	ParentID   *uuid.UUID `gorm:"type:uuid" json:"parent_id,omitempty"`
+	IPAddress  string     `gorm:"type:varchar(64)" json:"ip_address"`
+	UserAgent  string     `gorm:"type:text" json:"user_agent"`
+	StartedAt  time.Time  `gorm:"not null" json:"started_at"`
+	LastSeenAt time.Time  `gorm:"not null" json:"last_seen_at"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (s *Session) TableName() string {
+	return "sessions"
+}
+
+func (s *Session) IsUsable(now time.Time) bool {
+	return s.RotatedAt == nil && s.RevokedAt == nil && s.ExpiresAt.After(now)
+}
@@ -10,6 +10,7 @@ import (
 type RoleEnum string
 
 const (
+	RoleAdmin  RoleEnum = "admin"
 	RoleAdmin1 RoleEnum = "admin1"
 	RoleAdmin2 RoleEnum = "admin2"
 	RoleAdmin3 RoleEnum = "admin3"
@@ -17,18 +18,28 @@ const (
 )
 
 type User struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Name      string         `gorm:"type:varchar(255);not null" json:"name" validate:"required,min=2,max=255"`
-	Username  string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"username" validate:"required,alphanum,min=3,max=100"`
-	Email     string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email" validate:"required,email"`
-	Password  string         `gorm:"type:varchar(255);not null" json:"-" validate:"required,min=8"`
-	Phone     string         `gorm:"type:varchar(20)" json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
-	Role      RoleEnum       `gorm:"type:varchar(50);not null;default:'user'" json:"role" validate:"required,oneof=admin user manager"`
-	IsActive  bool           `gorm:"default:true" json:"is_active"`
-	LastLogin *time.Time     `json:"last_login,omitempty"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID        uuid.UUID      `gorm:"type:uuid;index" json:"tenant_id"`
+	Name            string         `gorm:"type:varchar(255);not null" json:"name" validate:"required,min=2,max=255"`
+	Username        string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"username" validate:"required,alphanum,min=3,max=100"`
+	Email           string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email" validate:"required,email"`
+	Password        string         `gorm:"type:varchar(255);not null" json:"-" validate:"required,min=8"`
+	Phone           string         `gorm:"type:varchar(20)" json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
+	Role            RoleEnum       `gorm:"type:varchar(50);not null;default:'user'" json:"role" validate:"required,oneof=admin user manager"`
+	Locale          string         `gorm:"type:varchar(35)" json:"locale,omitempty"`
+	IsActive        bool           `gorm:"default:true" json:"is_active"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty"`
+	MFAEnabled      bool           `gorm:"default:false" json:"mfa_enabled"`
+	MFASecret       string         `gorm:"type:varchar(255)" json:"-"`
+	MFALastUsedStep int64          `gorm:"default:0" json:"-"`
+	LastLogin       *time.Time     `json:"last_login,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 func (u *User) TableName() string {
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+	"testcase/config"
+	"testcase/internal/helpers"
 	"testcase/internal/middlewares"
 	"testcase/internal/modules/user/dto"
 	"testcase/internal/modules/user/services"
@@ -10,13 +12,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// ssoStateCookie binds a started SSO login to the browser that started it.
+const (
+	ssoStateCookie     = "sso_state"
+	ssoStateCookiePath = "/api/v1/users/sso"
+)
+
 type UserHandler struct {
-	userService services.UserService
+	userService   services.UserService
+	secureCookies bool
 }
 
-func NewUserHandler(userService services.UserService) *UserHandler {
+func NewUserHandler(userService services.UserService, cfg *config.Config) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:   userService,
+		secureCookies: cfg.HttpServer.Env == "production",
 	}
 }
 
@@ -35,6 +45,50 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	utils.SuccessResponse(c, user, "User created successfully", http.StatusCreated)
 }
 
+func (h *UserHandler) RegisterUser(c *gin.Context) {
+	var input dto.RegisterUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.userService.RegisterUser(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, user, "User created successfully", http.StatusCreated)
+}
+
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	var input dto.UpdateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.userService.UpdateUser(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, user, "User updated successfully", http.StatusOK)
+}
+
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var input dto.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), &input); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Password changed successfully, please log in again", http.StatusOK)
+}
+
 func (h *UserHandler) LoginUser(c *gin.Context) {
 	var input dto.LoginUserInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -47,6 +101,41 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 		panic(err)
 	}
 
+	if loginResponse.MFA != nil {
+		utils.SuccessResponse(c, loginResponse, "Two-factor authentication required", http.StatusOK)
+		return
+	}
+
+	utils.SuccessResponse(c, loginResponse, "Login successful", http.StatusOK)
+}
+
+func (h *UserHandler) StartSSOLogin(c *gin.Context) {
+	authorization, err := h.userService.StartSSOLogin(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoStateCookie, authorization.State, int(authorization.ExpiresIn), ssoStateCookiePath, "", h.secureCookies, true)
+
+	utils.SuccessResponse(c, authorization, "Redirect to the identity provider to continue", http.StatusOK)
+}
+
+func (h *UserHandler) CompleteSSOLogin(c *gin.Context) {
+	var input dto.SSOCallbackInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	input.BrowserState, _ = c.Cookie(ssoStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoStateCookie, "", -1, ssoStateCookiePath, "", h.secureCookies, true)
+
+	loginResponse, err := h.userService.CompleteSSOLogin(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
 	utils.SuccessResponse(c, loginResponse, "Login successful", http.StatusOK)
 }
 
@@ -58,3 +147,242 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 
 	utils.SuccessResponse(c, refreshResponse, "Token refreshed successfully", http.StatusOK)
 }
+
+func (h *UserHandler) Logout(c *gin.Context) {
+	if err := h.userService.Logout(c.Request.Context()); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Logged out successfully", http.StatusOK)
+}
+
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	if err := h.userService.LogoutAll(c.Request.Context()); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Logged out from all sessions", http.StatusOK)
+}
+
+func (h *UserHandler) ListMySessions(c *gin.Context) {
+	sessions, err := h.userService.ListMySessions(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, sessions, "Sessions retrieved successfully", http.StatusOK)
+}
+
+func (h *UserHandler) RevokeMySession(c *gin.Context) {
+	if err := h.userService.RevokeMySession(c.Request.Context(), c.Param("id")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Session revoked successfully", http.StatusOK)
+}
+
+func (h *UserHandler) ListUserSessions(c *gin.Context) {
+	sessions, err := h.userService.ListUserSessions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, sessions, "Sessions retrieved successfully", http.StatusOK)
+}
+
+func (h *UserHandler) RevokeUserSession(c *gin.Context) {
+	if err := h.userService.RevokeUserSession(c.Request.Context(), c.Param("id"), c.Param("sessionId")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Session revoked successfully", http.StatusOK)
+}
+
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var input dto.VerifyEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := h.userService.VerifyEmail(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, user, "Email verified successfully", http.StatusOK)
+}
+
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	var input dto.ResendVerificationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.userService.ResendVerification(c.Request.Context(), &input); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "If the email is registered and not yet verified, a verification link has been sent", http.StatusOK)
+}
+
+func (h *UserHandler) EnrollMFA(c *gin.Context) {
+	enrollment, err := h.userService.EnrollMFA(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, enrollment, "Scan the provisioning URI with your authenticator app", http.StatusOK)
+}
+
+func (h *UserHandler) ActivateMFA(c *gin.Context) {
+	var input dto.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	recovery, err := h.userService.ActivateMFA(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, recovery, "Two-factor authentication enabled", http.StatusOK)
+}
+
+func (h *UserHandler) DisableMFA(c *gin.Context) {
+	var input dto.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := h.userService.DisableMFA(c.Request.Context(), &input); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Two-factor authentication disabled", http.StatusOK)
+}
+
+func (h *UserHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var input dto.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	recovery, err := h.userService.RegenerateRecoveryCodes(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, recovery, "Recovery codes regenerated", http.StatusOK)
+}
+
+func (h *UserHandler) VerifyMFALogin(c *gin.Context) {
+	var input dto.MFALoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	loginResponse, err := h.userService.VerifyMFALogin(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, loginResponse, "Login successful", http.StatusOK)
+}
+
+func (h *UserHandler) CompleteMFAEnrollment(c *gin.Context) {
+	var input dto.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	loginResponse, err := h.userService.CompleteMFAEnrollment(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, loginResponse, "Two-factor authentication enabled, login successful", http.StatusOK)
+}
+
+func (h *UserHandler) StepUp(c *gin.Context) {
+	var input dto.StepUpInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	stepUp, err := h.userService.StepUp(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, stepUp, "Step-up token issued", http.StatusOK)
+}
+
+func (h *UserHandler) ListLockouts(c *gin.Context) {
+	params := helpers.ParsePaginationParams(c)
+
+	lockouts, total, err := h.userService.ListLockouts(c.Request.Context(), params)
+	if err != nil {
+		panic(err)
+	}
+
+	list := helpers.CreatePaginationResult(lockouts, total, params)
+
+	utils.SuccessResponse(c, list, "Lockouts retrieved successfully", http.StatusOK)
+}
+
+func (h *UserHandler) ClearLockout(c *gin.Context) {
+	if err := h.userService.ClearLockout(c.Request.Context(), c.Param("id")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Lockout cleared successfully", http.StatusOK)
+}
+
+func (h *UserHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.userService.JWKS())
+}
+
+func (h *UserHandler) CreateAPIKey(c *gin.Context) {
+	var input dto.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	apiKey, err := h.userService.CreateAPIKey(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, apiKey, "API key created, store the key now as it will not be shown again", http.StatusCreated)
+}
+
+func (h *UserHandler) ListAPIKeys(c *gin.Context) {
+	params := helpers.ParsePaginationParams(c)
+
+	keys, total, err := h.userService.ListAPIKeys(c.Request.Context(), params)
+	if err != nil {
+		panic(err)
+	}
+
+	list := helpers.CreatePaginationResult(keys, total, params)
+
+	utils.SuccessResponse(c, list, "API keys retrieved successfully", http.StatusOK)
+}
+
+func (h *UserHandler) RevokeAPIKey(c *gin.Context) {
+	if err := h.userService.RevokeAPIKey(c.Request.Context(), c.Param("id")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "API key revoked successfully", http.StatusOK)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/user/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *entities.APIKey) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error)
+	ListAPIKeys(ctx context.Context, params *helpers.PaginationParams, activeOnly bool) ([]entities.APIKey, int64, error)
+	Revoke(ctx context.Context, key *entities.APIKey) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testcase/internal/helpers"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/user/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewAPIKeyRepository(db *database.Database) APIKeyRepository {
+	return &apiKeyRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepositoryImpl) CreateAPIKey(ctx context.Context, key *entities.APIKey) error {
+	err := r.db.WithContext(ctx).Create(key).Error
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error) {
+	var key entities.APIKey
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("api key with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to find api key by ID: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepositoryImpl) FindByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	var key entities.APIKey
+
+	err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("api key with prefix %s not found", prefix)
+		}
+		return nil, fmt.Errorf("failed to find api key by prefix: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepositoryImpl) ListAPIKeys(ctx context.Context, params *helpers.PaginationParams, activeOnly bool) ([]entities.APIKey, int64, error) {
+	var keys []entities.APIKey
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.APIKey{})
+
+	if activeOnly {
+		query = query.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", time.Now())
+	}
+
+	if params.Search != "" {
+		search := fmt.Sprintf("%%%s%%", params.Search)
+		query = query.Where("name ILIKE ? OR prefix ILIKE ?", search, search)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count api keys: %w", err)
+	}
+
+	err := query.
+		Offset(params.GetOffset()).
+		Limit(params.Limit).
+		Order("created_at desc").
+		Find(&keys).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	return keys, total, nil
+}
+
+func (r *apiKeyRepositoryImpl) Revoke(ctx context.Context, key *entities.APIKey) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).
+		Model(key).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", now).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+	if err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/user/entities"
+
+	"github.com/google/uuid"
+)
+
+type EmailVerificationRepository interface {
+	CreateToken(ctx context.Context, token *entities.EmailVerificationToken) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*entities.EmailVerificationToken, error)
+	MarkUsed(ctx context.Context, token *entities.EmailVerificationToken) error
+	DeleteUnusedByUserID(ctx context.Context, userID uuid.UUID) error
+}
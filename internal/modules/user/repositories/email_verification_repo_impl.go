@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/user/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type emailVerificationRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewEmailVerificationRepository(db *database.Database) EmailVerificationRepository {
+	return &emailVerificationRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *emailVerificationRepositoryImpl) CreateToken(ctx context.Context, token *entities.EmailVerificationToken) error {
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return fmt.Errorf("failed to create email verification token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *emailVerificationRepositoryImpl) FindByTokenHash(ctx context.Context, tokenHash string) (*entities.EmailVerificationToken, error) {
+	var token entities.EmailVerificationToken
+
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("email verification token not found")
+		}
+		return nil, fmt.Errorf("failed to find email verification token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *emailVerificationRepositoryImpl) MarkUsed(ctx context.Context, token *entities.EmailVerificationToken) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).Model(token).Update("used_at", now).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark email verification token as used: %w", err)
+	}
+	token.UsedAt = &now
+
+	return nil
+}
+
+func (r *emailVerificationRepositoryImpl) DeleteUnusedByUserID(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Delete(&entities.EmailVerificationToken{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete email verification tokens: %w", err)
+	}
+
+	return nil
+}
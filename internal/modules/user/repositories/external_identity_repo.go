@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/user/entities"
+)
+
+type ExternalIdentityRepository interface {
+	FindByIssuerSubject(ctx context.Context, issuer, subject string) (*entities.ExternalIdentity, error)
+	SaveIdentity(ctx context.Context, identity *entities.ExternalIdentity) error
+	CreateLoginState(ctx context.Context, state *entities.OIDCLoginState) error
+	ConsumeLoginState(ctx context.Context, stateHash string) (*entities.OIDCLoginState, error)
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/user/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type externalIdentityRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewExternalIdentityRepository(db *database.Database) ExternalIdentityRepository {
+	return &externalIdentityRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *externalIdentityRepositoryImpl) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*entities.ExternalIdentity, error) {
+	var identity entities.ExternalIdentity
+
+	err := r.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find external identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+func (r *externalIdentityRepositoryImpl) SaveIdentity(ctx context.Context, identity *entities.ExternalIdentity) error {
+	err := r.db.WithContext(ctx).Save(identity).Error
+	if err != nil {
+		return fmt.Errorf("failed to save external identity: %w", err)
+	}
+
+	return nil
+}
+
+func (r *externalIdentityRepositoryImpl) CreateLoginState(ctx context.Context, state *entities.OIDCLoginState) error {
+	if err := r.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&entities.OIDCLoginState{}).Error; err != nil {
+		return fmt.Errorf("failed to prune login states: %w", err)
+	}
+
+	err := r.db.WithContext(ctx).Create(state).Error
+	if err != nil {
+		return fmt.Errorf("failed to create login state: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeLoginState deletes and returns the state in one statement, so a
+// callback can only be completed once.
+func (r *externalIdentityRepositoryImpl) ConsumeLoginState(ctx context.Context, stateHash string) (*entities.OIDCLoginState, error) {
+	var states []entities.OIDCLoginState
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume login state: %w", err)
+	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("login state not found")
+	}
+
+	return &states[0], nil
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/user/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginLockoutRepository interface {
+	Find(ctx context.Context, kind entities.LockoutKind, identifier string) (*entities.LoginLockout, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.LoginLockout, error)
+	// RecordFailure counts a failed attempt in a single statement, so
+	// concurrent failures can't overwrite each other's increment. The count
+	// starts over when the last failure is older than window and no lockout
+	// is active. It returns the row as updated.
+	RecordFailure(ctx context.Context, kind entities.LockoutKind, identifier string, now time.Time, window time.Duration) (*entities.LoginLockout, error)
+	// Lock extends the lockout to lockedUntil, never shortening it.
+	Lock(ctx context.Context, id uuid.UUID, lockedUntil time.Time) error
+	Delete(ctx context.Context, lockout *entities.LoginLockout) error
+	DeleteByIdentifier(ctx context.Context, kind entities.LockoutKind, identifier string) error
+	ListLockouts(ctx context.Context, params *helpers.PaginationParams, activeOnly bool) ([]entities.LoginLockout, int64, error)
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testcase/internal/helpers"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/user/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type loginLockoutRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewLoginLockoutRepository(db *database.Database) LoginLockoutRepository {
+	return &loginLockoutRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *loginLockoutRepositoryImpl) Find(ctx context.Context, kind entities.LockoutKind, identifier string) (*entities.LoginLockout, error) {
+	var lockout entities.LoginLockout
+
+	err := r.db.WithContext(ctx).Where("kind = ? AND identifier = ?", kind, identifier).First(&lockout).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find login lockout: %w", err)
+	}
+
+	return &lockout, nil
+}
+
+func (r *loginLockoutRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*entities.LoginLockout, error) {
+	var lockout entities.LoginLockout
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&lockout).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("login lockout with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to find login lockout by ID: %w", err)
+	}
+
+	return &lockout, nil
+}
+
+func (r *loginLockoutRepositoryImpl) RecordFailure(ctx context.Context, kind entities.LockoutKind, identifier string, now time.Time, window time.Duration) (*entities.LoginLockout, error) {
+	var lockout entities.LoginLockout
+
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO login_lockouts (id, kind, identifier, failed_attempts, last_failed_at, created_at, updated_at)
+		VALUES (?, ?, ?, 1, ?, ?, ?)
+		ON CONFLICT (kind, identifier) DO UPDATE SET
+			failed_attempts = CASE
+				WHEN login_lockouts.last_failed_at < ? AND (login_lockouts.locked_until IS NULL OR login_lockouts.locked_until <= ?)
+				THEN 1
+				ELSE login_lockouts.failed_attempts + 1
+			END,
+			last_failed_at = EXCLUDED.last_failed_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *`,
+		uuid.New(), kind, identifier, now, now, now,
+		now.Add(-window), now,
+	).Scan(&lockout).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	return &lockout, nil
+}
+
+func (r *loginLockoutRepositoryImpl) Lock(ctx context.Context, id uuid.UUID, lockedUntil time.Time) error {
+	err := r.db.WithContext(ctx).Model(&entities.LoginLockout{}).
+		Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, lockedUntil).
+		Update("locked_until", lockedUntil).Error
+	if err != nil {
+		return fmt.Errorf("failed to lock login: %w", err)
+	}
+
+	return nil
+}
+
+func (r *loginLockoutRepositoryImpl) Delete(ctx context.Context, lockout *entities.LoginLockout) error {
+	err := r.db.WithContext(ctx).Delete(lockout).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete login lockout: %w", err)
+	}
+
+	return nil
+}
+
+func (r *loginLockoutRepositoryImpl) DeleteByIdentifier(ctx context.Context, kind entities.LockoutKind, identifier string) error {
+	err := r.db.WithContext(ctx).
+		Where("kind = ? AND identifier = ?", kind, identifier).
+		Delete(&entities.LoginLockout{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete login lockout: %w", err)
+	}
+
+	return nil
+}
+
+func (r *loginLockoutRepositoryImpl) ListLockouts(ctx context.Context, params *helpers.PaginationParams, activeOnly bool) ([]entities.LoginLockout, int64, error) {
+	var lockouts []entities.LoginLockout
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.LoginLockout{})
+
+	if activeOnly {
+		query = query.Where("locked_until > ?", time.Now())
+	}
+
+	if params.Search != "" {
+		query = query.Where("identifier ILIKE ?", fmt.Sprintf("%%%s%%", params.Search))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count login lockouts: %w", err)
+	}
+
+	err := query.
+		Offset(params.GetOffset()).
+		Limit(params.Limit).
+		Order("last_failed_at desc").
+		Find(&lockouts).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list login lockouts: %w", err)
+	}
+
+	return lockouts, total, nil
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/user/entities"
+
+	"github.com/google/uuid"
+)
+
+type MFARepository interface {
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []entities.MFARecoveryCode) error
+	FindUnusedRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (*entities.MFARecoveryCode, error)
+	MarkRecoveryCodeUsed(ctx context.Context, code *entities.MFARecoveryCode) error
+	DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+}
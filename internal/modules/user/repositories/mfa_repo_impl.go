@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/user/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type mfaRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewMFARepository(db *database.Database) MFARepository {
+	return &mfaRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *mfaRepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []entities.MFARecoveryCode) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entities.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *mfaRepositoryImpl) FindUnusedRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (*entities.MFARecoveryCode, error) {
+	var code entities.MFARecoveryCode
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("recovery code not found")
+		}
+		return nil, fmt.Errorf("failed to find recovery code: %w", err)
+	}
+
+	return &code, nil
+}
+
+func (r *mfaRepositoryImpl) MarkRecoveryCodeUsed(ctx context.Context, code *entities.MFARecoveryCode) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&entities.MFARecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", code.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark recovery code as used: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("recovery code already used")
+	}
+	code.UsedAt = &now
+
+	return nil
+}
+
+func (r *mfaRepositoryImpl) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.MFARecoveryCode{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	return nil
+}
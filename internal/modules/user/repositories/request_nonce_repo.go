@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// RequestNonceRepository is the database-backed securities.NonceCache.
+type RequestNonceRepository interface {
+	Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
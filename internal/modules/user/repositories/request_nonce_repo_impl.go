@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/user/entities"
+	"testcase/package/securities"
+
+	"gorm.io/gorm/clause"
+)
+
+type requestNonceRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewRequestNonceRepository(db *database.Database) RequestNonceRepository {
+	return &requestNonceRepositoryImpl{
+		db: db,
+	}
+}
+
+// Remember inserts the nonce and prunes rows that have expired. An expired
+// row with the same nonce is taken over, so the insert or update affects
+// exactly one row unless the nonce is still live.
+func (r *requestNonceRepositoryImpl) Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&entities.RequestNonce{}).Error; err != nil {
+		return false, fmt.Errorf("failed to prune request nonces: %w", err)
+	}
+
+	entry := &entities.RequestNonce{
+		Hash:      securities.HashToken(nonce),
+		ExpiresAt: now.Add(ttl),
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Lt{Column: clause.Column{Table: "request_nonces", Name: "expires_at"}, Value: now}}},
+		DoUpdates: clause.AssignmentColumns([]string{"expires_at", "created_at"}),
+	}).Create(entry)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remember request nonce: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
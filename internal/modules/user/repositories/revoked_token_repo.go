@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// RevokedTokenRepository is the database-backed securities.RevocationStore.
+type RevokedTokenRepository interface {
+	Revoke(ctx context.Context, key string, revokedAt, expiresAt time.Time) error
+	RevokedAt(ctx context.Context, key string) (*time.Time, error)
+}
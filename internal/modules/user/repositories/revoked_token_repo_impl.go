@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/user/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type revokedTokenRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewRevokedTokenRepository(db *database.Database) RevokedTokenRepository {
+	return &revokedTokenRepositoryImpl{
+		db: db,
+	}
+}
+
+// Revoke upserts the entry and prunes rows that have expired, which keeps
+// the table bounded without a separate cleanup job.
+func (r *revokedTokenRepositoryImpl) Revoke(ctx context.Context, key string, revokedAt, expiresAt time.Time) error {
+	if err := r.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&entities.RevokedToken{}).Error; err != nil {
+		return fmt.Errorf("failed to prune revoked tokens: %w", err)
+	}
+
+	entry := &entities.RevokedToken{
+		Key:       key,
+		RevokedAt: revokedAt,
+		ExpiresAt: expiresAt,
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at", "expires_at", "updated_at"}),
+	}).Create(entry).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *revokedTokenRepositoryImpl) RevokedAt(ctx context.Context, key string) (*time.Time, error) {
+	var entry entities.RevokedToken
+
+	err := r.db.WithContext(ctx).Where("key = ? AND expires_at > ?", key, time.Now()).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find revoked token: %w", err)
+	}
+
+	return &entry.RevokedAt, nil
+}
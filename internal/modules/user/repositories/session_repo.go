@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/user/entities"
+
+	"github.com/google/uuid"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *entities.Session) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.Session, error)
+	MarkRotated(ctx context.Context, id uuid.UUID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]entities.Session, error)
+	FindActiveByFamily(ctx context.Context, familyID uuid.UUID) (*entities.Session, error)
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/user/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type sessionRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewSessionRepository(db *database.Database) SessionRepository {
+	return &sessionRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *sessionRepositoryImpl) CreateSession(ctx context.Context, session *entities.Session) error {
+	err := r.db.WithContext(ctx).Create(session).Error
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*entities.Session, error) {
+	var session entities.Session
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("session with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to find session by ID: %w", err)
+	}
+
+	return &session, nil
+}
+
+// MarkRotated flags the token as used only if it is still active, so two
+// concurrent refreshes with the same token can't both succeed.
+func (r *sessionRepositoryImpl) MarkRotated(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.Session{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		Update("rotated_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to rotate session: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *sessionRepositoryImpl) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke session family: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepositoryImpl) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Model(&entities.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepositoryImpl) ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]entities.Session, error) {
+	var sessions []entities.Session
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at desc").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepositoryImpl) FindActiveByFamily(ctx context.Context, familyID uuid.UUID) (*entities.Session, error) {
+	var session entities.Session
+
+	err := r.db.WithContext(ctx).
+		Where("family_id = ? AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > ?", familyID, time.Now()).
+		First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("session %s not found", familyID)
+		}
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+
+	return &session, nil
+}
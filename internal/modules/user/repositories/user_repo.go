@@ -9,9 +9,18 @@ import (
 )
 
 type UserRepository interface {
-	FindByEmail(email string) (*entities.User, error)
-	FindByUsername(username string) (*entities.User, error)
-	FindByID(id uuid.UUID) (*entities.User, error)
+	FindByEmail(ctx context.Context, email string) (*entities.User, error)
+	FindByUsername(ctx context.Context, username string) (*entities.User, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.User, error)
+	// FindActiveByIDs, FindActiveByRoles and FindActiveByUsernames skip
+	// deactivated users.
+	FindActiveByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.User, error)
+	FindActiveByRoles(ctx context.Context, roles []string) ([]entities.User, error)
+	FindActiveByUsernames(ctx context.Context, usernames []string) ([]entities.User, error)
+	// FindWithMFASecret returns the users that have started or finished
+	// MFA enrollment.
+	FindWithMFASecret(ctx context.Context) ([]entities.User, error)
 	CreateUser(ctx context.Context, user *entities.User) error
 	UpdateUser(ctx context.Context, user *entities.User) error
 	ListUsers(ctx context.Context, params *helpers.PaginationParams) ([]entities.User, int64, error)
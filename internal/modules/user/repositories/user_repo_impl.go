@@ -23,10 +23,10 @@ func NewUserRepository(db *database.Database) UserRepository {
 	}
 }
 
-func (r *userRepositoryImpl) FindByEmail(email string) (*entities.User, error) {
+func (r *userRepositoryImpl) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
 	var user entities.User
 
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user with email %s not found", email)
@@ -37,10 +37,10 @@ func (r *userRepositoryImpl) FindByEmail(email string) (*entities.User, error) {
 	return &user, nil
 }
 
-func (r *userRepositoryImpl) FindByID(id uuid.UUID) (*entities.User, error) {
+func (r *userRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
 	var user entities.User
 
-	err := r.db.Where("id = ?", id).First(&user).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user with ID %s not found", id)
@@ -51,6 +51,73 @@ func (r *userRepositoryImpl) FindByID(id uuid.UUID) (*entities.User, error) {
 	return &user, nil
 }
 
+func (r *userRepositoryImpl) FindActiveByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.User, error) {
+	var users []entities.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("id IN ? AND is_active = ?", ids, true).Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by ID: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *userRepositoryImpl) FindActiveByRoles(ctx context.Context, roles []string) ([]entities.User, error) {
+	var users []entities.User
+	if len(roles) == 0 {
+		return users, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("role IN ? AND is_active = ?", roles, true).Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by role: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *userRepositoryImpl) FindActiveByUsernames(ctx context.Context, usernames []string) ([]entities.User, error) {
+	var users []entities.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("username IN ? AND is_active = ?", usernames, true).Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by username: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *userRepositoryImpl) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]entities.User, error) {
+	var users []entities.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users by ID: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *userRepositoryImpl) FindWithMFASecret(ctx context.Context) ([]entities.User, error) {
+	var users []entities.User
+
+	err := r.db.WithContext(ctx).Where("mfa_secret <> ''").Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users with mfa secret: %w", err)
+	}
+
+	return users, nil
+}
+
 func (r *userRepositoryImpl) CreateUser(ctx context.Context, user *entities.User) error {
 	err := r.db.WithContext(ctx).Create(user).Error
 	if err != nil {
@@ -110,10 +177,10 @@ func (r *userRepositoryImpl) DeleteUser(ctx context.Context, user *entities.User
 	return nil
 }
 
-func (r *userRepositoryImpl) FindByUsername(username string) (*entities.User, error) {
+func (r *userRepositoryImpl) FindByUsername(ctx context.Context, username string) (*entities.User, error) {
 	var user entities.User
 
-	err := r.db.Where("username = ?", username).First(&user).Error
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user with username %s not found", username)
@@ -3,9 +3,60 @@ package responses
 import (
 	"testcase/internal/modules/user/entities"
 	"testcase/package/securities"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type LoginResponse struct {
-	User  entities.User        `json:"user"`
-	Token securities.TokenPair `json:"token"`
+	User          entities.User         `json:"user"`
+	Token         *securities.TokenPair `json:"token,omitempty"`
+	MFA           *MFAChallenge         `json:"mfa,omitempty"`
+	RecoveryCodes []string              `json:"recovery_codes,omitempty"`
+}
+
+type MFAChallenge struct {
+	Token              string `json:"token"`
+	ExpiresIn          int64  `json:"expires_in"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+}
+
+type MFAEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type StepUpResponse struct {
+	Token      string `json:"token"`
+	Scope      string `json:"scope"`
+	ResourceID string `json:"resource_id,omitempty"`
+	ExpiresIn  int64  `json:"expires_in"`
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	StartedAt  time.Time `json:"started_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// APIKeyCreatedResponse is the only response that contains the raw key or,
+// for HMAC keys, the signing secret.
+type APIKeyCreatedResponse struct {
+	entities.APIKey
+	Key    string `json:"key,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+type SSOAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresIn        int64  `json:"expires_in"`
 }
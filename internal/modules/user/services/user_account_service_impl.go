@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	auditEntities "testcase/internal/modules/audit/entities"
+	"testcase/internal/modules/user/dto"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+
+	"github.com/google/uuid"
+)
+
+// UpdateUser applies an admin edit. Changing the password or role, or
+// deactivating the account, revokes every token the user currently holds
+// so the change takes effect immediately rather than at token expiry.
+func (u *userServiceImpl) UpdateUser(ctx context.Context, id string, input *dto.UpdateUserInput) (*entities.User, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid user ID: %w", err))
+	}
+
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrUserNotFound, err)
+	}
+
+	revokeAccess := false
+	emailChanged := false
+	previousRole := user.Role
+
+	if input.Name != nil {
+		user.Name = *input.Name
+	}
+	if input.Username != nil && *input.Username != user.Username {
+		existing, _ := u.userRepo.FindByUsername(utils.WithoutTenantScope(ctx), *input.Username)
+		if existing != nil {
+			return nil, utils.NewAppError(utils.ErrUsernameExists, fmt.Errorf("username already exists"))
+		}
+		user.Username = *input.Username
+	}
+	if input.Email != nil && *input.Email != user.Email {
+		existing, _ := u.userRepo.FindByEmail(utils.WithoutTenantScope(ctx), *input.Email)
+		if existing != nil {
+			return nil, utils.NewAppError(utils.ErrEmailExists, fmt.Errorf("email already exists"))
+		}
+		user.Email = *input.Email
+		user.EmailVerifiedAt = nil
+		emailChanged = true
+	}
+	if input.Phone != nil {
+		user.Phone = *input.Phone
+	}
+	if input.Locale != nil {
+		user.Locale = *input.Locale
+	}
+	if input.Password != nil {
+		if err := u.localPasswordsOnly(); err != nil {
+			return nil, err
+		}
+		if err := u.checkPasswordPolicy(ctx, *input.Password); err != nil {
+			return nil, err
+		}
+		hashedPassword, err := securities.HashPassword(*input.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = hashedPassword
+		revokeAccess = true
+	}
+	if input.Role != nil && *input.Role != user.Role {
+		if err := u.ensureRoleExists(ctx, *input.Role); err != nil {
+			return nil, err
+		}
+		user.Role = *input.Role
+		revokeAccess = true
+	}
+	if input.IsActive != nil && *input.IsActive != user.IsActive {
+		user.IsActive = *input.IsActive
+		revokeAccess = revokeAccess || !user.IsActive
+	}
+
+	if err := u.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	if user.Role != previousRole {
+		u.recordAudit(ctx, &auditEntities.AuditLog{
+			Action:     auditEntities.ActionUserRole,
+			TargetType: auditTargetUser,
+			TargetID:   user.ID.String(),
+			Metadata:   map[string]string{"from": string(previousRole), "to": string(user.Role)},
+		})
+	}
+
+	if revokeAccess {
+		if err := u.revokeUserAccess(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	if emailChanged {
+		if err := u.sendVerificationEmail(ctx, user); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+		}
+	}
+
+	return user, nil
+}
+
+// ChangePassword updates the caller's password and signs them out
+// everywhere, including the session that made the request.
+func (u *userServiceImpl) ChangePassword(ctx context.Context, input *dto.ChangePasswordInput) error {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := u.localPasswordsOnly(); err != nil {
+		return err
+	}
+	if err := securities.VerifyPassword(user.Password, input.CurrentPassword); err != nil {
+		return utils.NewAppError(utils.ErrInvalidCredentials, fmt.Errorf("invalid credentials"))
+	}
+	if err := u.checkPasswordPolicy(ctx, input.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := securities.HashPassword(input.NewPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+
+	if err := u.userRepo.UpdateUser(ctx, user); err != nil {
+		return utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	return u.revokeUserAccess(ctx, user.ID)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"testcase/internal/helpers"
+	auditEntities "testcase/internal/modules/audit/entities"
+	"testcase/internal/modules/user/dto"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/modules/user/responses"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyTouchInterval limits last-used writes to one per key per interval.
+const apiKeyTouchInterval = time.Minute
+
+func (u *userServiceImpl) CreateAPIKey(ctx context.Context, input *dto.CreateAPIKeyInput) (*responses.APIKeyCreatedResponse, error) {
+	creator, err := u.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := creator
+	if input.UserID != "" {
+		ownerID, err := uuid.Parse(input.UserID)
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid user ID: %w", err))
+		}
+		owner, err = u.userRepo.FindByID(ctx, ownerID)
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrUserNotFound, err)
+		}
+	}
+	if !owner.IsActive {
+		return nil, utils.NewAppError(utils.ErrInactiveUser, fmt.Errorf("user with email %s is inactive", owner.Email))
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("expires_at is in the past"), "Expiry must be in the future")
+	}
+	for _, scope := range input.Scopes {
+		if !securities.IsValidAPIScope(scope) {
+			return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("unknown scope %q", scope), fmt.Sprintf("Unknown scope %q", scope))
+		}
+	}
+
+	rawKey, prefix, err := securities.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &entities.APIKey{
+		Name:      input.Name,
+		Kind:      entities.APIKeyKindKey,
+		Prefix:    prefix,
+		UserID:    owner.ID,
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+		CreatedBy: creator.ID,
+	}
+	response := &responses.APIKeyCreatedResponse{}
+
+	if input.Kind == string(entities.APIKeyKindHMAC) {
+		// The generated key becomes the shared secret; the client identifies
+		// itself with the prefix alone and never sends the secret.
+		secret := rawKey
+		encrypted, err := securities.EncryptSecret(u.config.RequestSigning.EncryptionKey, secret)
+		if err != nil {
+			return nil, err
+		}
+		apiKey.Kind = entities.APIKeyKindHMAC
+		apiKey.SigningSecret = encrypted
+		response.Secret = secret
+	} else {
+		apiKey.KeyHash = securities.HashToken(rawKey)
+		response.Key = rawKey
+	}
+
+	if err := u.apiKeyRepo.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	u.recordAudit(ctx, &auditEntities.AuditLog{
+		Action:     auditEntities.ActionTokenIssued,
+		TargetType: auditTargetUser,
+		TargetID:   owner.ID.String(),
+		Metadata: map[string]string{
+			"token":      "api_key",
+			"kind":       string(apiKey.Kind),
+			"api_key_id": apiKey.ID.String(),
+			"prefix":     apiKey.Prefix,
+		},
+	})
+	response.APIKey = *apiKey
+
+	return response, nil
+}
+
+func (u *userServiceImpl) ListAPIKeys(ctx context.Context, params *helpers.PaginationParams) ([]entities.APIKey, int64, error) {
+	activeOnly := params.Filter != "all"
+	keys, total, err := u.apiKeyRepo.ListAPIKeys(ctx, params, activeOnly)
+	if err != nil {
+		return nil, 0, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return keys, total, nil
+}
+
+func (u *userServiceImpl) RevokeAPIKey(ctx context.Context, id string) error {
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid api key ID: %w", err))
+	}
+
+	apiKey, err := u.apiKeyRepo.FindByID(ctx, keyID)
+	if err != nil {
+		return utils.NewAppError(utils.ErrNotFound, err)
+	}
+
+	return u.apiKeyRepo.Revoke(ctx, apiKey)
+}
+
+// AuthenticateAPIKey resolves a raw X-API-Key value to the identity of its
+// owner. Unknown, revoked and expired keys all fail the same way.
+func (u *userServiceImpl) AuthenticateAPIKey(ctx context.Context, rawKey string) (*securities.ServicePrincipal, error) {
+	invalid := utils.NewAppErrorWithMessage(utils.ErrInvalidToken, securities.ErrAPIKeyInvalid, "API key is invalid")
+
+	prefix, err := securities.ParseAPIKeyPrefix(rawKey)
+	if err != nil {
+		return nil, invalid
+	}
+
+	apiKey, err := u.apiKeyRepo.FindByPrefix(utils.WithoutTenantScope(ctx), prefix)
+	if err != nil {
+		return nil, invalid
+	}
+
+	if apiKey.Kind != entities.APIKeyKindKey || !apiKey.IsUsable(time.Now()) {
+		return nil, invalid
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(securities.HashToken(rawKey))) != 1 {
+		return nil, invalid
+	}
+
+	return u.servicePrincipal(ctx, apiKey, invalid)
+}
+
+// AuthenticateSignature verifies an HMAC-signed request made with the key
+// whose prefix is keyID. Timestamp and nonce checks happen in the
+// middleware before this is called.
+func (u *userServiceImpl) AuthenticateSignature(ctx context.Context, keyID, canonicalRequest, signature string) (*securities.ServicePrincipal, error) {
+	invalid := utils.NewAppErrorWithMessage(utils.ErrInvalidToken, securities.ErrAPIKeyInvalid, "Request signature is invalid")
+
+	apiKey, err := u.apiKeyRepo.FindByPrefix(utils.WithoutTenantScope(ctx), keyID)
+	if err != nil {
+		return nil, invalid
+	}
+	if apiKey.Kind != entities.APIKeyKindHMAC || !apiKey.IsUsable(time.Now()) {
+		return nil, invalid
+	}
+
+	secret, err := securities.DecryptSecret(u.config.RequestSigning.EncryptionKey, apiKey.SigningSecret)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("signing secret for key %s: %w", apiKey.Prefix, err))
+	}
+	if !securities.VerifyRequestSignature(secret, canonicalRequest, signature) {
+		return nil, invalid
+	}
+
+	return u.servicePrincipal(ctx, apiKey, invalid)
+}
+
+func (u *userServiceImpl) servicePrincipal(ctx context.Context, apiKey *entities.APIKey, invalid error) (*securities.ServicePrincipal, error) {
+	ctx = utils.WithTenant(ctx, apiKey.TenantID)
+	user, err := u.userRepo.FindByID(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, invalid
+	}
+	if !user.IsActive {
+		return nil, utils.NewAppError(utils.ErrInactiveUser, fmt.Errorf("user with email %s is inactive", user.Email))
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		if err := u.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+			log.Printf("Failed to record use of api key %s: %v", apiKey.Prefix, err)
+		}
+	}
+
+	return &securities.ServicePrincipal{
+		KeyID:   apiKey.ID,
+		Payload: newJWTPayload(user),
+		Scopes:  apiKey.Scopes,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"log"
+	auditEntities "testcase/internal/modules/audit/entities"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/utils"
+)
+
+// Target type of entries about a user account.
+const auditTargetUser = "user"
+
+// Reasons recorded with failed logins.
+const (
+	loginFailureInvalidCredentials = "invalid_credentials"
+	loginFailureLockedOut          = "locked_out"
+	loginFailureInactive           = "inactive"
+	loginFailureEmailNotVerified   = "email_not_verified"
+	loginFailureInvalidMFACode     = "invalid_mfa_code"
+)
+
+// recordAudit appends entry to the audit log. A failure is logged rather
+// than returned: the action it describes has already happened.
+func (u *userServiceImpl) recordAudit(ctx context.Context, entry *auditEntities.AuditLog) {
+	if err := u.audit.Record(ctx, entry); err != nil {
+		log.Printf("Failed to record %s audit entry: %v", entry.Action, err)
+	}
+}
+
+// recordLogin records a completed login of user, whatever the method.
+func (u *userServiceImpl) recordLogin(ctx context.Context, user *entities.User) {
+	u.recordAudit(ctx, &auditEntities.AuditLog{
+		OrganizationID: &user.TenantID,
+		ActorID:        &user.ID,
+		Action:         auditEntities.ActionLogin,
+		TargetType:     auditTargetUser,
+		TargetID:       user.ID.String(),
+	})
+}
+
+// recordLoginFailure records a refused login for email. The attempt is
+// attached to the account when one has the email; attempts on unknown
+// emails are platform-wide.
+func (u *userServiceImpl) recordLoginFailure(ctx context.Context, email string, user *entities.User, reason string) {
+	if user == nil {
+		user, _ = u.userRepo.FindByEmail(utils.WithoutTenantScope(ctx), email)
+	}
+
+	entry := &auditEntities.AuditLog{
+		Action:   auditEntities.ActionLoginFailed,
+		Outcome:  auditEntities.OutcomeFailure,
+		Metadata: map[string]string{"email": normalizeEmail(email), "reason": reason},
+	}
+	if user != nil {
+		entry.OrganizationID = &user.TenantID
+		entry.TargetType = auditTargetUser
+		entry.TargetID = user.ID.String()
+	}
+
+	u.recordAudit(ctx, entry)
+}
+
+// recordTokenIssued records a token user obtained for themselves. Kind
+// names the token: session, refresh or step_up.
+func (u *userServiceImpl) recordTokenIssued(ctx context.Context, user *entities.User, kind string, metadata map[string]string) {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	metadata["token"] = kind
+
+	u.recordAudit(ctx, &auditEntities.AuditLog{
+		OrganizationID: &user.TenantID,
+		ActorID:        &user.ID,
+		Action:         auditEntities.ActionTokenIssued,
+		TargetType:     auditTargetUser,
+		TargetID:       user.ID.String(),
+		Metadata:       metadata,
+	})
+}
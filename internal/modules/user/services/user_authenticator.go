@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testcase/internal/modules/user/entities"
+)
+
+// Authenticator checks an email and password and returns the local user
+// they belong to. Unknown users and wrong passwords both return
+// ErrInvalidCredentials so callers can't tell them apart.
+type Authenticator interface {
+	Authenticate(ctx context.Context, email, password string) (*entities.User, error)
+}
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
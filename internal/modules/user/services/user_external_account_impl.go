@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// externalAccount is a user authenticated by an outside identity source,
+// either an OIDC provider or an LDAP directory.
+type externalAccount struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string
+	Groups        []string
+}
+
+// externalRolePrecedence decides which role wins when a user's groups map
+// to several roles. Custom roles rank below these and above RoleUser.
+var externalRolePrecedence = []entities.RoleEnum{
+	entities.RoleAdmin,
+	entities.RoleAdmin3,
+	entities.RoleAdmin2,
+	entities.RoleAdmin1,
+}
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// resolveExternalUser finds the user linked to the external account, links
+// an existing user with the same verified email, or provisions a new one.
+// When a role mapping is configured the role follows the account's groups
+// on every login. Identity sources are configured for the default
+// organization, so users of other organizations are never linked or
+// signed in through them.
+func (u *userServiceImpl) resolveExternalUser(ctx context.Context, account *externalAccount, roleMapping map[string]string, defaultRole string) (*entities.User, error) {
+	now := time.Now()
+	mappedRole, hasMapping := mapGroupsToRole(account.Groups, roleMapping, defaultRole)
+
+	ctx, err := u.defaultOrganizationContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if hasMapping {
+		if err := u.ensureRoleExists(ctx, mappedRole); err != nil {
+			return nil, err
+		}
+	}
+
+	identity, err := u.identityRepo.FindByIssuerSubject(ctx, account.Issuer, account.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *entities.User
+	if identity != nil {
+		user, err = u.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrUserNotFound, err)
+		}
+	} else {
+		user, err = u.linkOrProvisionExternalUser(ctx, account, mappedRole)
+		if err != nil {
+			return nil, err
+		}
+		identity = &entities.ExternalIdentity{
+			UserID:  user.ID,
+			Issuer:  account.Issuer,
+			Subject: account.Subject,
+		}
+	}
+
+	if hasMapping && user.Role != mappedRole {
+		user.Role = mappedRole
+		if err := u.userRepo.UpdateUser(ctx, user); err != nil {
+			return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+		}
+		// Revoking the whole user would also reject the token this login
+		// is about to issue within the same second.
+		if err := u.revokeSessions(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	if account.EmailVerified && !user.IsEmailVerified() && strings.EqualFold(account.Email, user.Email) {
+		user.EmailVerifiedAt = &now
+		if err := u.userRepo.UpdateUser(ctx, user); err != nil {
+			return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+		}
+	}
+
+	identity.Email = account.Email
+	identity.LastLoginAt = &now
+	if err := u.identityRepo.SaveIdentity(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *userServiceImpl) linkOrProvisionExternalUser(ctx context.Context, account *externalAccount, role entities.RoleEnum) (*entities.User, error) {
+	if account.Email == "" {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("external account %s has no email", account.Subject), "Identity provider did not share an email address")
+	}
+
+	if existing, _ := u.userRepo.FindByEmail(utils.WithoutTenantScope(ctx), account.Email); existing != nil {
+		// Only link on a verified email, otherwise anyone able to set an
+		// arbitrary email at the provider could take over the account.
+		if !account.EmailVerified {
+			return nil, utils.NewAppErrorWithMessage(utils.ErrEmailExists, fmt.Errorf("unverified external email %s matches an existing user", account.Email), "An account with this email already exists")
+		}
+		if tenantID, _ := ctx.Value(utils.TenantIDContextKey).(uuid.UUID); existing.TenantID != tenantID {
+			return nil, utils.NewAppErrorWithMessage(utils.ErrEmailExists, fmt.Errorf("external email %s belongs to a user of organization %s", account.Email, existing.TenantID), "An account with this email already exists")
+		}
+		return existing, nil
+	}
+
+	// External users never sign in with a local password; store an
+	// unguessable one so the column stays populated and password login
+	// can't succeed.
+	randomPassword, err := securities.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := securities.HashPassword(randomPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	name := account.Name
+	if name == "" {
+		name = account.Email
+	}
+
+	user := &entities.User{
+		Name:     name,
+		Username: u.availableUsername(ctx, account),
+		Email:    account.Email,
+		Password: hashedPassword,
+		Role:     role,
+		IsActive: true,
+	}
+	if account.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := u.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, utils.NewAppError(utils.ErrCreateUserError, err)
+	}
+
+	return user, nil
+}
+
+// mapGroupsToRole maps groups to a role. The boolean is false when no
+// mapping is configured, in which case roles are managed locally.
+func mapGroupsToRole(groups []string, roleMapping map[string]string, defaultRole string) (entities.RoleEnum, bool) {
+	if len(roleMapping) == 0 {
+		return entities.RoleEnum(defaultRole), false
+	}
+
+	granted := make(map[entities.RoleEnum]bool)
+	var firstCustom entities.RoleEnum
+	for _, group := range groups {
+		if role, ok := roleMapping[group]; ok {
+			granted[entities.RoleEnum(role)] = true
+			if firstCustom == "" && !isBuiltInRole(entities.RoleEnum(role)) {
+				firstCustom = entities.RoleEnum(role)
+			}
+		}
+	}
+	for _, role := range externalRolePrecedence {
+		if granted[role] {
+			return role, true
+		}
+	}
+	// Custom roles have no rank among themselves; take the first one the
+	// groups name.
+	if firstCustom != "" {
+		return firstCustom, true
+	}
+	if granted[entities.RoleUser] {
+		return entities.RoleUser, true
+	}
+
+	return entities.RoleEnum(defaultRole), true
+}
+
+func isBuiltInRole(role entities.RoleEnum) bool {
+	if role == entities.RoleUser {
+		return true
+	}
+	for _, r := range externalRolePrecedence {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *userServiceImpl) availableUsername(ctx context.Context, account *externalAccount) string {
+	base := account.Username
+	if base == "" {
+		base, _, _ = strings.Cut(account.Email, "@")
+	}
+	base = usernameInvalidChars.ReplaceAllString(base, "")
+	if len(base) > 90 {
+		base = base[:90]
+	}
+	for len(base) < 3 {
+		base += "0"
+	}
+
+	candidate := base
+	for i := 1; ; i++ {
+		if existing, _ := u.userRepo.FindByUsername(utils.WithoutTenantScope(ctx), candidate); existing == nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+}
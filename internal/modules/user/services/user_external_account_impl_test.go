@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testcase/config"
+	organizationEntities "testcase/internal/modules/organization/entities"
+	organizationRepositories "testcase/internal/modules/organization/repositories"
+	roleEntities "testcase/internal/modules/role/entities"
+	roleRepositories "testcase/internal/modules/role/repositories"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/modules/user/repositories"
+	"testcase/internal/utils"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type defaultOrganizationRepository struct {
+	organizationRepositories.OrganizationRepository
+	organization *organizationEntities.Organization
+}
+
+func (r *defaultOrganizationRepository) FindBySlug(ctx context.Context, slug string) (*organizationEntities.Organization, error) {
+	if slug != r.organization.Slug {
+		return nil, nil
+	}
+	return r.organization, nil
+}
+
+type namedRoleRepository struct {
+	roleRepositories.RoleRepository
+	names []string
+}
+
+func (r *namedRoleRepository) FindByName(ctx context.Context, name string) (*roleEntities.Role, error) {
+	for _, existing := range r.names {
+		if existing == name {
+			return &roleEntities.Role{Name: name}, nil
+		}
+	}
+	return nil, nil
+}
+
+type memoryIdentityRepository struct {
+	repositories.ExternalIdentityRepository
+	identities []*entities.ExternalIdentity
+}
+
+func (r *memoryIdentityRepository) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*entities.ExternalIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryIdentityRepository) SaveIdentity(ctx context.Context, identity *entities.ExternalIdentity) error {
+	for _, existing := range r.identities {
+		if existing == identity {
+			return nil
+		}
+	}
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func newExternalAccountService(users ...*entities.User) (*userServiceImpl, *memoryIdentityRepository, uuid.UUID) {
+	organization := &organizationEntities.Organization{ID: uuid.New(), Slug: "default"}
+	identities := &memoryIdentityRepository{}
+	service := &userServiceImpl{
+		userRepo:         newMemoryUserRepository(users...),
+		identityRepo:     identities,
+		roleRepo:         &namedRoleRepository{names: []string{"user", "admin1"}},
+		organizationRepo: &defaultOrganizationRepository{organization: organization},
+		config:           &config.Config{Organization: config.Organization{DefaultSlug: "default"}},
+	}
+	return service, identities, organization.ID
+}
+
+func TestResolveExternalUserLinksVerifiedEmailInDefaultOrganization(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	service, identities, organizationID := newExternalAccountService(user)
+	user.TenantID = organizationID
+
+	resolved, err := service.resolveExternalUser(context.Background(), &externalAccount{
+		Issuer:        "https://idp.example.com",
+		Subject:       "jane",
+		Email:         user.Email,
+		EmailVerified: true,
+	}, nil, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.ID != user.ID || len(identities.identities) != 1 {
+		t.Fatalf("expected the existing user to be linked, got %+v", resolved)
+	}
+}
+
+func TestResolveExternalUserRefusesUsersOfOtherOrganizations(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	service, identities, _ := newExternalAccountService(user)
+
+	_, err := service.resolveExternalUser(context.Background(), &externalAccount{
+		Issuer:        "https://idp.example.com",
+		Subject:       "jane",
+		Email:         user.Email,
+		EmailVerified: true,
+	}, nil, "user")
+
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) || appErr.ErrorCode != utils.ErrEmailExists {
+		t.Fatalf("expected the email to be refused, got %v", err)
+	}
+	if len(identities.identities) != 0 {
+		t.Fatal("expected no identity to be linked")
+	}
+}
+
+func TestResolveExternalUserRejectsUnknownMappedRole(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	service, identities, organizationID := newExternalAccountService(user)
+	user.TenantID = organizationID
+
+	_, err := service.resolveExternalUser(context.Background(), &externalAccount{
+		Issuer:        "https://idp.example.com",
+		Subject:       "jane",
+		Email:         user.Email,
+		EmailVerified: true,
+		Groups:        []string{"auditors"},
+	}, map[string]string{"auditors": "auditor"}, "user")
+
+	var appErr *utils.AppError
+	if !errors.As(err, &appErr) || appErr.ErrorCode != utils.ErrInvalidRequest {
+		t.Fatalf("expected the unknown role to be rejected, got %v", err)
+	}
+	if user.Role != entities.RoleUser || len(identities.identities) != 0 {
+		t.Fatalf("expected the user to be left alone, got role %s", user.Role)
+	}
+}
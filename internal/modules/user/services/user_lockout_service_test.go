@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testcase/config"
+	"testcase/internal/modules/user/dto"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/modules/user/repositories"
+	"testcase/internal/utils"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryLockoutRepository counts failures under a lock, which is what the
+// upsert in the database repository guarantees.
+type memoryLockoutRepository struct {
+	repositories.LoginLockoutRepository
+
+	mu       sync.Mutex
+	lockouts map[string]*entities.LoginLockout
+}
+
+func newMemoryLockoutRepository() *memoryLockoutRepository {
+	return &memoryLockoutRepository{lockouts: map[string]*entities.LoginLockout{}}
+}
+
+func (r *memoryLockoutRepository) Find(ctx context.Context, kind entities.LockoutKind, identifier string) (*entities.LoginLockout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lockout, ok := r.lockouts[string(kind)+":"+identifier]
+	if !ok {
+		return nil, nil
+	}
+	found := *lockout
+	return &found, nil
+}
+
+func (r *memoryLockoutRepository) RecordFailure(ctx context.Context, kind entities.LockoutKind, identifier string, now time.Time, window time.Duration) (*entities.LoginLockout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := string(kind) + ":" + identifier
+	lockout, ok := r.lockouts[key]
+	switch {
+	case !ok:
+		lockout = &entities.LoginLockout{ID: uuid.New(), Kind: kind, Identifier: identifier, FailedAttempts: 1}
+		r.lockouts[key] = lockout
+	case lockout.LastFailedAt.Before(now.Add(-window)) && !lockout.IsLocked(now):
+		lockout.FailedAttempts = 1
+	default:
+		lockout.FailedAttempts++
+	}
+	lockout.LastFailedAt = now
+
+	recorded := *lockout
+	return &recorded, nil
+}
+
+func (r *memoryLockoutRepository) Lock(ctx context.Context, id uuid.UUID, lockedUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, lockout := range r.lockouts {
+		if lockout.ID == id && (lockout.LockedUntil == nil || lockout.LockedUntil.Before(lockedUntil)) {
+			lockout.LockedUntil = &lockedUntil
+		}
+	}
+	return nil
+}
+
+func newLockoutService(repo repositories.LoginLockoutRepository) *userServiceImpl {
+	return &userServiceImpl{
+		lockoutRepo: repo,
+		config: &config.Config{Lockout: config.Lockout{
+			AccountThreshold: 5,
+			IPThreshold:      20,
+			Window:           15 * time.Minute,
+			BaseDuration:     time.Minute,
+			MaxDuration:      time.Hour,
+		}},
+	}
+}
+
+func TestRecordFailedLoginCountsConcurrentFailures(t *testing.T) {
+	repo := newMemoryLockoutRepository()
+	service := newLockoutService(repo)
+	ctx := context.WithValue(context.Background(), utils.IPAddressContextKey, "203.0.113.7")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := service.recordFailedLogin(ctx, "Alice@Example.com "); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	account, _ := repo.Find(ctx, entities.LockoutKindAccount, "alice@example.com")
+	if account == nil || account.FailedAttempts != 30 {
+		t.Fatalf("expected 30 account failures, got %+v", account)
+	}
+	ip, _ := repo.Find(ctx, entities.LockoutKindIP, "203.0.113.7")
+	if ip == nil || ip.FailedAttempts != 30 || !ip.IsLocked(time.Now()) {
+		t.Fatalf("expected the IP to be locked after 30 failures, got %+v", ip)
+	}
+
+	var appErr *utils.AppError
+	if err := service.checkLoginLockout(ctx, "alice@example.com"); !errors.As(err, &appErr) || appErr.ErrorCode != utils.ErrTooManyAttempts {
+		t.Fatalf("expected a too many attempts error, got %v", err)
+	}
+}
+
+func TestRecordFailedLoginLocksAtThreshold(t *testing.T) {
+	repo := newMemoryLockoutRepository()
+	service := newLockoutService(repo)
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		if err := service.checkLoginLockout(ctx, "bob@example.com"); err != nil {
+			t.Fatalf("attempt %d rejected before the threshold: %v", i, err)
+		}
+		if err := service.recordFailedLogin(ctx, "bob@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lockout, _ := repo.Find(ctx, entities.LockoutKindAccount, "bob@example.com")
+	if lockout.LockedUntil == nil {
+		t.Fatal("expected the account to be locked at the threshold")
+	}
+	if remaining := time.Until(*lockout.LockedUntil); remaining <= 0 || remaining > time.Minute {
+		t.Fatalf("expected a lock of at most the base duration, got %s", remaining)
+	}
+	if err := service.checkLoginLockout(ctx, "bob@example.com"); err == nil {
+		t.Fatal("expected the locked account to be rejected")
+	}
+}
+
+func TestRecordFailedLoginStartsOverAfterWindow(t *testing.T) {
+	repo := newMemoryLockoutRepository()
+	service := newLockoutService(repo)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		if err := service.recordFailedLogin(ctx, "carol@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo.lockouts["account:carol@example.com"].LastFailedAt = time.Now().Add(-time.Hour)
+
+	if err := service.recordFailedLogin(ctx, "carol@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	lockout, _ := repo.Find(ctx, entities.LockoutKindAccount, "carol@example.com")
+	if lockout.FailedAttempts != 1 || lockout.LockedUntil != nil {
+		t.Fatalf("expected the count to start over after the window, got %+v", lockout)
+	}
+}
+
+func TestCheckLoginLockoutAllowsExpiredLock(t *testing.T) {
+	repo := newMemoryLockoutRepository()
+	service := newLockoutService(repo)
+	ctx := context.Background()
+
+	expired := time.Now().Add(-time.Second)
+	repo.lockouts["account:dave@example.com"] = &entities.LoginLockout{
+		ID:             uuid.New(),
+		Kind:           entities.LockoutKindAccount,
+		Identifier:     "dave@example.com",
+		FailedAttempts: 5,
+		LastFailedAt:   time.Now().Add(-time.Minute),
+		LockedUntil:    &expired,
+	}
+
+	if err := service.checkLoginLockout(ctx, "dave@example.com"); err != nil {
+		t.Fatalf("expected an expired lock to let the attempt through, got %v", err)
+	}
+}
+
+func TestStepUpWrongPasswordCountsTowardsLockout(t *testing.T) {
+	user := &entities.User{ID: uuid.New(), Email: "erin@example.com", IsActive: true}
+	repo := newMemoryLockoutRepository()
+	service := newLockoutService(repo)
+	service.userRepo = newMemoryUserRepository(user)
+	service.authenticator = rejectingAuthenticator{}
+	service.audit = discardAuditService{}
+	ctx := context.WithValue(context.Background(), utils.UserIDContextKey, user.ID)
+	input := &dto.StepUpInput{Scope: "document.action", ResourceID: uuid.NewString(), Password: "wrong"}
+
+	for i := 0; i < 5; i++ {
+		var appErr *utils.AppError
+		if _, err := service.StepUp(ctx, input); !errors.As(err, &appErr) || appErr.ErrorCode != utils.ErrInvalidCredentials {
+			t.Fatalf("attempt %d: expected invalid credentials, got %v", i+1, err)
+		}
+	}
+
+	var appErr *utils.AppError
+	if _, err := service.StepUp(ctx, input); !errors.As(err, &appErr) || appErr.ErrorCode != utils.ErrTooManyAttempts {
+		t.Fatalf("expected step-up to be locked out, got %v", err)
+	}
+}
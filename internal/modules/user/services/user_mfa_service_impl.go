@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testcase/internal/modules/user/dto"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/modules/user/responses"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"time"
+)
+
+func (u *userServiceImpl) EnrollMFA(ctx context.Context) (*responses.MFAEnrollmentResponse, error) {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrConflict, fmt.Errorf("mfa already enabled"), "Two-factor authentication is already enabled")
+	}
+
+	secret, err := securities.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := securities.EncryptSecret(u.config.MFA.EncryptionKey, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFASecret = encrypted
+	user.MFALastUsedStep = 0
+	if err := u.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &responses.MFAEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: securities.TOTPProvisioningURI(u.config.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (u *userServiceImpl) ActivateMFA(ctx context.Context, input *dto.MFACodeInput) (*responses.MFARecoveryCodesResponse, error) {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrConflict, fmt.Errorf("mfa already enabled"), "Two-factor authentication is already enabled")
+	}
+	if user.MFASecret == "" {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("mfa enrollment not started"), "Start two-factor enrollment first")
+	}
+
+	if err := u.verifyTOTP(ctx, user, input.Code); err != nil {
+		return nil, err
+	}
+
+	user.MFAEnabled = true
+	if err := u.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	codes, err := u.generateRecoveryCodes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *userServiceImpl) DisableMFA(ctx context.Context, input *dto.MFACodeInput) error {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("mfa not enabled"), "Two-factor authentication is not enabled")
+	}
+	mfaRequired, err := u.mfaRequired(ctx, user.Role)
+	if err != nil {
+		return err
+	}
+	if mfaRequired {
+		return utils.NewAppErrorWithMessage(utils.ErrForbiddenAccess, fmt.Errorf("mfa required for role %s", user.Role), "Two-factor authentication is mandatory for your role")
+	}
+
+	if err := u.verifyTOTP(ctx, user, input.Code); err != nil {
+		return err
+	}
+
+	user.MFAEnabled = false
+	user.MFASecret = ""
+	user.MFALastUsedStep = 0
+	if err := u.userRepo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	return u.mfaRepo.DeleteRecoveryCodes(ctx, user.ID)
+}
+
+func (u *userServiceImpl) RegenerateRecoveryCodes(ctx context.Context, input *dto.MFACodeInput) (*responses.MFARecoveryCodesResponse, error) {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("mfa not enabled"), "Two-factor authentication is not enabled")
+	}
+
+	if err := u.verifyTOTP(ctx, user, input.Code); err != nil {
+		return nil, err
+	}
+
+	codes, err := u.generateRecoveryCodes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *userServiceImpl) VerifyMFALogin(ctx context.Context, input *dto.MFALoginInput) (*responses.LoginResponse, error) {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, utils.NewAppError(utils.ErrInactiveUser, fmt.Errorf("user with email %s is inactive", user.Email))
+	}
+	if !user.MFAEnabled {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("mfa enrollment required"), "Two-factor enrollment is required before login")
+	}
+	if err := u.checkLoginLockout(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	if err := u.verifyMFALoginCode(ctx, user, input); err != nil {
+		u.recordLoginFailure(ctx, user.Email, user, loginFailureInvalidMFACode)
+		if recordErr := u.recordFailedLogin(ctx, user.Email); recordErr != nil {
+			return nil, recordErr
+		}
+		return nil, err
+	}
+	if err := u.clearFailedLogins(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	return u.issueLoginTokens(ctx, user)
+}
+
+func (u *userServiceImpl) verifyMFALoginCode(ctx context.Context, user *entities.User, input *dto.MFALoginInput) error {
+	if input.Code != "" {
+		return u.verifyTOTP(ctx, user, input.Code)
+	}
+
+	code, err := u.mfaRepo.FindUnusedRecoveryCode(ctx, user.ID, securities.HashToken(normalizeRecoveryCode(input.RecoveryCode)))
+	if err != nil {
+		return utils.NewAppError(utils.ErrInvalidMFACode, err)
+	}
+	if err := u.mfaRepo.MarkRecoveryCodeUsed(ctx, code); err != nil {
+		return utils.NewAppError(utils.ErrInvalidMFACode, err)
+	}
+
+	return nil
+}
+
+func (u *userServiceImpl) CompleteMFAEnrollment(ctx context.Context, input *dto.MFACodeInput) (*responses.LoginResponse, error) {
+	recovery, err := u.ActivateMFA(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loginResponse, err := u.issueLoginTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	loginResponse.RecoveryCodes = recovery.RecoveryCodes
+
+	return loginResponse, nil
+}
+
+func (u *userServiceImpl) EncryptMFASecrets(ctx context.Context) error {
+	users, err := u.userRepo.FindWithMFASecret(utils.WithoutTenantScope(ctx))
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		user := &users[i]
+		if !securities.IsTOTPSecret(user.MFASecret) {
+			continue
+		}
+
+		encrypted, err := securities.EncryptSecret(u.config.MFA.EncryptionKey, user.MFASecret)
+		if err != nil {
+			return err
+		}
+		user.MFASecret = encrypted
+		if err := u.userRepo.UpdateUser(utils.WithTenant(ctx, user.TenantID), user); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *userServiceImpl) issueMFAChallenge(user *entities.User) (*responses.LoginResponse, error) {
+	expiry := u.config.MFA.ChallengeExpiry
+	token, err := u.jwtManager.GenerateMFAToken(newJWTPayload(user), expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.LoginResponse{
+		User: *user,
+		MFA: &responses.MFAChallenge{
+			Token:              token,
+			ExpiresIn:          int64(expiry.Seconds()),
+			EnrollmentRequired: !user.MFAEnabled,
+		},
+	}, nil
+}
+
+// verifyTOTP accepts each time step at most once so an intercepted code
+// cannot be replayed within its validity window.
+func (u *userServiceImpl) verifyTOTP(ctx context.Context, user *entities.User, code string) error {
+	secret, err := securities.DecryptSecret(u.config.MFA.EncryptionKey, user.MFASecret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt mfa secret: %w", err)
+	}
+
+	step, ok := securities.ValidateTOTPCode(secret, code, time.Now())
+	if !ok || step <= user.MFALastUsedStep {
+		return utils.NewAppError(utils.ErrInvalidMFACode, fmt.Errorf("invalid totp code"))
+	}
+
+	user.MFALastUsedStep = step
+	return u.userRepo.UpdateUser(ctx, user)
+}
+
+func (u *userServiceImpl) generateRecoveryCodes(ctx context.Context, user *entities.User) ([]string, error) {
+	codes := make([]string, 0, u.config.MFA.RecoveryCodeCount)
+	records := make([]entities.MFARecoveryCode, 0, u.config.MFA.RecoveryCodeCount)
+
+	for i := 0; i < u.config.MFA.RecoveryCodeCount; i++ {
+		raw, err := securities.GenerateRandomToken(5)
+		if err != nil {
+			return nil, err
+		}
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		records = append(records, entities.MFARecoveryCode{
+			UserID:   user.ID,
+			CodeHash: securities.HashToken(normalizeRecoveryCode(code)),
+		})
+	}
+
+	if err := u.mfaRepo.ReplaceRecoveryCodes(ctx, user.ID, records); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
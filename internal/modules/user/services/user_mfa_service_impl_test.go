@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testcase/config"
+	"testcase/internal/modules/user/dto"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/modules/user/repositories"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryMFARepository keeps recovery codes in memory. MarkRecoveryCodeUsed
+// succeeds once per code, like the conditional update it stands in for.
+type memoryMFARepository struct {
+	repositories.MFARepository
+
+	mu    sync.Mutex
+	codes []*entities.MFARecoveryCode
+}
+
+func (r *memoryMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []entities.MFARecoveryCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.codes[:0]
+	for _, code := range r.codes {
+		if code.UserID != userID {
+			kept = append(kept, code)
+		}
+	}
+	for _, code := range codes {
+		code.ID = uuid.New()
+		kept = append(kept, &code)
+	}
+	r.codes = kept
+	return nil
+}
+
+func (r *memoryMFARepository) FindUnusedRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (*entities.MFARecoveryCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, code := range r.codes {
+		if code.UserID == userID && code.CodeHash == codeHash && code.UsedAt == nil {
+			found := *code
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("recovery code not found")
+}
+
+func (r *memoryMFARepository) MarkRecoveryCodeUsed(ctx context.Context, used *entities.MFARecoveryCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, code := range r.codes {
+		if code.ID == used.ID && code.UsedAt == nil {
+			now := time.Now()
+			code.UsedAt = &now
+			return nil
+		}
+	}
+	return fmt.Errorf("recovery code already used")
+}
+
+func newMFAService(users *memoryUserRepository) *userServiceImpl {
+	return &userServiceImpl{
+		userRepo: users,
+		mfaRepo:  &memoryMFARepository{},
+		config: &config.Config{MFA: config.MFA{
+			Issuer:            "Testcase",
+			RecoveryCodeCount: 3,
+			EncryptionKey:     "mfa-key",
+		}},
+	}
+}
+
+func TestEnrollMFAStoresEncryptedSecret(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	users := newMemoryUserRepository(user)
+	service := newMFAService(users)
+	ctx := context.WithValue(context.Background(), utils.UserIDContextKey, user.ID)
+
+	enrollment, err := service.EnrollMFA(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ := users.FindByID(ctx, user.ID)
+	if stored.MFASecret == enrollment.Secret || securities.IsTOTPSecret(stored.MFASecret) {
+		t.Fatalf("expected the stored secret to be encrypted, got %q", stored.MFASecret)
+	}
+	secret, err := securities.DecryptSecret("mfa-key", stored.MFASecret)
+	if err != nil || secret != enrollment.Secret {
+		t.Fatalf("expected the stored secret to decrypt to the enrolled one, got %q (%v)", secret, err)
+	}
+}
+
+func TestTOTPCodeCannotBeReplayed(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	service := newMFAService(newMemoryUserRepository(user))
+	ctx := context.WithValue(context.Background(), utils.UserIDContextKey, user.ID)
+
+	enrollment, err := service.EnrollMFA(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := securities.GenerateTOTPCode(enrollment.Secret, time.Now())
+
+	if _, err := service.ActivateMFA(ctx, &dto.MFACodeInput{Code: code}); err != nil {
+		t.Fatalf("expected the first use of the code to activate MFA, got %v", err)
+	}
+
+	var appErr *utils.AppError
+	if _, err := service.RegenerateRecoveryCodes(ctx, &dto.MFACodeInput{Code: code}); !errors.As(err, &appErr) || appErr.ErrorCode != utils.ErrInvalidMFACode {
+		t.Fatalf("expected the replayed code to be rejected, got %v", err)
+	}
+}
+
+func TestRecoveryCodeWorksOnce(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	service := newMFAService(newMemoryUserRepository(user))
+	ctx := context.WithValue(context.Background(), utils.UserIDContextKey, user.ID)
+
+	enrollment, err := service.EnrollMFA(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := securities.GenerateTOTPCode(enrollment.Secret, time.Now())
+	recovery, err := service.ActivateMFA(ctx, &dto.MFACodeInput{Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recovery.RecoveryCodes) != 3 {
+		t.Fatalf("expected 3 recovery codes, got %d", len(recovery.RecoveryCodes))
+	}
+
+	stored, _ := service.userRepo.FindByID(ctx, user.ID)
+	input := &dto.MFALoginInput{RecoveryCode: " " + strings.ToUpper(recovery.RecoveryCodes[0]) + " "}
+	if err := service.verifyMFALoginCode(ctx, stored, input); err != nil {
+		t.Fatalf("expected the recovery code to be accepted, got %v", err)
+	}
+
+	var appErr *utils.AppError
+	if err := service.verifyMFALoginCode(ctx, stored, input); !errors.As(err, &appErr) || appErr.ErrorCode != utils.ErrInvalidMFACode {
+		t.Fatalf("expected the used recovery code to be rejected, got %v", err)
+	}
+	if err := service.verifyMFALoginCode(ctx, stored, &dto.MFALoginInput{RecoveryCode: recovery.RecoveryCodes[1]}); err != nil {
+		t.Fatalf("expected the other recovery codes to stay valid, got %v", err)
+	}
+}
+
+func TestEncryptMFASecretsSealsLegacySecrets(t *testing.T) {
+	legacy, _ := securities.GenerateTOTPSecret()
+	plaintext := newActiveUser(entities.RoleUser)
+	plaintext.MFASecret = legacy
+
+	sealed, _ := securities.EncryptSecret("mfa-key", legacy)
+	encrypted := newActiveUser(entities.RoleUser)
+	encrypted.MFASecret = sealed
+
+	users := newMemoryUserRepository(plaintext, encrypted)
+	service := newMFAService(users)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := service.EncryptMFASecrets(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrated, _ := users.FindByID(ctx, plaintext.ID)
+	if secret, err := securities.DecryptSecret("mfa-key", migrated.MFASecret); err != nil || secret != legacy {
+		t.Fatalf("expected the legacy secret to be encrypted once, got %q (%v)", migrated.MFASecret, err)
+	}
+	untouched, _ := users.FindByID(ctx, encrypted.ID)
+	if untouched.MFASecret != sealed {
+		t.Fatal("expected an encrypted secret to be left alone")
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/modules/user/repositories"
+	"testcase/package/securities"
+)
+
+// passwordAuthenticator checks the bcrypt hash stored in the users table.
+type passwordAuthenticator struct {
+	userRepo repositories.UserRepository
+}
+
+func NewPasswordAuthenticator(userRepo repositories.UserRepository) Authenticator {
+	return &passwordAuthenticator{userRepo: userRepo}
+}
+
+func (a *passwordAuthenticator) Authenticate(ctx context.Context, email, password string) (*entities.User, error) {
+	user, err := a.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		securities.DummyVerifyPassword(password)
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := securities.VerifyPassword(user.Password, password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
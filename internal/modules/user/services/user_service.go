@@ -2,13 +2,48 @@ package services
 
 import (
 	"context"
+	"testcase/internal/helpers"
 	"testcase/internal/modules/user/dto"
 	"testcase/internal/modules/user/entities"
 	"testcase/internal/modules/user/responses"
+	"testcase/package/securities"
 )
 
 type UserService interface {
 	CreateUser(ctx context.Context, input *dto.CreateUserInput) (*entities.User, error)
+	// RegisterUser signs up a new user in the default organization with
+	// the user role, whatever the caller asks for.
+	RegisterUser(ctx context.Context, input *dto.RegisterUserInput) (*entities.User, error)
+	UpdateUser(ctx context.Context, id string, input *dto.UpdateUserInput) (*entities.User, error)
+	ChangePassword(ctx context.Context, input *dto.ChangePasswordInput) error
 	LoginUser(ctx context.Context, input *dto.LoginUserInput) (*responses.LoginResponse, error)
 	RefreshToken(ctx context.Context) (*responses.LoginResponse, error)
+	Logout(ctx context.Context) error
+	LogoutAll(ctx context.Context) error
+	ListMySessions(ctx context.Context) ([]responses.SessionResponse, error)
+	RevokeMySession(ctx context.Context, sessionID string) error
+	ListUserSessions(ctx context.Context, userID string) ([]responses.SessionResponse, error)
+	RevokeUserSession(ctx context.Context, userID, sessionID string) error
+	VerifyEmail(ctx context.Context, input *dto.VerifyEmailInput) (*entities.User, error)
+	ResendVerification(ctx context.Context, input *dto.ResendVerificationInput) error
+	EnrollMFA(ctx context.Context) (*responses.MFAEnrollmentResponse, error)
+	ActivateMFA(ctx context.Context, input *dto.MFACodeInput) (*responses.MFARecoveryCodesResponse, error)
+	DisableMFA(ctx context.Context, input *dto.MFACodeInput) error
+	RegenerateRecoveryCodes(ctx context.Context, input *dto.MFACodeInput) (*responses.MFARecoveryCodesResponse, error)
+	VerifyMFALogin(ctx context.Context, input *dto.MFALoginInput) (*responses.LoginResponse, error)
+	CompleteMFAEnrollment(ctx context.Context, input *dto.MFACodeInput) (*responses.LoginResponse, error)
+	// EncryptMFASecrets seals TOTP secrets stored before they were
+	// encrypted at rest. It is safe to run on every start.
+	EncryptMFASecrets(ctx context.Context) error
+	StepUp(ctx context.Context, input *dto.StepUpInput) (*responses.StepUpResponse, error)
+	ListLockouts(ctx context.Context, params *helpers.PaginationParams) ([]entities.LoginLockout, int64, error)
+	ClearLockout(ctx context.Context, id string) error
+	CreateAPIKey(ctx context.Context, input *dto.CreateAPIKeyInput) (*responses.APIKeyCreatedResponse, error)
+	ListAPIKeys(ctx context.Context, params *helpers.PaginationParams) ([]entities.APIKey, int64, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*securities.ServicePrincipal, error)
+	AuthenticateSignature(ctx context.Context, keyID, canonicalRequest, signature string) (*securities.ServicePrincipal, error)
+	StartSSOLogin(ctx context.Context) (*responses.SSOAuthorizationResponse, error)
+	CompleteSSOLogin(ctx context.Context, input *dto.SSOCallbackInput) (*responses.LoginResponse, error)
+	JWKS() *securities.JWKS
 }
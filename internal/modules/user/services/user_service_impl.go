@@ -2,12 +2,24 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"testcase/config"
+	auditServices "testcase/internal/modules/audit/services"
+	organizationRepositories "testcase/internal/modules/organization/repositories"
+	roleRepositories "testcase/internal/modules/role/repositories"
+	settingEntities "testcase/internal/modules/setting/entities"
+	settingServices "testcase/internal/modules/setting/services"
 	"testcase/internal/modules/user/dto"
 	"testcase/internal/modules/user/entities"
 	"testcase/internal/modules/user/repositories"
 	"testcase/internal/modules/user/responses"
 	"testcase/internal/utils"
+	"testcase/package/ldap"
+	"testcase/package/mailer"
+	"testcase/package/oidc"
 	"testcase/package/securities"
 	"time"
 
@@ -15,19 +27,48 @@ import (
 )
 
 type userServiceImpl struct {
-	userRepo   repositories.UserRepository
-	jwtManager *securities.JWTManager
+	userRepo         repositories.UserRepository
+	verificationRepo repositories.EmailVerificationRepository
+	mfaRepo          repositories.MFARepository
+	lockoutRepo      repositories.LoginLockoutRepository
+	sessionRepo      repositories.SessionRepository
+	apiKeyRepo       repositories.APIKeyRepository
+	identityRepo     repositories.ExternalIdentityRepository
+	roleRepo         roleRepositories.RoleRepository
+	organizationRepo organizationRepositories.OrganizationRepository
+	settings         settingServices.SettingService
+	jwtManager       *securities.JWTManager
+	revocations      *securities.RevocationList
+	oidcProvider     *oidc.LazyProvider
+	authenticator    Authenticator
+	mailer           mailer.Mailer
+	audit            auditServices.AuditService
+	config           *config.Config
 }
 
+// CreateUser adds the user to the organization in ctx, joining the default
+// one when ctx carries none. Usernames and emails are unique across all
+// organizations since login doesn't ask for one.
 func (u *userServiceImpl) CreateUser(ctx context.Context, input *dto.CreateUserInput) (*entities.User, error) {
-	username, _ := u.userRepo.FindByUsername(input.Username)
+	ctx, err := u.registrationContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	username, _ := u.userRepo.FindByUsername(utils.WithoutTenantScope(ctx), input.Username)
 	if username != nil {
 		return nil, utils.NewAppError(utils.ErrUsernameExists, fmt.Errorf("username already exists"))
 	}
-	email, _ := u.userRepo.FindByEmail(input.Email)
+	email, _ := u.userRepo.FindByEmail(utils.WithoutTenantScope(ctx), input.Email)
 	if email != nil {
 		return nil, utils.NewAppError(utils.ErrEmailExists, fmt.Errorf("email already exists"))
 	}
+	if err := u.ensureRoleExists(ctx, input.Role); err != nil {
+		return nil, err
+	}
+	if err := u.checkPasswordPolicy(ctx, input.Password); err != nil {
+		return nil, err
+	}
 
 	hashedPassword, hashErr := securities.HashPassword(input.Password)
 	if hashErr != nil {
@@ -42,6 +83,7 @@ func (u *userServiceImpl) CreateUser(ctx context.Context, input *dto.CreateUserI
 		Password: input.Password,
 		Phone:    input.Phone,
 		Role:     input.Role,
+		Locale:   input.Locale,
 		IsActive: true,
 	}
 
@@ -49,28 +91,67 @@ func (u *userServiceImpl) CreateUser(ctx context.Context, input *dto.CreateUserI
 	if createUserErr != nil {
 		return nil, createUserErr
 	}
+
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+	}
+
 	return user, nil
 }
 
+func (u *userServiceImpl) RegisterUser(ctx context.Context, input *dto.RegisterUserInput) (*entities.User, error) {
+	return u.CreateUser(ctx, &dto.CreateUserInput{
+		Name:     input.Name,
+		Username: input.Username,
+		Email:    input.Email,
+		Password: input.Password,
+		Phone:    input.Phone,
+		Role:     entities.RoleUser,
+		Locale:   input.Locale,
+	})
+}
+
 func (u *userServiceImpl) LoginUser(ctx context.Context, input *dto.LoginUserInput) (*responses.LoginResponse, error) {
-	user, err := u.userRepo.FindByEmail(input.Email)
+	if err := u.checkLoginLockout(ctx, input.Email); err != nil {
+		u.recordLoginFailure(ctx, input.Email, nil, loginFailureLockedOut)
+		return nil, err
+	}
+
+	// The organization is only known once the user is found.
+	user, err := u.authenticator.Authenticate(utils.WithoutTenantScope(ctx), input.Email, input.Password)
+	if errors.Is(err, ErrInvalidCredentials) {
+		return nil, u.invalidCredentials(ctx, input.Email, nil)
+	}
 	if err != nil {
-		return nil, utils.NewAppError(utils.ErrNotFound, fmt.Errorf("user with email %s not found", input.Email))
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInternalServer, err, "Authentication service is unavailable")
+	}
+	if err := u.clearFailedLogins(ctx, input.Email); err != nil {
+		return nil, err
 	}
+
 	if !user.IsActive {
+		u.recordLoginFailure(ctx, input.Email, user, loginFailureInactive)
 		return nil, utils.NewAppError(utils.ErrInactiveUser, fmt.Errorf("user with email %s is inactive", input.Email))
 	}
+	if u.config.EmailVerification.BlocksLogin() && !user.IsEmailVerified() {
+		u.recordLoginFailure(ctx, input.Email, user, loginFailureEmailNotVerified)
+		return nil, utils.NewAppError(utils.ErrEmailNotVerified, fmt.Errorf("user with email %s has not verified their email", input.Email))
+	}
+	ctx = utils.WithTenant(ctx, user.TenantID)
 
-	errPass := securities.VerifyPassword(user.Password, input.Password)
-	if errPass != nil {
-		return nil, utils.NewAppError(utils.ErrInvalidCredentials, fmt.Errorf("invalid credentials"))
+	mfaRequired, err := u.mfaRequired(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled || mfaRequired {
+		return u.issueMFAChallenge(user)
 	}
 
-	accessToken, refreshToken, err := u.jwtManager.GenerateTokenPair(&securities.JWTPayload{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     user.Role,
-	})
+	return u.issueLoginTokens(ctx, user)
+}
+
+func (u *userServiceImpl) issueLoginTokens(ctx context.Context, user *entities.User) (*responses.LoginResponse, error) {
+	tokenPair, err := u.issueSessionTokens(ctx, user, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,45 +162,481 @@ func (u *userServiceImpl) LoginUser(ctx context.Context, input *dto.LoginUserInp
 	if updateErr != nil {
 		return nil, updateErr
 	}
+	u.recordLogin(ctx, user)
 
 	return &responses.LoginResponse{
-		Token: securities.TokenPair{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-		},
-		User: *user,
+		Token: tokenPair,
+		User:  *user,
 	}, nil
 }
 
+// RefreshToken rotates the presented refresh token. Presenting a token that
+// was already rotated means it leaked, so the whole session family is revoked.
 func (u *userServiceImpl) RefreshToken(ctx context.Context) (*responses.LoginResponse, error) {
-	userId := ctx.Value(utils.UserIDContextKey)
+	rawTokenID, _ := ctx.Value(utils.TokenIDContextKey).(string)
+	tokenID, err := uuid.Parse(rawTokenID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidToken, fmt.Errorf("refresh token has no valid token id"))
+	}
+
+	session, err := u.sessionRepo.FindByID(ctx, tokenID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidToken, err)
+	}
+	if session.RevokedAt != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidToken, fmt.Errorf("session %s revoked", session.FamilyID), "Session has been revoked")
+	}
+	if session.RotatedAt != nil {
+		return nil, u.revokeReusedSession(ctx, session)
+	}
+	if !session.IsUsable(time.Now()) {
+		return nil, utils.NewAppError(utils.ErrTokenExpired, fmt.Errorf("refresh token expired"))
+	}
 
-	user, err := u.userRepo.FindByID(userId.(uuid.UUID))
+	rotated, err := u.sessionRepo.MarkRotated(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, u.revokeReusedSession(ctx, session)
+	}
+
+	user, err := u.userRepo.FindByID(ctx, session.UserID)
 	if err != nil {
 		return nil, utils.NewAppError(utils.ErrNotFound, fmt.Errorf("user not found: %w", err))
 	}
+	if !user.IsActive {
+		if err := u.revokeFamily(ctx, session.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, utils.NewAppError(utils.ErrInactiveUser, fmt.Errorf("user with email %s is inactive", user.Email))
+	}
 
-	accessToken, refreshToken, err := u.jwtManager.GenerateTokenPair(&securities.JWTPayload{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     user.Role,
-	})
+	tokenPair, err := u.issueSessionTokens(ctx, user, session)
 	if err != nil {
 		return nil, err
 	}
 
 	return &responses.LoginResponse{
-		Token: securities.TokenPair{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-		},
-		User: *user,
+		Token: tokenPair,
+		User:  *user,
+	}, nil
+}
+
+func (u *userServiceImpl) Logout(ctx context.Context) error {
+	rawSessionID, _ := ctx.Value(utils.SessionIDContextKey).(string)
+	familyID, err := uuid.Parse(rawSessionID)
+	if err != nil {
+		return utils.NewAppError(utils.ErrInvalidToken, fmt.Errorf("access token is not bound to a session"))
+	}
+
+	return u.revokeFamily(ctx, familyID)
+}
+
+func (u *userServiceImpl) LogoutAll(ctx context.Context) error {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.revokeUserAccess(ctx, user.ID)
+}
+
+// revokeFamily ends a login session: the refresh token family can no longer
+// be rotated and access tokens issued for it are rejected immediately.
+func (u *userServiceImpl) revokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	if err := u.sessionRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	return u.revocations.RevokeSession(ctx, familyID.String())
+}
+
+// revokeSessions ends the user's current sessions one by one. Unlike
+// revokeUserAccess it leaves tokens issued later in the same second
+// working, for a login that must revoke the sessions before it.
+func (u *userServiceImpl) revokeSessions(ctx context.Context, userID uuid.UUID) error {
+	sessions, err := u.sessionRepo.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := u.revokeFamily(ctx, session.FamilyID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeUserAccess ends every session of the user and rejects all access
+// tokens issued to them so far.
+func (u *userServiceImpl) revokeUserAccess(ctx context.Context, userID uuid.UUID) error {
+	if err := u.sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return u.revocations.RevokeUser(ctx, userID)
+}
+
+// issueSessionTokens starts a new session when parent is nil, otherwise it
+// continues the parent's session family with a rotated refresh token.
+func (u *userServiceImpl) issueSessionTokens(ctx context.Context, user *entities.User, parent *entities.Session) (*securities.TokenPair, error) {
+	now := time.Now()
+	ipAddress, _ := ctx.Value(utils.IPAddressContextKey).(string)
+	userAgent, _ := ctx.Value(utils.UserAgentContextKey).(string)
+
+	session := &entities.Session{
+		ID:         uuid.New(),
+		FamilyID:   uuid.New(),
+		UserID:     user.ID,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		StartedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(u.jwtManager.GetRefreshExpiry()),
+	}
+	if parent != nil {
+		session.FamilyID = parent.FamilyID
+		session.ParentID = &parent.ID
+		session.StartedAt = parent.StartedAt
+	}
+	if err := u.sessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	payload := newJWTPayload(user)
+	payload.SessionID = session.FamilyID.String()
+
+	accessToken, refreshToken, err := u.jwtManager.GenerateTokenPair(payload, session.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	kind := "session"
+	if parent != nil {
+		kind = "refresh"
+	}
+	u.recordTokenIssued(ctx, user, kind, map[string]string{"session_id": session.FamilyID.String()})
+
+	return &securities.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (u *userServiceImpl) revokeReusedSession(ctx context.Context, session *entities.Session) error {
+	if err := u.revokeFamily(ctx, session.FamilyID); err != nil {
+		return err
+	}
+	log.Printf("Refresh token reuse detected for user %s, revoked session %s", session.UserID, session.FamilyID)
+
+	return utils.NewAppErrorWithMessage(utils.ErrInvalidToken, fmt.Errorf("refresh token reuse detected"), "Refresh token has already been used, session revoked")
+}
+
+func (u *userServiceImpl) VerifyEmail(ctx context.Context, input *dto.VerifyEmailInput) (*entities.User, error) {
+	token, err := u.verificationRepo.FindByTokenHash(utils.WithoutTenantScope(ctx), securities.HashToken(input.Token))
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidToken, err, "Verification token is invalid")
+	}
+	ctx = utils.WithTenant(ctx, token.TenantID)
+	if token.UsedAt != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidToken, fmt.Errorf("verification token already used"), "Verification token has already been used")
+	}
+	if token.IsExpired() {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrTokenExpired, fmt.Errorf("verification token expired"), "Verification token has expired")
+	}
+
+	user, err := u.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrNotFound, fmt.Errorf("user not found: %w", err))
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := u.userRepo.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.verificationRepo.MarkUsed(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *userServiceImpl) ResendVerification(ctx context.Context, input *dto.ResendVerificationInput) error {
+	user, err := u.userRepo.FindByEmail(utils.WithoutTenantScope(ctx), input.Email)
+	if err != nil || user.IsEmailVerified() {
+		// Respond the same way for unknown and already verified emails so the
+		// endpoint can't be used to enumerate accounts.
+		return nil
+	}
+
+	return u.sendVerificationEmail(utils.WithTenant(ctx, user.TenantID), user)
+}
+
+func (u *userServiceImpl) StepUp(ctx context.Context, input *dto.StepUpInput) (*responses.StepUpResponse, error) {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, utils.NewAppError(utils.ErrInactiveUser, fmt.Errorf("user with email %s is inactive", user.Email))
+	}
+
+	if input.Code != "" && !user.MFAEnabled {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("mfa not enabled"), "Two-factor authentication is not enabled")
+	}
+	if err := u.checkLoginLockout(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	if input.Code != "" {
+		if err := u.verifyTOTP(ctx, user, input.Code); err != nil {
+			u.recordLoginFailure(ctx, user.Email, user, loginFailureInvalidMFACode)
+			if recordErr := u.recordFailedLogin(ctx, user.Email); recordErr != nil {
+				return nil, recordErr
+			}
+			return nil, err
+		}
+	} else if err := u.reauthenticate(ctx, user, input.Password); err != nil {
+		return nil, err
+	}
+	if err := u.clearFailedLogins(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	expiry := u.config.StepUp.Expiry
+	token, err := u.jwtManager.GenerateStepUpToken(newJWTPayload(user), input.Scope, input.ResourceID, expiry)
+	if err != nil {
+		return nil, err
+	}
+	u.recordTokenIssued(ctx, user, "step_up", map[string]string{"scope": input.Scope, "resource_id": input.ResourceID})
+
+	return &responses.StepUpResponse{
+		Token:      token,
+		Scope:      input.Scope,
+		ResourceID: input.ResourceID,
+		ExpiresIn:  int64(expiry.Seconds()),
 	}, nil
 }
 
-func NewUserService(userRepo repositories.UserRepository, jwtManager *securities.JWTManager) UserService {
-	return &userServiceImpl{
-		userRepo:   userRepo,
-		jwtManager: jwtManager,
+func (u *userServiceImpl) sendVerificationEmail(ctx context.Context, user *entities.User) error {
+	if err := u.verificationRepo.DeleteUnusedByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	rawToken, err := securities.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	token := &entities.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: securities.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(u.config.EmailVerification.LinkExpiry),
+	}
+	if err := u.verificationRepo.CreateToken(ctx, token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s?token=%s", u.config.EmailVerification.VerifyURL, url.QueryEscape(rawToken))
+	subject, body, err := u.renderEmail(ctx, settingEntities.EmailTemplateVerification, settingEntities.EmailData{
+		Name:   user.Name,
+		Link:   link,
+		Expiry: u.config.EmailVerification.LinkExpiry.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.mailer.Send(ctx, &mailer.Message{
+		From:     u.config.Mail.From,
+		To:       []string{user.Email},
+		Subject:  subject,
+		TextBody: body,
+	})
+}
+
+// renderEmail fills the organization's version of the template, adding its
+// brand name to data.
+func (u *userServiceImpl) renderEmail(ctx context.Context, name string, data settingEntities.EmailData) (string, string, error) {
+	settings, err := u.settings.ForTenant(ctx)
+	if err != nil {
+		return "", "", err
 	}
+	tmpl, err := settings.Template(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	data.Brand = settings.Branding.DisplayName
+	return tmpl.Render(data)
+}
+
+// checkPasswordPolicy applies the password policy of the organization in
+// ctx.
+func (u *userServiceImpl) checkPasswordPolicy(ctx context.Context, password string) error {
+	settings, err := u.settings.ForTenant(ctx)
+	if err != nil {
+		return err
+	}
+	if message := settings.PasswordPolicy.Check(password); message != "" {
+		return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("password violates policy"), message)
+	}
+	return nil
+}
+
+func (u *userServiceImpl) mfaRequired(ctx context.Context, role entities.RoleEnum) (bool, error) {
+	settings, err := u.settings.ForTenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	return settings.MFA.RequiredForRole(string(role)), nil
+}
+
+// invalidCredentials records the failed attempt and returns the same error
+// for unknown emails and wrong passwords. user is nil when the caller
+// hasn't looked the account up.
+func (u *userServiceImpl) invalidCredentials(ctx context.Context, email string, user *entities.User) error {
+	u.recordLoginFailure(ctx, email, user, loginFailureInvalidCredentials)
+	if err := u.recordFailedLogin(ctx, email); err != nil {
+		return err
+	}
+	return utils.NewAppError(utils.ErrInvalidCredentials, fmt.Errorf("invalid credentials"))
+}
+
+// reauthenticate confirms the signed-in user's password through the
+// configured authenticator. A wrong password counts towards the same
+// lockout as a failed login.
+func (u *userServiceImpl) reauthenticate(ctx context.Context, user *entities.User, password string) error {
+	authenticated, err := u.authenticator.Authenticate(ctx, user.Email, password)
+	if err != nil && !errors.Is(err, ErrInvalidCredentials) {
+		return utils.NewAppErrorWithMessage(utils.ErrInternalServer, err, "Authentication service is unavailable")
+	}
+	if err != nil || authenticated.ID != user.ID {
+		return u.invalidCredentials(ctx, user.Email, user)
+	}
+
+	return nil
+}
+
+func (u *userServiceImpl) ensureRoleExists(ctx context.Context, role entities.RoleEnum) error {
+	existing, err := u.roleRepo.FindByName(ctx, string(role))
+	if err != nil {
+		return utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if existing == nil {
+		return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("role %s does not exist", role), "Role does not exist")
+	}
+	return nil
+}
+
+// localPasswordsOnly rejects password changes when passwords live in the
+// directory, where a local hash would never be checked.
+func (u *userServiceImpl) localPasswordsOnly() error {
+	if u.config.UsesLDAPPasswords() {
+		return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("passwords are managed by LDAP"), "Passwords are managed by the directory")
+	}
+	return nil
+}
+
+func (u *userServiceImpl) JWKS() *securities.JWKS {
+	return u.jwtManager.JWKS()
+}
+
+func (u *userServiceImpl) currentUser(ctx context.Context) (*entities.User, error) {
+	userID, ok := ctx.Value(utils.UserIDContextKey).(uuid.UUID)
+	if !ok {
+		return nil, utils.NewAppError(utils.ErrUnauthorized, fmt.Errorf("user id missing from context"))
+	}
+
+	user, err := u.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrNotFound, fmt.Errorf("user not found: %w", err))
+	}
+
+	return user, nil
+}
+
+// registrationContext returns ctx scoped to the organization new users join:
+// the caller's own, or the default organization for self-registration.
+func (u *userServiceImpl) registrationContext(ctx context.Context) (context.Context, error) {
+	if tenantID, ok := ctx.Value(utils.TenantIDContextKey).(uuid.UUID); ok && tenantID != uuid.Nil {
+		return ctx, nil
+	}
+
+	return u.defaultOrganizationContext(ctx)
+}
+
+// defaultOrganizationContext returns ctx scoped to the default
+// organization, whatever organization ctx carried before.
+func (u *userServiceImpl) defaultOrganizationContext(ctx context.Context) (context.Context, error) {
+	organization, err := u.organizationRepo.FindBySlug(ctx, u.config.Organization.DefaultSlug)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+	if organization == nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, fmt.Errorf("default organization %s does not exist", u.config.Organization.DefaultSlug))
+	}
+
+	return utils.WithTenant(ctx, organization.ID), nil
+}
+
+func newJWTPayload(user *entities.User) *securities.JWTPayload {
+	return &securities.JWTPayload{
+		UserID:        user.ID,
+		TenantID:      user.TenantID,
+		Username:      user.Username,
+		Role:          user.Role,
+		EmailVerified: user.IsEmailVerified(),
+	}
+}
+
+func NewUserService(
+	userRepo repositories.UserRepository,
+	verificationRepo repositories.EmailVerificationRepository,
+	mfaRepo repositories.MFARepository,
+	lockoutRepo repositories.LoginLockoutRepository,
+	sessionRepo repositories.SessionRepository,
+	apiKeyRepo repositories.APIKeyRepository,
+	identityRepo repositories.ExternalIdentityRepository,
+	roleRepo roleRepositories.RoleRepository,
+	organizationRepo organizationRepositories.OrganizationRepository,
+	settings settingServices.SettingService,
+	jwtManager *securities.JWTManager,
+	revocations *securities.RevocationList,
+	oidcProvider *oidc.LazyProvider,
+	directory *ldap.Directory,
+	mailer mailer.Mailer,
+	audit auditServices.AuditService,
+	cfg *config.Config,
+) UserService {
+	service := &userServiceImpl{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		mfaRepo:          mfaRepo,
+		lockoutRepo:      lockoutRepo,
+		sessionRepo:      sessionRepo,
+		apiKeyRepo:       apiKeyRepo,
+		identityRepo:     identityRepo,
+		roleRepo:         roleRepo,
+		organizationRepo: organizationRepo,
+		settings:         settings,
+		jwtManager:       jwtManager,
+		revocations:      revocations,
+		oidcProvider:     oidcProvider,
+		mailer:           mailer,
+		audit:            audit,
+		config:           cfg,
+	}
+
+	service.authenticator = NewPasswordAuthenticator(userRepo)
+	if directory != nil {
+		service.authenticator = newLDAPAuthenticator(directory, service)
+	}
+
+	return service
 }
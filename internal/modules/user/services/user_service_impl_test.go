@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testcase/config"
+	auditEntities "testcase/internal/modules/audit/entities"
+	auditServices "testcase/internal/modules/audit/services"
+	settingEntities "testcase/internal/modules/setting/entities"
+	settingServices "testcase/internal/modules/setting/services"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/modules/user/repositories"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryUserRepository serves the users it was given by ID and email.
+type memoryUserRepository struct {
+	repositories.UserRepository
+
+	mu    sync.Mutex
+	users map[uuid.UUID]*entities.User
+}
+
+func newMemoryUserRepository(users ...*entities.User) *memoryUserRepository {
+	repo := &memoryUserRepository{users: map[uuid.UUID]*entities.User{}}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *memoryUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user with ID %s not found", id)
+	}
+	return user, nil
+}
+
+func (r *memoryUserRepository) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if normalizeEmail(user.Email) == normalizeEmail(email) {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("user with email %s not found", email)
+}
+
+func (r *memoryUserRepository) FindWithMFASecret(ctx context.Context) ([]entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var users []entities.User
+	for _, user := range r.users {
+		if user.MFASecret != "" {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) UpdateUser(ctx context.Context, user *entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
+
+type rejectingAuthenticator struct{}
+
+func (rejectingAuthenticator) Authenticate(ctx context.Context, email, password string) (*entities.User, error) {
+	return nil, ErrInvalidCredentials
+}
+
+type discardAuditService struct {
+	auditServices.AuditService
+}
+
+func (discardAuditService) Record(ctx context.Context, entry *auditEntities.AuditLog) error {
+	return nil
+}
+
+// fixedSettingService returns the same settings for every organization.
+type fixedSettingService struct {
+	settingServices.SettingService
+	settings settingEntities.Settings
+}
+
+func (s *fixedSettingService) ForTenant(ctx context.Context) (*settingEntities.Settings, error) {
+	settings := s.settings
+	return &settings, nil
+}
+
+func newTestJWTManager() *securities.JWTManager {
+	return securities.NewJWTManager("access-secret", "refresh-secret", time.Minute, time.Hour, nil, securities.JWTOptions{})
+}
+
+// newSessionService wires the dependencies a login needs to finish:
+// sessions, tokens, the user's last login and the audit log.
+func newSessionService(users *memoryUserRepository, sessions *memorySessionRepository) *userServiceImpl {
+	return &userServiceImpl{
+		userRepo:    users,
+		sessionRepo: sessions,
+		settings:    &fixedSettingService{},
+		jwtManager:  newTestJWTManager(),
+		revocations: securities.NewRevocationList(securities.NewMemoryRevocationStore(), time.Minute, time.Second),
+		audit:       discardAuditService{},
+		config: &config.Config{
+			MFA: config.MFA{ChallengeExpiry: 5 * time.Minute},
+		},
+	}
+}
+
+// memorySessionRepository keeps sessions in memory. MarkRotated succeeds
+// once per session, like the conditional update it stands in for.
+type memorySessionRepository struct {
+	repositories.SessionRepository
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*entities.Session
+}
+
+func newMemorySessionRepository() *memorySessionRepository {
+	return &memorySessionRepository{sessions: map[uuid.UUID]*entities.Session{}}
+}
+
+func (r *memorySessionRepository) CreateSession(ctx context.Context, session *entities.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+func (r *memorySessionRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("session %s not found", id)
+	}
+	found := *session
+	return &found, nil
+}
+
+func (r *memorySessionRepository) MarkRotated(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok || session.RotatedAt != nil || session.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	session.RotatedAt = &now
+	return true, nil
+}
+
+func (r *memorySessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, session := range r.sessions {
+		if session.FamilyID == familyID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// family returns the sessions of a session family.
+func (r *memorySessionRepository) family(familyID uuid.UUID) []entities.Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sessions []entities.Session
+	for _, session := range r.sessions {
+		if session.FamilyID == familyID {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions
+}
+
+func newActiveUser(role entities.RoleEnum) *entities.User {
+	verified := time.Now()
+	return &entities.User{
+		ID:              uuid.New(),
+		TenantID:        uuid.New(),
+		Email:           "user@example.com",
+		Role:            role,
+		IsActive:        true,
+		EmailVerifiedAt: &verified,
+	}
+}
+
+// refreshContext carries the refresh token's ID the way the refresh
+// middleware does.
+func refreshContext(t *testing.T, service *userServiceImpl, refreshToken string) context.Context {
+	t.Helper()
+
+	claims, err := service.jwtManager.ValidateAndExtract(refreshToken, securities.TokenTypeRefresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return context.WithValue(context.Background(), utils.TokenIDContextKey, claims.ID)
+}
+
+func TestRefreshTokenRotatesWithinFamily(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	sessions := newMemorySessionRepository()
+	service := newSessionService(newMemoryUserRepository(user), sessions)
+
+	login, err := service.issueLoginTokens(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := refreshContext(t, service, login.Token.RefreshToken)
+	firstID, _ := uuid.Parse(first.Value(utils.TokenIDContextKey).(string))
+
+	refreshed, err := service.RefreshToken(first)
+	if err != nil {
+		t.Fatalf("expected the refresh token to rotate, got %v", err)
+	}
+	second := refreshContext(t, service, refreshed.Token.RefreshToken)
+	secondID, _ := uuid.Parse(second.Value(utils.TokenIDContextKey).(string))
+
+	parent, _ := sessions.FindByID(context.Background(), firstID)
+	child, _ := sessions.FindByID(context.Background(), secondID)
+	if parent.RotatedAt == nil {
+		t.Fatal("expected the presented session to be marked rotated")
+	}
+	if child.FamilyID != parent.FamilyID || child.ParentID == nil || *child.ParentID != parent.ID {
+		t.Fatalf("expected the new session to continue the family, got %+v", child)
+	}
+
+	if _, err := service.RefreshToken(second); err != nil {
+		t.Fatalf("expected the rotated refresh token to work, got %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	sessions := newMemorySessionRepository()
+	service := newSessionService(newMemoryUserRepository(user), sessions)
+	ctx := context.Background()
+
+	login, err := service.issueLoginTokens(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stolen := refreshContext(t, service, login.Token.RefreshToken)
+	refreshed, err := service.RefreshToken(stolen)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var appErr *utils.AppError
+	if _, err := service.RefreshToken(stolen); !errors.As(err, &appErr) || appErr.ErrorCode != utils.ErrInvalidToken {
+		t.Fatalf("expected the reused refresh token to be rejected, got %v", err)
+	}
+
+	access, err := service.jwtManager.ValidateAndExtract(refreshed.Token.AccessToken, securities.TokenTypeAccess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	familyID, _ := uuid.Parse(access.SessionID)
+	for _, session := range sessions.family(familyID) {
+		if session.RevokedAt == nil {
+			t.Fatalf("expected every session in the family to be revoked, got %+v", session)
+		}
+	}
+	if revoked, err := service.revocations.IsRevoked(ctx, access); err != nil || !revoked {
+		t.Fatalf("expected access tokens of the family to be revoked, got %v (%v)", revoked, err)
+	}
+	if _, err := service.RefreshToken(refreshContext(t, service, refreshed.Token.RefreshToken)); err == nil {
+		t.Fatal("expected the latest refresh token of the family to stop working")
+	}
+}
+
+func TestRefreshTokenRotatesOnceUnderConcurrency(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	sessions := newMemorySessionRepository()
+	service := newSessionService(newMemoryUserRepository(user), sessions)
+
+	login, err := service.issueLoginTokens(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := refreshContext(t, service, login.Token.RefreshToken)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.RefreshToken(ctx); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one refresh to succeed, got %d", succeeded)
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testcase/internal/modules/user/responses"
+	"testcase/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+func (u *userServiceImpl) ListMySessions(ctx context.Context) ([]responses.SessionResponse, error) {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.listSessions(ctx, user.ID)
+}
+
+func (u *userServiceImpl) RevokeMySession(ctx context.Context, sessionID string) error {
+	user, err := u.currentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.revokeSession(ctx, user.ID, sessionID)
+}
+
+func (u *userServiceImpl) ListUserSessions(ctx context.Context, userID string) ([]responses.SessionResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid user ID: %w", err))
+	}
+
+	user, err := u.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrUserNotFound, err)
+	}
+
+	return u.listSessions(ctx, user.ID)
+}
+
+func (u *userServiceImpl) RevokeUserSession(ctx context.Context, userID, sessionID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid user ID: %w", err))
+	}
+
+	return u.revokeSession(ctx, id, sessionID)
+}
+
+func (u *userServiceImpl) listSessions(ctx context.Context, userID uuid.UUID) ([]responses.SessionResponse, error) {
+	sessions, err := u.sessionRepo.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	currentSessionID, _ := ctx.Value(utils.SessionIDContextKey).(string)
+	result := make([]responses.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, responses.SessionResponse{
+			ID:         session.FamilyID,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			StartedAt:  session.StartedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.FamilyID.String() == currentSessionID,
+		})
+	}
+
+	return result, nil
+}
+
+func (u *userServiceImpl) revokeSession(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	familyID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid session ID: %w", err))
+	}
+
+	session, err := u.sessionRepo.FindActiveByFamily(ctx, familyID)
+	if err != nil || session.UserID != userID {
+		return utils.NewAppError(utils.ErrNotFound, fmt.Errorf("session %s not found", sessionID))
+	}
+
+	return u.revokeFamily(ctx, familyID)
+}
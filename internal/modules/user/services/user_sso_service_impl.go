@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"testcase/internal/modules/user/dto"
+	"testcase/internal/modules/user/entities"
+	"testcase/internal/modules/user/responses"
+	"testcase/internal/utils"
+	"testcase/package/oidc"
+	"testcase/package/securities"
+	"time"
+)
+
+func (u *userServiceImpl) StartSSOLogin(ctx context.Context) (*responses.SSOAuthorizationResponse, error) {
+	provider, err := u.ssoProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := securities.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := securities.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := u.config.OIDC.StateExpiry
+	loginState := &entities.OIDCLoginState{
+		StateHash:    securities.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(expiry),
+	}
+	if err := u.identityRepo.CreateLoginState(ctx, loginState); err != nil {
+		return nil, err
+	}
+
+	return &responses.SSOAuthorizationResponse{
+		AuthorizationURL: provider.AuthCodeURL(state, nonce, oidc.CodeChallengeS256(verifier)),
+		State:            state,
+		ExpiresIn:        int64(expiry.Seconds()),
+	}, nil
+}
+
+// CompleteSSOLogin redeems the authorization code, verifies the ID token
+// and signs the user in, provisioning the account on first login. Local MFA
+// applies as it does to password logins unless the ID token's amr claim
+// names a method configured as trusted.
+func (u *userServiceImpl) CompleteSSOLogin(ctx context.Context, input *dto.SSOCallbackInput) (*responses.LoginResponse, error) {
+	provider, err := u.ssoProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(input.BrowserState), []byte(input.State)) != 1 {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidToken, fmt.Errorf("login state not bound to this browser"), "SSO login was started in another browser, please start again")
+	}
+
+	loginState, err := u.identityRepo.ConsumeLoginState(ctx, securities.HashToken(input.State))
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidToken, err, "SSO login state is invalid or has already been used")
+	}
+	if time.Now().After(loginState.ExpiresAt) {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrTokenExpired, fmt.Errorf("login state expired"), "SSO login has expired, please start again")
+	}
+
+	tokens, err := provider.Exchange(ctx, input.Code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidCredentials, err, "Identity provider rejected the authorization code")
+	}
+
+	idToken, err := provider.VerifyIDToken(ctx, tokens.IDToken, loginState.Nonce)
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInvalidToken, err, "Identity provider returned an invalid ID token")
+	}
+
+	user, err := u.resolveExternalUser(ctx, &externalAccount{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         idToken.Email,
+		EmailVerified: idToken.EmailVerified,
+		Name:          idToken.Name,
+		Username:      idToken.PreferredUsername,
+		Groups:        idToken.StringsClaim(u.config.OIDC.GroupsClaim),
+	}, u.config.OIDC.RoleMapping, u.config.OIDC.DefaultRole)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, utils.NewAppError(utils.ErrInactiveUser, fmt.Errorf("user with email %s is inactive", user.Email))
+	}
+	if u.config.EmailVerification.BlocksLogin() && !user.IsEmailVerified() {
+		return nil, utils.NewAppError(utils.ErrEmailNotVerified, fmt.Errorf("user with email %s has not verified their email", user.Email))
+	}
+
+	return u.finishSSOLogin(utils.WithTenant(ctx, user.TenantID), user, idToken.StringsClaim("amr"))
+}
+
+// finishSSOLogin issues the MFA challenge a password login would get,
+// unless amr shows the provider already did MFA.
+func (u *userServiceImpl) finishSSOLogin(ctx context.Context, user *entities.User, amr []string) (*responses.LoginResponse, error) {
+	mfaRequired, err := u.mfaRequired(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if (user.MFAEnabled || mfaRequired) && !u.config.OIDC.TrustsMFA(amr) {
+		return u.issueMFAChallenge(user)
+	}
+
+	return u.issueLoginTokens(ctx, user)
+}
+
+func (u *userServiceImpl) ssoProvider(ctx context.Context) (*oidc.Provider, error) {
+	if u.oidcProvider == nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrNotFound, fmt.Errorf("oidc is not configured"), "Single sign-on is not enabled")
+	}
+
+	provider, err := u.oidcProvider.Get(ctx)
+	if err != nil {
+		return nil, utils.NewAppErrorWithMessage(utils.ErrInternalServer, err, "Identity provider is unavailable")
+	}
+
+	return provider, nil
+}
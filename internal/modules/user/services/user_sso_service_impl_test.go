@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	settingEntities "testcase/internal/modules/setting/entities"
+	"testcase/internal/modules/user/entities"
+	"testing"
+)
+
+func TestFinishSSOLoginChallengesMFAUsers(t *testing.T) {
+	enrolled := newActiveUser(entities.RoleUser)
+	enrolled.MFAEnabled = true
+	required := newActiveUser(entities.RoleAdmin)
+
+	service := newSessionService(newMemoryUserRepository(enrolled, required), newMemorySessionRepository())
+	service.settings = &fixedSettingService{settings: settingEntities.Settings{
+		MFA: settingEntities.MFASettings{RequiredRoles: []string{string(entities.RoleAdmin)}},
+	}}
+	service.config.OIDC.TrustedAMR = []string{"mfa"}
+
+	login, err := service.finishSSOLogin(context.Background(), enrolled, []string{"pwd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.Token != nil || login.MFA == nil || login.MFA.EnrollmentRequired {
+		t.Fatalf("expected an MFA challenge for an enrolled user, got %+v", login)
+	}
+
+	login, err = service.finishSSOLogin(context.Background(), required, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.Token != nil || login.MFA == nil || !login.MFA.EnrollmentRequired {
+		t.Fatalf("expected an enrollment challenge for a role that requires MFA, got %+v", login)
+	}
+}
+
+func TestFinishSSOLoginTrustsConfiguredAMR(t *testing.T) {
+	user := newActiveUser(entities.RoleUser)
+	user.MFAEnabled = true
+	service := newSessionService(newMemoryUserRepository(user), newMemorySessionRepository())
+
+	login, err := service.finishSSOLogin(context.Background(), user, []string{"pwd", "mfa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.MFA == nil {
+		t.Fatal("expected an MFA challenge while no amr value is trusted")
+	}
+
+	service.config.OIDC.TrustedAMR = []string{"MFA"}
+	login, err = service.finishSSOLogin(context.Background(), user, []string{"pwd", "mfa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.MFA != nil || login.Token == nil {
+		t.Fatalf("expected tokens when the provider did trusted MFA, got %+v", login)
+	}
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"testcase/internal/middlewares"
+	roleEntities "testcase/internal/modules/role/entities"
 	"testcase/internal/modules/user/handlers"
 
 	"github.com/gin-gonic/gin"
@@ -11,8 +12,65 @@ func RegisterUserRoutes(rg *gin.RouterGroup, h *handlers.UserHandler, authMware
 
 	userRoutes := rg.Group("/users")
 	{
-		userRoutes.POST("/", h.CreateUser)
+		userRoutes.POST("/", h.RegisterUser)
+		userRoutes.POST("/members", authMware.Auth(), authMware.RequirePermission(roleEntities.PermissionUserManage), h.CreateUser)
 		userRoutes.POST("/login", h.LoginUser)
+		userRoutes.POST("/sso/authorize", h.StartSSOLogin)
+		userRoutes.POST("/sso/callback", h.CompleteSSOLogin)
 		userRoutes.POST("/refresh-token", authMware.AuthRefresh(), h.RefreshToken)
+		userRoutes.POST("/logout", authMware.Auth(), h.Logout)
+		userRoutes.POST("/logout-all", authMware.Auth(), h.LogoutAll)
+		userRoutes.POST("/verify-email", h.VerifyEmail)
+		userRoutes.POST("/verify-email/resend", h.ResendVerification)
+
+		mfaLoginRoutes := userRoutes.Group("/login/mfa")
+		mfaLoginRoutes.Use(authMware.AuthMFA())
+		{
+			mfaLoginRoutes.POST("", h.VerifyMFALogin)
+			mfaLoginRoutes.POST("/enroll", h.EnrollMFA)
+			mfaLoginRoutes.POST("/activate", h.CompleteMFAEnrollment)
+		}
+
+		userRoutes.POST("/me/step-up", authMware.Auth(), h.StepUp)
+		userRoutes.POST("/me/password", authMware.Auth(), h.ChangePassword)
+		userRoutes.PATCH("/:id", authMware.Auth(), authMware.RequirePermission(roleEntities.PermissionUserManage), h.UpdateUser)
+
+		sessionRoutes := userRoutes.Group("/me/sessions")
+		sessionRoutes.Use(authMware.Auth())
+		{
+			sessionRoutes.GET("", h.ListMySessions)
+			sessionRoutes.DELETE("/:id", h.RevokeMySession)
+		}
+
+		adminSessionRoutes := userRoutes.Group("/:id/sessions")
+		adminSessionRoutes.Use(authMware.Auth(), authMware.RequirePermission(roleEntities.PermissionUserManage))
+		{
+			adminSessionRoutes.GET("", h.ListUserSessions)
+			adminSessionRoutes.DELETE("/:sessionId", h.RevokeUserSession)
+		}
+
+		mfaRoutes := userRoutes.Group("/me/mfa")
+		mfaRoutes.Use(authMware.Auth())
+		{
+			mfaRoutes.POST("/enroll", h.EnrollMFA)
+			mfaRoutes.POST("/activate", h.ActivateMFA)
+			mfaRoutes.POST("/disable", h.DisableMFA)
+			mfaRoutes.POST("/recovery-codes", h.RegenerateRecoveryCodes)
+		}
+
+		lockoutRoutes := userRoutes.Group("/lockouts")
+		lockoutRoutes.Use(authMware.Auth(), authMware.RequirePlatformOrganization(), authMware.RequirePermission(roleEntities.PermissionUserManage))
+		{
+			lockoutRoutes.GET("", h.ListLockouts)
+			lockoutRoutes.DELETE("/:id", h.ClearLockout)
+		}
+
+		apiKeyRoutes := userRoutes.Group("/api-keys")
+		apiKeyRoutes.Use(authMware.Auth(), authMware.RequirePermission(roleEntities.PermissionAPIKeyManage))
+		{
+			apiKeyRoutes.POST("", h.CreateAPIKey)
+			apiKeyRoutes.GET("", h.ListAPIKeys)
+			apiKeyRoutes.DELETE("/:id", h.RevokeAPIKey)
+		}
 	}
 }
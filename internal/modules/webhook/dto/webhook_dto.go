@@ -0,0 +1,13 @@
+package dto
+
+// WebhookInput creates a webhook or replaces one on update. Events takes
+// event types such as "document.approved", "document.*" or "*". A secret
+// is generated when none is given on create; on update an empty secret
+// keeps the current one.
+type WebhookInput struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	Description string   `json:"description" binding:"max=255"`
+	Events      []string `json:"events" binding:"required,min=1,dive,required,max=100"`
+	Secret      string   `json:"secret,omitempty" binding:"omitempty,min=16,max=255"`
+	Active      *bool    `json:"active,omitempty"`
+}
@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PingEvent is sent on request to check a webhook; it is delivered
+// regardless of the webhook's event filter.
+const PingEvent = "webhook.ping"
+
+// AllEvents subscribes a webhook to every event type.
+const AllEvents = "*"
+
+// Webhook is an organization's subscription to domain events. Events holds
+// exact event types, "*" or a "<prefix>.*" pattern. The secret is stored
+// encrypted because it is needed in full to sign deliveries.
+type Webhook struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID    uuid.UUID  `gorm:"type:uuid;index" json:"tenant_id"`
+	URL         string     `gorm:"type:text;not null" json:"url"`
+	Description string     `gorm:"type:varchar(255)" json:"description"`
+	Events      []string   `gorm:"type:jsonb;serializer:json;not null" json:"events"`
+	Secret      string     `gorm:"type:text;not null" json:"-"`
+	Active      bool       `gorm:"not null" json:"active"`
+	CreatedBy   *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (w *Webhook) TableName() string {
+	return "webhooks"
+}
+
+func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// Subscribes reports whether the webhook's filter matches eventType.
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, pattern := range w.Events {
+		if MatchesEvent(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func MatchesEvent(pattern, eventType string) bool {
+	if pattern == AllEvents || pattern == eventType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(pattern, ".*")
+	return ok && strings.HasPrefix(eventType, prefix+".")
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead marks a delivery that ran out of attempts. It is only
+	// sent again through a manual redeliver.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event on its way to one webhook. Payload is the
+// exact body sent on every attempt, so receivers can deduplicate on the
+// event id it carries.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID       uuid.UUID       `gorm:"type:uuid;index" json:"tenant_id"`
+	WebhookID      uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_event" json:"webhook_id"`
+	EventID        uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_event" json:"event_id"`
+	EventType      string          `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload        json.RawMessage `gorm:"type:jsonb;serializer:json;not null" json:"payload"`
+	Status         DeliveryStatus  `gorm:"type:varchar(20);not null;index" json:"status"`
+	AttemptCount   int             `gorm:"not null;default:0" json:"attempt_count"`
+	NextAttemptAt  time.Time       `gorm:"index" json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	Webhook  *Webhook                 `json:"-"`
+	Attempts []WebhookDeliveryAttempt `gorm:"foreignKey:DeliveryID" json:"attempts,omitempty"`
+}
+
+func (d *WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// WebhookDeliveryAttempt records one HTTP request made for a delivery.
+// Error is set when no response was received.
+type WebhookDeliveryAttempt struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	TenantID     uuid.UUID `gorm:"type:uuid;index" json:"tenant_id"`
+	DeliveryID   uuid.UUID `gorm:"type:uuid;not null;index" json:"delivery_id"`
+	Attempt      int       `gorm:"not null" json:"attempt"`
+	Manual       bool      `gorm:"not null;default:false" json:"manual"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	ResponseBody string    `gorm:"type:text" json:"response_body,omitempty"`
+	Error        string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (a *WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
+
+func (a *WebhookDeliveryAttempt) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// Succeeded reports whether the receiver answered with a 2xx status.
+func (a *WebhookDeliveryAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// EventEnvelope is the JSON body of every delivery.
+type EventEnvelope struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	TenantID  uuid.UUID       `json:"tenant_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
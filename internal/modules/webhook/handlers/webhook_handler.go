@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"testcase/internal/helpers"
+	"testcase/internal/middlewares"
+	"testcase/internal/modules/webhook/dto"
+	"testcase/internal/modules/webhook/services"
+	"testcase/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context())
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, webhooks, "Webhooks retrieved successfully", http.StatusOK)
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	webhook, err := h.webhookService.GetWebhook(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, webhook, "Webhook retrieved successfully", http.StatusOK)
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var input dto.WebhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, webhook, "Webhook created successfully", http.StatusCreated)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var input dto.WebhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middlewares.ValidationErrorResponse(c, err)
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), c.Param("id"), &input)
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, webhook, "Webhook updated successfully", http.StatusOK)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), c.Param("id")); err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, nil, "Webhook deleted successfully", http.StatusOK)
+}
+
+func (h *WebhookHandler) PingWebhook(c *gin.Context) {
+	delivery, err := h.webhookService.PingWebhook(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, delivery, "Webhook ping sent", http.StatusOK)
+}
+
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	params := helpers.ParsePaginationParams(c)
+
+	deliveries, total, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Param("id"), params)
+	if err != nil {
+		panic(err)
+	}
+
+	list := helpers.CreatePaginationResult(deliveries, total, params)
+
+	utils.SuccessResponse(c, list, "Webhook deliveries retrieved successfully", http.StatusOK)
+}
+
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, delivery, "Webhook delivery retrieved successfully", http.StatusOK)
+}
+
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	delivery, err := h.webhookService.RedeliverDelivery(c.Request.Context(), c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		panic(err)
+	}
+
+	utils.SuccessResponse(c, delivery, "Webhook delivery redelivered", http.StatusOK)
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	"testcase/internal/modules/webhook/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDeliveryRepository interface {
+	// EnqueueDeliveries stores new deliveries, skipping any event that was
+	// already queued for the same webhook.
+	EnqueueDeliveries(ctx context.Context, deliveries []entities.WebhookDelivery) error
+	FindDelivery(ctx context.Context, webhookID, id uuid.UUID) (*entities.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, params *helpers.PaginationParams) ([]entities.WebhookDelivery, int64, error)
+	// ClaimDue returns up to limit due deliveries of active webhooks, with
+	// their webhook loaded, and pushes their next attempt back by lease so
+	// other workers leave them alone while they are being sent.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error)
+	// RecordAttempt saves the delivery state and the attempt log entry
+	// together.
+	RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery, attempt *entities.WebhookDeliveryAttempt) error
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testcase/internal/helpers"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/webhook/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookDeliveryRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewWebhookDeliveryRepository(db *database.Database) WebhookDeliveryRepository {
+	return &webhookDeliveryRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *webhookDeliveryRepositoryImpl) EnqueueDeliveries(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}}, DoNothing: true}).
+		Create(&deliveries).Error
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookDeliveryRepositoryImpl) FindDelivery(ctx context.Context, webhookID, id uuid.UUID) (*entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+
+	err := r.db.WithContext(ctx).
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB { return db.Order("attempt asc") }).
+		Where("id = ? AND webhook_id = ?", id, webhookID).
+		First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("webhook delivery with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to find webhook delivery by ID: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+func (r *webhookDeliveryRepositoryImpl) ListDeliveries(ctx context.Context, webhookID uuid.UUID, params *helpers.PaginationParams) ([]entities.WebhookDelivery, int64, error) {
+	var deliveries []entities.WebhookDelivery
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+
+	if params.Filter != "" {
+		query = query.Where("status = ?", params.Filter)
+	}
+
+	if params.Search != "" {
+		query = query.Where("event_type ILIKE ?", fmt.Sprintf("%%%s%%", params.Search))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	err := query.
+		Offset(params.GetOffset()).
+		Limit(params.Limit).
+		Order("created_at desc").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *webhookDeliveryRepositoryImpl) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error) {
+	var deliveries []entities.WebhookDelivery
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		active := tx.Model(&entities.Webhook{}).Select("id").Where("active = ?", true)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ? AND webhook_id IN (?)", entities.DeliveryPending, now, active).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(deliveries))
+		webhookIDs := make([]uuid.UUID, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+			webhookIDs = append(webhookIDs, delivery.WebhookID)
+		}
+
+		err = tx.Model(&entities.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return err
+		}
+
+		var webhooks []entities.Webhook
+		if err := tx.Where("id IN ?", webhookIDs).Find(&webhooks).Error; err != nil {
+			return err
+		}
+		byID := make(map[uuid.UUID]*entities.Webhook, len(webhooks))
+		for i := range webhooks {
+			byID[webhooks[i].ID] = &webhooks[i]
+		}
+		for i := range deliveries {
+			deliveries[i].Webhook = byID[deliveries[i].WebhookID]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepositoryImpl) RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery, attempt *entities.WebhookDeliveryAttempt) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(delivery).
+			Select("status", "attempt_count", "next_attempt_at", "last_status_code", "last_error", "delivered_at", "updated_at").
+			Updates(delivery).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(attempt).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"testcase/internal/modules/webhook/entities"
+
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *entities.Webhook) error
+	UpdateWebhook(ctx context.Context, webhook *entities.Webhook) error
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]entities.Webhook, error)
+	ListActive(ctx context.Context) ([]entities.Webhook, error)
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testcase/internal/infrastructures/database"
+	"testcase/internal/modules/webhook/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type webhookRepositoryImpl struct {
+	db *database.Database
+}
+
+func NewWebhookRepository(db *database.Database) WebhookRepository {
+	return &webhookRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *webhookRepositoryImpl) CreateWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	if err := r.db.WithContext(ctx).Create(webhook).Error; err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepositoryImpl) UpdateWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	if err := r.db.WithContext(ctx).Save(webhook).Error; err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhook removes the webhook together with its delivery log.
+func (r *webhookRepositoryImpl) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deliveries := tx.Model(&entities.WebhookDelivery{}).Select("id").Where("webhook_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&entities.WebhookDeliveryAttempt{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook delivery attempts: %w", err)
+		}
+		if err := tx.Where("webhook_id = ?", id).Delete(&entities.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		if err := tx.Where("id = ?", id).Delete(&entities.Webhook{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *webhookRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*entities.Webhook, error) {
+	var webhook entities.Webhook
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&webhook).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("webhook with ID %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to find webhook by ID: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+func (r *webhookRepositoryImpl) ListWebhooks(ctx context.Context) ([]entities.Webhook, error) {
+	var webhooks []entities.Webhook
+
+	if err := r.db.WithContext(ctx).Order("created_at asc").Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *webhookRepositoryImpl) ListActive(ctx context.Context) ([]entities.Webhook, error) {
+	var webhooks []entities.Webhook
+
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list active webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
@@ -0,0 +1,10 @@
+package responses
+
+import "testcase/internal/modules/webhook/entities"
+
+// WebhookCreatedResponse carries the signing secret, which is only shown
+// when it is set.
+type WebhookCreatedResponse struct {
+	entities.Webhook
+	Secret string `json:"secret,omitempty"`
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	"testcase/internal/modules/webhook/entities"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"time"
+)
+
+func (s *webhookServiceImpl) HandleEvent(ctx context.Context, event *outboxEntities.OutboxEvent) error {
+	webhooks, err := s.webhookRepo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []entities.WebhookDelivery
+	for i := range webhooks {
+		if !webhooks[i].Subscribes(event.Type) {
+			continue
+		}
+		delivery, err := newDelivery(&webhooks[i], event.ID, event.Type, event.CreatedAt, event.Payload)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return s.deliveryRepo.EnqueueDeliveries(ctx, deliveries)
+}
+
+// RunDeliveries polls for due deliveries of every organization. Deliveries
+// of a disabled webhook stay pending and resume once it is enabled again.
+func (s *webhookServiceImpl) RunDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Webhook.PollInterval)
+	defer ticker.Stop()
+
+	ctx = utils.WithoutTenantScope(ctx)
+	for {
+		s.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *webhookServiceImpl) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := s.deliveryRepo.ClaimDue(ctx, s.config.Webhook.BatchSize, s.claimLease())
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Webhook delivery failed: %v", err)
+			}
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *entities.WebhookDelivery) {
+				defer wg.Done()
+				if err := s.attempt(ctx, delivery, false); err != nil && ctx.Err() == nil {
+					log.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+				}
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		if len(deliveries) < s.config.Webhook.BatchSize {
+			return
+		}
+	}
+}
+
+// attempt sends the delivery once and stores the outcome. Failed automatic
+// attempts are rescheduled until the delivery runs out of attempts and is
+// dead-lettered.
+func (s *webhookServiceImpl) attempt(ctx context.Context, delivery *entities.WebhookDelivery, manual bool) error {
+	webhook := delivery.Webhook
+	if webhook == nil {
+		return fmt.Errorf("webhook %s of delivery %s not found", delivery.WebhookID, delivery.ID)
+	}
+
+	var attempt *entities.WebhookDeliveryAttempt
+	secret, err := securities.DecryptSecret(s.config.Webhook.EncryptionKey, webhook.Secret)
+	if err != nil {
+		attempt = &entities.WebhookDeliveryAttempt{DeliveryID: delivery.ID, Attempt: delivery.AttemptCount + 1, Error: err.Error()}
+	} else {
+		attempt = s.sender.send(ctx, webhook.URL, secret, delivery)
+	}
+	if ctx.Err() != nil {
+		// Shutting down: leave the delivery to be claimed again.
+		return ctx.Err()
+	}
+	attempt.Manual = manual
+
+	now := time.Now()
+	delivery.AttemptCount++
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+	delivery.UpdatedAt = now
+
+	switch {
+	case attempt.Succeeded():
+		delivery.Status = entities.DeliverySucceeded
+		delivery.DeliveredAt = &now
+	case delivery.Status != entities.DeliveryPending:
+	case delivery.AttemptCount >= s.config.Webhook.MaxAttempts:
+		delivery.Status = entities.DeliveryDead
+		log.Printf("Webhook delivery %s (%s) dead-lettered after %d attempts: %s", delivery.ID, delivery.EventType, delivery.AttemptCount, attempt.Error)
+	default:
+		delivery.NextAttemptAt = now.Add(s.retryDelay(delivery.AttemptCount))
+	}
+
+	return s.deliveryRepo.RecordAttempt(utils.WithTenant(ctx, delivery.TenantID), delivery, attempt)
+}
+
+// retryDelay doubles the configured delay after every failed attempt, up
+// to the configured maximum.
+func (s *webhookServiceImpl) retryDelay(attempts int) time.Duration {
+	delay := s.config.Webhook.RetryDelay
+	for i := 1; i < attempts && delay < s.config.Webhook.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, s.config.Webhook.MaxRetryDelay)
+}
+
+// claimLease is how long a claimed delivery is reserved for the worker
+// sending it; a worker that dies mid-delivery releases it after this.
+func (s *webhookServiceImpl) claimLease() time.Duration {
+	return 2*s.config.Webhook.Timeout + time.Minute
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"testcase/config"
+	"testcase/internal/modules/webhook/entities"
+	"testcase/package/securities"
+	"time"
+)
+
+// maxResponseBody is how much of a receiver's response is kept in the
+// delivery log.
+const maxResponseBody = 2048
+
+var errPrivateTarget = errors.New("webhook target is on a private network")
+
+type webhookSender struct {
+	client *http.Client
+}
+
+func newWebhookSender(cfg *config.Config) *webhookSender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.Webhook.AllowPrivateTargets {
+		// Checking the address actually dialed, after DNS resolution,
+		// also catches public names pointing at internal hosts.
+		dialer := &net.Dialer{Timeout: cfg.Webhook.Timeout, Control: refusePrivateTargets}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+
+	return &webhookSender{
+		client: &http.Client{
+			Timeout:   cfg.Webhook.Timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// send posts the delivery payload once and reports what happened. It never
+// fails; transport errors are recorded on the attempt.
+func (s *webhookSender) send(ctx context.Context, url, secret string, delivery *entities.WebhookDelivery) *entities.WebhookDeliveryAttempt {
+	attempt := &entities.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.AttemptCount + 1,
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Testcase-Webhooks/1.0")
+	request.Header.Set(securities.WebhookDeliveryHeader, delivery.ID.String())
+	request.Header.Set(securities.WebhookEventHeader, delivery.EventType)
+	request.Header.Set(securities.WebhookTimestampHeader, timestamp)
+	request.Header.Set(securities.WebhookSignatureHeader, securities.SignWebhook(secret, timestamp, delivery.Payload))
+
+	started := time.Now()
+	response, err := s.client.Do(request)
+	attempt.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBody))
+	attempt.StatusCode = response.StatusCode
+	attempt.ResponseBody = strings.ReplaceAll(strings.ToValidUTF8(string(body), ""), "\x00", "")
+	if !attempt.Succeeded() {
+		attempt.Error = fmt.Sprintf("receiver responded with status %d", response.StatusCode)
+	}
+
+	return attempt
+}
+
+func refusePrivateTargets(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isPrivateAddress(ip) {
+		return fmt.Errorf("%w: %s", errPrivateTarget, host)
+	}
+	return nil
+}
+
+func isPrivateAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testcase/config"
+	"testcase/internal/modules/webhook/dto"
+	"testcase/internal/modules/webhook/entities"
+	"testcase/package/securities"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func webhookConfig(allowPrivate bool) *config.Config {
+	return &config.Config{Webhook: config.Webhook{Timeout: 5 * time.Second, AllowPrivateTargets: allowPrivate}}
+}
+
+func testDelivery() *entities.WebhookDelivery {
+	return &entities.WebhookDelivery{
+		ID:        uuid.New(),
+		EventType: "document.approved",
+		Payload:   []byte(`{"type":"document.approved"}`),
+	}
+}
+
+func TestWebhookSenderSignsDeliveries(t *testing.T) {
+	var verified bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verified = securities.VerifyWebhookSignature("whsec-test", r.Header.Get(securities.WebhookTimestampHeader), body, r.Header.Get(securities.WebhookSignatureHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	attempt := newWebhookSender(webhookConfig(true)).send(context.Background(), receiver.URL, "whsec-test", testDelivery())
+	if !attempt.Succeeded() {
+		t.Fatalf("expected the delivery to succeed, got %+v", attempt)
+	}
+	if !verified {
+		t.Fatal("expected the receiver to verify the signature")
+	}
+}
+
+func TestWebhookSenderRefusesPrivateTargets(t *testing.T) {
+	var called bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	attempt := newWebhookSender(webhookConfig(false)).send(context.Background(), receiver.URL, "whsec-test", testDelivery())
+	if attempt.Succeeded() || !strings.Contains(attempt.Error, errPrivateTarget.Error()) {
+		t.Fatalf("expected the loopback receiver to be refused, got %+v", attempt)
+	}
+	if called {
+		t.Fatal("expected no request to reach the receiver")
+	}
+}
+
+func TestValidateInputRefusesPrivateTargets(t *testing.T) {
+	service := &webhookServiceImpl{config: webhookConfig(false)}
+
+	for _, target := range []string{
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"https://10.1.2.3/hook",
+		"https://192.168.0.10/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+	} {
+		if err := service.validateInput(&dto.WebhookInput{URL: target, Events: []string{"*"}}); err == nil {
+			t.Errorf("expected %s to be refused", target)
+		}
+	}
+
+	if err := service.validateInput(&dto.WebhookInput{URL: "https://hooks.example.com/in", Events: []string{"document.*"}}); err != nil {
+		t.Errorf("expected a public URL to be accepted, got %v", err)
+	}
+
+	service.config = webhookConfig(true)
+	if err := service.validateInput(&dto.WebhookInput{URL: "http://localhost:8080/hook", Events: []string{"*"}}); err != nil {
+		t.Errorf("expected private targets to be allowed when configured, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"testcase/internal/helpers"
+	outboxEntities "testcase/internal/modules/outbox/entities"
+	"testcase/internal/modules/webhook/dto"
+	"testcase/internal/modules/webhook/entities"
+	"testcase/internal/modules/webhook/responses"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, input *dto.WebhookInput) (*responses.WebhookCreatedResponse, error)
+	ListWebhooks(ctx context.Context) ([]entities.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*entities.Webhook, error)
+	UpdateWebhook(ctx context.Context, id string, input *dto.WebhookInput) (*responses.WebhookCreatedResponse, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	PingWebhook(ctx context.Context, id string) (*entities.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, id string, params *helpers.PaginationParams) ([]entities.WebhookDelivery, int64, error)
+	GetDelivery(ctx context.Context, id, deliveryID string) (*entities.WebhookDelivery, error)
+	RedeliverDelivery(ctx context.Context, id, deliveryID string) (*entities.WebhookDelivery, error)
+
+	// HandleEvent queues an outbox event for every active webhook of its
+	// organization that subscribes to it.
+	HandleEvent(ctx context.Context, event *outboxEntities.OutboxEvent) error
+	// RunDeliveries sends due deliveries until ctx is cancelled.
+	RunDeliveries(ctx context.Context)
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"testcase/config"
+	"testcase/internal/helpers"
+	commentEvents "testcase/internal/modules/comment/events"
+	documentEvents "testcase/internal/modules/document/events"
+	"testcase/internal/modules/webhook/dto"
+	"testcase/internal/modules/webhook/entities"
+	"testcase/internal/modules/webhook/repositories"
+	"testcase/internal/modules/webhook/responses"
+	"testcase/internal/utils"
+	"testcase/package/securities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// webhookSecretBytes is the entropy of generated signing secrets.
+const webhookSecretBytes = 32
+
+type webhookServiceImpl struct {
+	webhookRepo  repositories.WebhookRepository
+	deliveryRepo repositories.WebhookDeliveryRepository
+	sender       *webhookSender
+	config       *config.Config
+}
+
+func NewWebhookService(webhookRepo repositories.WebhookRepository, deliveryRepo repositories.WebhookDeliveryRepository, cfg *config.Config) WebhookService {
+	return &webhookServiceImpl{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		sender:       newWebhookSender(cfg),
+		config:       cfg,
+	}
+}
+
+func (s *webhookServiceImpl) CreateWebhook(ctx context.Context, input *dto.WebhookInput) (*responses.WebhookCreatedResponse, error) {
+	if err := s.validateInput(input); err != nil {
+		return nil, err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		generated, err := securities.GenerateRandomToken(webhookSecretBytes)
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrInternalServer, err)
+		}
+		secret = "whsec_" + generated
+	}
+	encrypted, err := securities.EncryptSecret(s.config.Webhook.EncryptionKey, secret)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	webhook := &entities.Webhook{
+		URL:         input.URL,
+		Description: input.Description,
+		Events:      input.Events,
+		Secret:      encrypted,
+		Active:      input.Active == nil || *input.Active,
+	}
+	if userID, ok := ctx.Value(utils.UserIDContextKey).(uuid.UUID); ok {
+		webhook.CreatedBy = &userID
+	}
+
+	if err := s.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return &responses.WebhookCreatedResponse{Webhook: *webhook, Secret: secret}, nil
+}
+
+func (s *webhookServiceImpl) ListWebhooks(ctx context.Context) ([]entities.Webhook, error) {
+	webhooks, err := s.webhookRepo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *webhookServiceImpl) GetWebhook(ctx context.Context, id string) (*entities.Webhook, error) {
+	webhookID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid webhook ID: %w", err))
+	}
+
+	webhook, err := s.webhookRepo.FindByID(ctx, webhookID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrNotFound, err)
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookServiceImpl) UpdateWebhook(ctx context.Context, id string, input *dto.WebhookInput) (*responses.WebhookCreatedResponse, error) {
+	webhook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(input); err != nil {
+		return nil, err
+	}
+
+	webhook.URL = input.URL
+	webhook.Description = input.Description
+	webhook.Events = input.Events
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+	if input.Secret != "" {
+		encrypted, err := securities.EncryptSecret(s.config.Webhook.EncryptionKey, input.Secret)
+		if err != nil {
+			return nil, utils.NewAppError(utils.ErrInternalServer, err)
+		}
+		webhook.Secret = encrypted
+	}
+
+	if err := s.webhookRepo.UpdateWebhook(ctx, webhook); err != nil {
+		return nil, utils.NewAppError(utils.ErrUpdateDataError, err)
+	}
+
+	return &responses.WebhookCreatedResponse{Webhook: *webhook, Secret: input.Secret}, nil
+}
+
+func (s *webhookServiceImpl) DeleteWebhook(ctx context.Context, id string) error {
+	webhook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.DeleteWebhook(ctx, webhook.ID); err != nil {
+		return utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return nil
+}
+
+// PingWebhook sends a webhook.ping event right away so a receiver can be
+// checked without waiting for a real event.
+func (s *webhookServiceImpl) PingWebhook(ctx context.Context, id string) (*entities.WebhookDelivery, error) {
+	webhook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(map[string]uuid.UUID{"webhook_id": webhook.ID})
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	delivery, err := newDelivery(webhook, uuid.New(), entities.PingEvent, time.Now(), data)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+	// Keep the worker away while the first attempt is made here.
+	delivery.NextAttemptAt = time.Now().Add(s.claimLease())
+	if err := s.deliveryRepo.EnqueueDeliveries(ctx, []entities.WebhookDelivery{*delivery}); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	delivery.Webhook = webhook
+	if err := s.attempt(ctx, delivery, true); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return s.GetDelivery(ctx, id, delivery.ID.String())
+}
+
+func (s *webhookServiceImpl) ListDeliveries(ctx context.Context, id string, params *helpers.PaginationParams) ([]entities.WebhookDelivery, int64, error) {
+	webhook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, total, err := s.deliveryRepo.ListDeliveries(ctx, webhook.ID, params)
+	if err != nil {
+		return nil, 0, utils.NewAppError(utils.ErrFetchDataError, err)
+	}
+
+	return deliveries, total, nil
+}
+
+func (s *webhookServiceImpl) GetDelivery(ctx context.Context, id, deliveryID string) (*entities.WebhookDelivery, error) {
+	webhookID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid webhook ID: %w", err))
+	}
+	parsedDeliveryID, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidRequest, fmt.Errorf("invalid delivery ID: %w", err))
+	}
+
+	delivery, err := s.deliveryRepo.FindDelivery(ctx, webhookID, parsedDeliveryID)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrNotFound, err)
+	}
+
+	return delivery, nil
+}
+
+// RedeliverDelivery sends a delivery again right away, whatever its state.
+// A dead or succeeded delivery keeps its state when the new attempt fails.
+func (s *webhookServiceImpl) RedeliverDelivery(ctx context.Context, id, deliveryID string) (*entities.WebhookDelivery, error) {
+	webhook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Webhook = webhook
+	delivery.Attempts = nil
+	if err := s.attempt(ctx, delivery, true); err != nil {
+		return nil, utils.NewAppError(utils.ErrInternalServer, err)
+	}
+
+	return s.GetDelivery(ctx, id, deliveryID)
+}
+
+func (s *webhookServiceImpl) validateInput(input *dto.WebhookInput) error {
+	target, err := url.Parse(input.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("invalid webhook url %q", input.URL), "Webhook URL must be an absolute http or https URL")
+	}
+	if !s.config.Webhook.AllowPrivateTargets {
+		ip := net.ParseIP(target.Hostname())
+		if strings.EqualFold(target.Hostname(), "localhost") || (ip != nil && isPrivateAddress(ip)) {
+			return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("%w: %s", errPrivateTarget, target.Hostname()), "Webhook URL must not point at a private network")
+		}
+	}
+
+	for _, pattern := range input.Events {
+		if !knownEventPattern(pattern) {
+			return utils.NewAppErrorWithMessage(utils.ErrInvalidRequest, fmt.Errorf("unknown event %q", pattern), fmt.Sprintf("Unknown event %q", pattern))
+		}
+	}
+
+	return nil
+}
+
+// knownEventPattern accepts a pattern that matches at least one event type
+// the application raises.
+func knownEventPattern(pattern string) bool {
+	for _, eventType := range append(append([]string{}, documentEvents.Types...), commentEvents.Types...) {
+		if entities.MatchesEvent(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func newDelivery(webhook *entities.Webhook, eventID uuid.UUID, eventType string, occurredAt time.Time, data json.RawMessage) (*entities.WebhookDelivery, error) {
+	payload, err := json.Marshal(entities.EventEnvelope{
+		ID:        eventID,
+		Type:      eventType,
+		TenantID:  webhook.TenantID,
+		CreatedAt: occurredAt,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.WebhookDelivery{
+		ID:            uuid.New(),
+		TenantID:      webhook.TenantID,
+		WebhookID:     webhook.ID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        entities.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"testcase/internal/middlewares"
+	roleEntities "testcase/internal/modules/role/entities"
+	"testcase/internal/modules/webhook/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterWebhookRoutes(rg *gin.RouterGroup, h *handlers.WebhookHandler, authMware *middlewares.AuthMiddleware) {
+
+	webhookRoutes := rg.Group("/webhooks")
+	webhookRoutes.Use(authMware.Auth(), authMware.RequirePermission(roleEntities.PermissionWebhookManage))
+	{
+		webhookRoutes.GET("", h.ListWebhooks)
+		webhookRoutes.POST("", h.CreateWebhook)
+		webhookRoutes.GET("/:id", h.GetWebhook)
+		webhookRoutes.PUT("/:id", h.UpdateWebhook)
+		webhookRoutes.DELETE("/:id", h.DeleteWebhook)
+		webhookRoutes.POST("/:id/ping", h.PingWebhook)
+		webhookRoutes.GET("/:id/deliveries", h.ListDeliveries)
+		webhookRoutes.GET("/:id/deliveries/:deliveryId", h.GetDelivery)
+		webhookRoutes.POST("/:id/deliveries/:deliveryId/redeliver", h.RedeliverDelivery)
+	}
+}
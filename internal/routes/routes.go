@@ -1,49 +1,305 @@
 package routes
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"testcase/config"
 	"testcase/internal/infrastructures/database"
 	"testcase/internal/middlewares"
+	"testcase/internal/modules/audit"
+	auditHandler "testcase/internal/modules/audit/handlers"
+	auditRepository "testcase/internal/modules/audit/repositories"
+	auditService "testcase/internal/modules/audit/services"
+	"testcase/internal/modules/comment"
+	commentEvents "testcase/internal/modules/comment/events"
+	commentHandler "testcase/internal/modules/comment/handlers"
+	commentRepository "testcase/internal/modules/comment/repositories"
+	commentService "testcase/internal/modules/comment/services"
+	"testcase/internal/modules/department"
+	departmentHandler "testcase/internal/modules/department/handlers"
+	departmentRepository "testcase/internal/modules/department/repositories"
+	departmentService "testcase/internal/modules/department/services"
 	"testcase/internal/modules/document"
+	documentEvents "testcase/internal/modules/document/events"
 	documentHandler "testcase/internal/modules/document/handlers"
 	documentRepository "testcase/internal/modules/document/repositories"
 	documentService "testcase/internal/modules/document/services"
+	"testcase/internal/modules/notification"
+	notificationHandler "testcase/internal/modules/notification/handlers"
+	notificationRepository "testcase/internal/modules/notification/repositories"
+	notificationService "testcase/internal/modules/notification/services"
+	"testcase/internal/modules/organization"
+	organizationHandler "testcase/internal/modules/organization/handlers"
+	organizationRepository "testcase/internal/modules/organization/repositories"
+	organizationService "testcase/internal/modules/organization/services"
+	outboxRepository "testcase/internal/modules/outbox/repositories"
+	outboxService "testcase/internal/modules/outbox/services"
+	"testcase/internal/modules/review"
+	reviewHandler "testcase/internal/modules/review/handlers"
+	reviewService "testcase/internal/modules/review/services"
+	"testcase/internal/modules/role"
+	roleHandler "testcase/internal/modules/role/handlers"
+	roleRepository "testcase/internal/modules/role/repositories"
+	roleService "testcase/internal/modules/role/services"
+	"testcase/internal/modules/setting"
+	settingHandler "testcase/internal/modules/setting/handlers"
+	settingRepository "testcase/internal/modules/setting/repositories"
+	settingService "testcase/internal/modules/setting/services"
+	"testcase/internal/modules/stream"
+	streamHandler "testcase/internal/modules/stream/handlers"
+	streamService "testcase/internal/modules/stream/services"
 	"testcase/internal/modules/user"
 	userHandler "testcase/internal/modules/user/handlers"
 	userRepository "testcase/internal/modules/user/repositories"
 	userService "testcase/internal/modules/user/services"
+	"testcase/internal/modules/webhook"
+	webhookHandler "testcase/internal/modules/webhook/handlers"
+	webhookRepository "testcase/internal/modules/webhook/repositories"
+	webhookService "testcase/internal/modules/webhook/services"
 	"testcase/internal/utils"
+	"testcase/package/ldap"
+	"testcase/package/mailer"
+	"testcase/package/oidc"
 	"testcase/package/securities"
 
 	"github.com/gin-gonic/gin"
 )
 
-func InitHttpRoutes(r *gin.Engine, db *database.Database) {
+// InitHttpRoutes wires the modules onto r. Background workers, such as the
+// outbox dispatcher, run until ctx is cancelled.
+func InitHttpRoutes(ctx context.Context, r *gin.Engine, db *database.Database) {
 	config := config.LoadConfig()
+
+	var keySet *securities.KeySet
+	if config.SigningKeyFile != "" {
+		loaded, err := securities.LoadKeySet(config.SigningKeyFile, config.SigningKeyID, config.VerificationKeys)
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing keys: %v", err)
+		}
+		keySet = loaded
+	}
+
 	jwtManager := securities.NewJWTManager(
 		config.AccessTokenSecret,
 		config.RefreshTokenSecret,
 		config.TokenExpiry,
 		config.RefreshExpiry,
-		"",
+		keySet,
+		securities.JWTOptions{
+			Issuer:   config.TokenIssuer,
+			Audience: config.TokenAudience,
+			Leeway:   config.TokenLeeway,
+		},
 	)
 
-	authMware := middlewares.NewAuthMiddleware(jwtManager)
+	// Revocations, nonces and the keys of signing, webhook and MFA secrets
+	// must be shared by every replica; only a single development instance
+	// may keep them in memory or fall back to the built-in keys.
+	if config.RequestSigning.EncryptionKey == "" {
+		if !config.HttpServer.IsDevelopment() {
+			log.Fatalf("REQUEST_SIGNING_ENCRYPTION_KEY must be set outside development")
+		}
+		log.Println("⚠️ REQUEST_SIGNING_ENCRYPTION_KEY is not set, using the development key")
+		config.RequestSigning.UseDevelopmentKey()
+	}
+	if config.Webhook.EncryptionKey == "" {
+		if !config.HttpServer.IsDevelopment() {
+			log.Fatalf("WEBHOOK_ENCRYPTION_KEY must be set outside development")
+		}
+		log.Println("⚠️ WEBHOOK_ENCRYPTION_KEY is not set, using the development key")
+		config.Webhook.UseDevelopmentKey()
+	}
+	if config.MFA.EncryptionKey == "" {
+		if !config.HttpServer.IsDevelopment() {
+			log.Fatalf("MFA_ENCRYPTION_KEY must be set outside development")
+		}
+		log.Println("⚠️ MFA_ENCRYPTION_KEY is not set, using the development key")
+		config.MFA.UseDevelopmentKey()
+	}
+
+	revocationStore := securities.NewMemoryRevocationStore()
+	if config.UsesDatabaseRevocation() {
+		revocationStore = userRepository.NewRevokedTokenRepository(db)
+	} else if !config.HttpServer.IsDevelopment() {
+		log.Fatalf("TOKEN_REVOCATION_STORE=%s is only allowed in development", config.RevocationStore)
+	}
+	revocations := securities.NewRevocationList(revocationStore, config.TokenExpiry, config.TokenLeeway)
+
+	appMailer, err := newMailer(config)
+	if err != nil {
+		log.Fatalf("Failed to configure mail: %v", err)
+	}
+
+	var oidcProvider *oidc.LazyProvider
+	if config.OIDC.Enabled() {
+		oidcProvider = oidc.NewLazyProvider(oidc.Config{
+			IssuerURL:    config.OIDC.IssuerURL,
+			ClientID:     config.OIDC.ClientID,
+			ClientSecret: config.OIDC.ClientSecret,
+			RedirectURL:  config.OIDC.RedirectURL,
+			Scopes:       config.OIDC.Scopes,
+		})
+	}
+
+	var directory *ldap.Directory
+	if config.UsesLDAPPasswords() {
+		if !config.LDAP.Encrypted() {
+			if !config.HttpServer.IsDevelopment() {
+				log.Fatalf("LDAP_URL must use ldaps:// or LDAP_START_TLS=true outside development")
+			}
+			log.Println("⚠️ LDAP passwords are sent unencrypted, use ldaps:// or LDAP_START_TLS outside development")
+		}
+		loaded, err := ldap.NewDirectory(ldap.Config{
+			URL:                config.LDAP.URL,
+			StartTLS:           config.LDAP.StartTLS,
+			InsecureSkipVerify: config.LDAP.InsecureSkipVerify,
+			CACertFile:         config.LDAP.CACertFile,
+			BindDN:             config.LDAP.BindDN,
+			BindPassword:       config.LDAP.BindPassword,
+			BaseDN:             config.LDAP.BaseDN,
+			UserFilter:         config.LDAP.UserFilter,
+			EmailAttribute:     config.LDAP.EmailAttribute,
+			NameAttribute:      config.LDAP.NameAttribute,
+			UsernameAttribute:  config.LDAP.UsernameAttribute,
+			GroupAttribute:     config.LDAP.GroupAttribute,
+			Timeout:            config.LDAP.Timeout,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure LDAP authentication: %v", err)
+		}
+		directory = loaded
+	}
 
 	userRepo := userRepository.NewUserRepository(db)
+	emailVerificationRepo := userRepository.NewEmailVerificationRepository(db)
+	mfaRepo := userRepository.NewMFARepository(db)
+	lockoutRepo := userRepository.NewLoginLockoutRepository(db)
+	sessionRepo := userRepository.NewSessionRepository(db)
+	apiKeyRepo := userRepository.NewAPIKeyRepository(db)
+	identityRepo := userRepository.NewExternalIdentityRepository(db)
 	documentRepo := documentRepository.NewDocumentRepository(db)
+	roleRepo := roleRepository.NewRoleRepository(db)
+	departmentRepo := departmentRepository.NewDepartmentRepository(db)
+	organizationRepo := organizationRepository.NewOrganizationRepository(db)
+	settingRepo := settingRepository.NewSettingRepository(db)
+	outboxRepo := outboxRepository.NewOutboxRepository(db)
+	webhookRepo := webhookRepository.NewWebhookRepository(db)
+	webhookDeliveryRepo := webhookRepository.NewWebhookDeliveryRepository(db)
+	notificationRepo := notificationRepository.NewNotificationRepository(db)
+	commentRepo := commentRepository.NewCommentRepository(db)
+	auditRepo := auditRepository.NewAuditRepository(db)
+
+	if err := auditRepo.EnsureAppendOnly(context.Background()); err != nil {
+		log.Fatalf("Failed to protect audit log: %v", err)
+	}
+	auditService := auditService.NewAuditService(auditRepo, organizationRepo, config)
 
-	userService := userService.NewUserService(userRepo, jwtManager)
-	documentService := documentService.NewDocumentService(documentRepo)
+	roleService := roleService.NewRoleService(roleRepo, auditService)
+	if err := roleService.EnsureDefaultRoles(context.Background()); err != nil {
+		log.Fatalf("Failed to seed default roles: %v", err)
+	}
+
+	if _, err := documentService.ParseApprovalRules(config.Approval.StepRules); err != nil {
+		log.Fatalf("Invalid APPROVAL_STEP_RULES: %v", err)
+	}
+	settingService := settingService.NewSettingService(db, settingRepo, config, documentService.ValidateApprovalSettings)
+
+	userService := userService.NewUserService(userRepo, emailVerificationRepo, mfaRepo, lockoutRepo, sessionRepo, apiKeyRepo, identityRepo, roleRepo, organizationRepo, settingService, jwtManager, revocations, oidcProvider, directory, appMailer, auditService, config)
+	if err := userService.EncryptMFASecrets(context.Background()); err != nil {
+		log.Fatalf("Failed to encrypt MFA secrets: %v", err)
+	}
+	departmentService := departmentService.NewDepartmentService(departmentRepo, userRepo)
+	organizationService := organizationService.NewOrganizationService(organizationRepo, userService, config)
+	defaultOrganization, err := organizationService.EnsureDefaultOrganization(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to prepare default organization: %v", err)
+	}
+
+	documentService := documentService.NewDocumentService(documentRepo, roleService, departmentService, settingService, config)
+	commentService := commentService.NewCommentService(commentRepo, userRepo, documentService, roleService)
+
+	webhookService := webhookService.NewWebhookService(webhookRepo, webhookDeliveryRepo, config)
+	notificationService, err := notificationService.NewNotificationService(notificationRepo, userRepo, documentService, departmentService, settingService, appMailer, config)
+	if err != nil {
+		log.Fatalf("Failed to load notification templates: %v", err)
+	}
+	streamService := streamService.NewStreamService(db, outboxRepo, notificationService.DocumentAudience, config)
+	reviewService := reviewService.NewReviewService(db, documentService, commentService, outboxRepo, config)
+
+	outboxDispatcher := outboxService.NewDispatcher(outboxRepo, config)
+	outboxDispatcher.Subscribe(outboxService.AllEvents, webhookService.HandleEvent)
+	for _, eventType := range documentEvents.Types {
+		outboxDispatcher.Subscribe(eventType, streamService.Publish)
+		outboxDispatcher.Subscribe(eventType, reviewService.Publish)
+		outboxDispatcher.Subscribe(eventType, notificationService.HandleDocumentEvent)
+		outboxDispatcher.Subscribe(eventType, auditService.HandleEvent)
+	}
+	for _, eventType := range commentEvents.Types {
+		outboxDispatcher.Subscribe(eventType, reviewService.Publish)
+		outboxDispatcher.Subscribe(eventType, notificationService.HandleCommentEvent)
+		outboxDispatcher.Subscribe(eventType, auditService.HandleEvent)
+	}
+	go settingService.Run(ctx)
+	go outboxDispatcher.Run(ctx)
+	go streamService.Run(ctx)
+	go reviewService.Run(ctx)
+	go webhookService.RunDeliveries(ctx)
+
+	authMware := middlewares.NewAuthMiddleware(jwtManager, revocations, userService, userRepository.NewRequestNonceRepository(db), roleService, defaultOrganization.ID, config)
+	auditMware := middlewares.NewAuditMiddleware(auditService)
 
 	documentHandler := documentHandler.NewDocumentHandler(documentService)
-	userHandler := userHandler.NewUserHandler(userService)
+	commentHandler := commentHandler.NewCommentHandler(commentService)
+	userHandler := userHandler.NewUserHandler(userService, config)
+	roleHandler := roleHandler.NewRoleHandler(roleService)
+	departmentHandler := departmentHandler.NewDepartmentHandler(departmentService)
+	organizationHandler := organizationHandler.NewOrganizationHandler(organizationService)
+	settingHandler := settingHandler.NewSettingHandler(settingService)
+	webhookHandler := webhookHandler.NewWebhookHandler(webhookService)
+	notificationHandler := notificationHandler.NewNotificationHandler(notificationService)
+	streamHandler := streamHandler.NewStreamHandler(streamService)
+	reviewHandler := reviewHandler.NewReviewHandler(reviewService, config)
+	auditHandler := auditHandler.NewAuditHandler(auditService)
+
+	r.GET("/.well-known/jwks.json", userHandler.JWKS)
 
 	v1 := r.Group("api/v1")
+	v1.Use(auditMware.AdminOperations())
 	{
 		user.RegisterUserRoutes(v1, userHandler, authMware)
 		document.RegisterDocumentRoutes(v1, documentHandler, authMware)
+		comment.RegisterCommentRoutes(v1, commentHandler, authMware)
+		role.RegisterRoleRoutes(v1, roleHandler, authMware)
+		department.RegisterDepartmentRoutes(v1, departmentHandler, authMware)
+		organization.RegisterOrganizationRoutes(v1, organizationHandler, authMware)
+		setting.RegisterSettingRoutes(v1, settingHandler, authMware)
+		webhook.RegisterWebhookRoutes(v1, webhookHandler, authMware)
+		notification.RegisterNotificationRoutes(v1, notificationHandler, authMware)
+		stream.RegisterStreamRoutes(v1, streamHandler, authMware)
+		review.RegisterReviewRoutes(v1, reviewHandler, authMware)
+		audit.RegisterAuditRoutes(v1, auditHandler, authMware)
 	}
 
 	r.NoRoute(func(c *gin.Context) { utils.HandleRouteNotFound(c) })
 }
+
+func newMailer(config *config.Config) (mailer.Mailer, error) {
+	switch config.Mail.Driver {
+	case "log":
+		return mailer.NewLogMailer(), nil
+	case "file":
+		return mailer.NewFileMailer(config.Mail.FileDir)
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     config.Mail.SMTPHost,
+			Port:     config.Mail.SMTPPort,
+			Username: config.Mail.SMTPUsername,
+			Password: config.Mail.SMTPPassword,
+			TLS:      config.Mail.SMTPTLS,
+			Timeout:  config.Mail.SMTPTimeout,
+		})
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", config.Mail.Driver)
+	}
+}
@@ -1,19 +1,60 @@
 package utils
 
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
 type contextKey string
 
 const (
-	UserIDContextKey   contextKey = "user_id"
-	UsernameContextKey contextKey = "username"
-	RoleContextKey     contextKey = "role"
-	EmailContextKey    contextKey = "email"
-	IsActiveContextKey contextKey = "is_active"
+	UserIDContextKey        contextKey = "user_id"
+	UsernameContextKey      contextKey = "username"
+	RoleContextKey          contextKey = "role"
+	EmailContextKey         contextKey = "email"
+	IsActiveContextKey      contextKey = "is_active"
+	EmailVerifiedContextKey contextKey = "email_verified"
+	TenantIDContextKey      contextKey = "tenant_id"
 )
 
 const (
-	RequestIDContextKey contextKey = "request_id"
-	TraceIDContextKey   contextKey = "trace_id"
-	SessionIDContextKey contextKey = "session_id"
-	IPAddressContextKey contextKey = "ip_address"
-	UserAgentContextKey contextKey = "user_agent"
+	RequestIDContextKey   contextKey = "request_id"
+	TraceIDContextKey     contextKey = "trace_id"
+	SessionIDContextKey   contextKey = "session_id"
+	TokenIDContextKey     contextKey = "token_id"
+	APIKeyIDContextKey    contextKey = "api_key_id"
+	IPAddressContextKey   contextKey = "ip_address"
+	UserAgentContextKey   contextKey = "user_agent"
+	CrossTenantContextKey contextKey = "cross_tenant"
+	AccessCheckContextKey contextKey = "access_check"
 )
+
+// AccessCheck returns an error once the credential that authenticated a
+// request no longer grants access, for example after it was revoked.
+type AccessCheck func(ctx context.Context) error
+
+// CheckAccess runs the AccessCheck the auth middleware left in ctx.
+// Long-lived responses such as streams call it periodically, since the
+// middleware only ran when they started. Contexts without one pass.
+func CheckAccess(ctx context.Context) error {
+	check, ok := ctx.Value(AccessCheckContextKey).(AccessCheck)
+	if !ok {
+		return nil
+	}
+	return check(ctx)
+}
+
+// WithTenant scopes database access made with ctx to the tenant.
+func WithTenant(ctx context.Context, tenantID uuid.UUID) context.Context {
+	ctx = context.WithValue(ctx, CrossTenantContextKey, false)
+	return context.WithValue(ctx, TenantIDContextKey, tenantID)
+}
+
+// WithoutTenantScope lets database access made with ctx cross tenants. It
+// is meant for lookups that run before the tenant is known, such as finding
+// the user behind a login or an API key.
+func WithoutTenantScope(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, TenantIDContextKey, uuid.Nil)
+	return context.WithValue(ctx, CrossTenantContextKey, true)
+}
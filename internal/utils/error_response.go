@@ -30,6 +30,11 @@ var (
 	ErrEmailExists        = ErrorCode{Code: 108, Key: "email_exists", Message: "Email already exists", HttpStatus: http.StatusConflict}
 	ErrInactiveUser       = ErrorCode{Code: 109, Key: "inactive_user", Message: "User is inactive", HttpStatus: http.StatusForbidden}
 	ErrForbiddenAccess    = ErrorCode{Code: 110, Key: "forbidden_access", Message: "You do not have permission to access this resource", HttpStatus: http.StatusForbidden}
+	ErrEmailNotVerified   = ErrorCode{Code: 111, Key: "email_not_verified", Message: "Email address has not been verified", HttpStatus: http.StatusForbidden}
+	ErrInvalidMFACode     = ErrorCode{Code: 112, Key: "invalid_mfa_code", Message: "Invalid authentication code", HttpStatus: http.StatusUnauthorized}
+	ErrStepUpRequired     = ErrorCode{Code: 113, Key: "step_up_required", Message: "Recent re-authentication is required for this action", HttpStatus: http.StatusUnauthorized}
+	ErrTooManyAttempts    = ErrorCode{Code: 114, Key: "too_many_attempts", Message: "Too many failed attempts, try again later", HttpStatus: http.StatusTooManyRequests}
+	ErrTokenRevoked       = ErrorCode{Code: 115, Key: "token_revoked", Message: "Token has been revoked", HttpStatus: http.StatusUnauthorized}
 )
 
 var errorMap = make(map[int]ErrorCode)
@@ -55,6 +60,11 @@ func init() {
 	registerError(ErrEmailExists)
 	registerError(ErrInactiveUser)
 	registerError(ErrForbiddenAccess)
+	registerError(ErrEmailNotVerified)
+	registerError(ErrInvalidMFACode)
+	registerError(ErrStepUpRequired)
+	registerError(ErrTooManyAttempts)
+	registerError(ErrTokenRevoked)
 }
 
 func registerError(err ErrorCode) {
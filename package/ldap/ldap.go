@@ -0,0 +1,212 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// LoginPlaceholder is replaced with the escaped login in Config.UserFilter.
+const LoginPlaceholder = "{login}"
+
+type Config struct {
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	CACertFile         string
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string
+	EmailAttribute     string
+	NameAttribute      string
+	UsernameAttribute  string
+	GroupAttribute     string
+	Timeout            time.Duration
+}
+
+// Entry is the directory account that a successful bind authenticated.
+// Groups holds every value of the group attribute, normalized with
+// NormalizeDN so mappings can match on the full DN. Groups are never
+// reduced to their CN, since the same CN may exist under several OUs.
+type Entry struct {
+	DN       string
+	Email    string
+	Name     string
+	Username string
+	Groups   []string
+}
+
+var ErrInvalidCredentials = errors.New("invalid directory credentials")
+
+// Conn is the part of an LDAP connection that Directory uses.
+type Conn interface {
+	Bind(username, password string) error
+	Search(request *goldap.SearchRequest) (*goldap.SearchResult, error)
+	Close() error
+}
+
+type Directory struct {
+	config    Config
+	tlsConfig *tls.Config
+	dial      func(ctx context.Context) (Conn, error)
+}
+
+func NewDirectory(cfg Config) (*Directory, error) {
+	if cfg.URL == "" || cfg.BaseDN == "" {
+		return nil, errors.New("LDAP URL and base DN are required")
+	}
+	if !strings.Contains(cfg.UserFilter, LoginPlaceholder) {
+		return nil, fmt.Errorf("LDAP user filter must contain %s", LoginPlaceholder)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CACertFile != "" {
+		pem, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read LDAP CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("LDAP CA certificate file contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	directory := &Directory{config: cfg, tlsConfig: tlsConfig}
+	directory.dial = directory.dialServer
+	return directory, nil
+}
+
+// Authenticate looks the login up with the service account and then binds
+// as the entry found with the given password.
+func (d *Directory) Authenticate(ctx context.Context, login, password string) (*Entry, error) {
+	// An empty password would be an unauthenticated bind, which most servers
+	// accept as success.
+	if login == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := d.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if d.config.BindDN != "" {
+		if err := conn.Bind(d.config.BindDN, d.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("LDAP service bind failed: %w", err)
+		}
+	}
+
+	entry, err := d.findUser(conn, login)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("LDAP user bind failed: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (d *Directory) dialServer(ctx context.Context) (Conn, error) {
+	dialer := &net.Dialer{Timeout: d.config.Timeout}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+
+	conn, err := goldap.DialURL(d.config.URL, goldap.DialWithDialer(dialer), goldap.DialWithTLSConfig(d.tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	conn.SetTimeout(d.config.Timeout)
+
+	if d.config.StartTLS {
+		if err := conn.StartTLS(d.tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("LDAP StartTLS failed: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (d *Directory) findUser(conn Conn, login string) (*Entry, error) {
+	filter := strings.ReplaceAll(d.config.UserFilter, LoginPlaceholder, goldap.EscapeFilter(login))
+	request := goldap.NewSearchRequest(
+		d.config.BaseDN,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		2,
+		int(d.config.Timeout.Seconds()),
+		false,
+		filter,
+		[]string{d.config.EmailAttribute, d.config.NameAttribute, d.config.UsernameAttribute, d.config.GroupAttribute},
+		nil,
+	)
+
+	result, err := conn.Search(request)
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("LDAP user search failed: %w", err)
+	}
+	// Refuse ambiguous filters rather than binding as whichever entry the
+	// server happened to return first.
+	if result == nil || len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+
+	found := result.Entries[0]
+	return &Entry{
+		DN:       found.DN,
+		Email:    found.GetAttributeValue(d.config.EmailAttribute),
+		Name:     found.GetAttributeValue(d.config.NameAttribute),
+		Username: found.GetAttributeValue(d.config.UsernameAttribute),
+		Groups:   groupNames(found.GetAttributeValues(d.config.GroupAttribute)),
+	}, nil
+}
+
+func groupNames(values []string) []string {
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		names = append(names, NormalizeDN(value))
+	}
+
+	return names
+}
+
+// NormalizeDN renders dn with lowercase attribute types and values and no
+// spaces around separators, so DNs written differently compare equal.
+// Values that are not DNs are only trimmed and lowercased.
+func NormalizeDN(dn string) string {
+	parsed, err := goldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return strings.ToLower(strings.TrimSpace(dn))
+	}
+
+	rdns := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		attributes := make([]string, 0, len(rdn.Attributes))
+		for _, attribute := range rdn.Attributes {
+			attributes = append(attributes, strings.ToLower(attribute.Type)+"="+strings.ToLower(attribute.Value))
+		}
+		rdns = append(rdns, strings.Join(attributes, "+"))
+	}
+
+	return strings.Join(rdns, ",")
+}
@@ -0,0 +1,134 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// fakeConn is a directory with a service account and the entries given,
+// which accept password as their bind password.
+type fakeConn struct {
+	entries  []*goldap.Entry
+	password string
+	binds    []string
+	closed   bool
+}
+
+func (c *fakeConn) Bind(username, password string) error {
+	c.binds = append(c.binds, username)
+	if username == "cn=service,dc=example,dc=com" && password == "service-secret" {
+		return nil
+	}
+	for _, entry := range c.entries {
+		if entry.DN == username && password == c.password {
+			return nil
+		}
+	}
+	return goldap.NewError(goldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (c *fakeConn) Search(request *goldap.SearchRequest) (*goldap.SearchResult, error) {
+	return &goldap.SearchResult{Entries: c.entries}, nil
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newTestDirectory(t *testing.T, conn *fakeConn) *Directory {
+	t.Helper()
+
+	directory, err := NewDirectory(Config{
+		URL:               "ldaps://ldap.example.com",
+		BindDN:            "cn=service,dc=example,dc=com",
+		BindPassword:      "service-secret",
+		BaseDN:            "dc=example,dc=com",
+		UserFilter:        "(mail={login})",
+		EmailAttribute:    "mail",
+		NameAttribute:     "cn",
+		UsernameAttribute: "uid",
+		GroupAttribute:    "memberOf",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	directory.dial = func(ctx context.Context) (Conn, error) { return conn, nil }
+	return directory
+}
+
+func janeEntry() *goldap.Entry {
+	return goldap.NewEntry("uid=jane,ou=people,dc=example,dc=com", map[string][]string{
+		"mail":     {"jane@example.com"},
+		"cn":       {"Jane Doe"},
+		"uid":      {"jane"},
+		"memberOf": {"CN=Approvers, OU=Groups, DC=example, DC=com"},
+	})
+}
+
+func TestAuthenticateBindsAsTheUser(t *testing.T) {
+	conn := &fakeConn{entries: []*goldap.Entry{janeEntry()}, password: "correct horse"}
+
+	entry, err := newTestDirectory(t, conn).Authenticate(context.Background(), "jane@example.com", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.DN != "uid=jane,ou=people,dc=example,dc=com" || entry.Email != "jane@example.com" || entry.Username != "jane" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	if len(entry.Groups) != 1 || entry.Groups[0] != "cn=approvers,ou=groups,dc=example,dc=com" {
+		t.Fatalf("expected normalized group DNs, got %v", entry.Groups)
+	}
+	if len(conn.binds) != 2 || conn.binds[1] != entry.DN {
+		t.Fatalf("expected a service bind then a user bind, got %v", conn.binds)
+	}
+	if !conn.closed {
+		t.Fatal("expected the connection to be closed")
+	}
+}
+
+func TestAuthenticateRejectsWrongPassword(t *testing.T) {
+	conn := &fakeConn{entries: []*goldap.Entry{janeEntry()}, password: "correct horse"}
+
+	_, err := newTestDirectory(t, conn).Authenticate(context.Background(), "jane@example.com", "wrong")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestAuthenticateRejectsEmptyPassword(t *testing.T) {
+	conn := &fakeConn{entries: []*goldap.Entry{janeEntry()}, password: ""}
+
+	_, err := newTestDirectory(t, conn).Authenticate(context.Background(), "jane@example.com", "")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if len(conn.binds) != 0 {
+		t.Fatalf("expected no bind for an empty password, got %v", conn.binds)
+	}
+}
+
+func TestAuthenticateRejectsAmbiguousSearch(t *testing.T) {
+	other := goldap.NewEntry("uid=jane2,ou=people,dc=example,dc=com", map[string][]string{"mail": {"jane@example.com"}})
+	conn := &fakeConn{entries: []*goldap.Entry{janeEntry(), other}, password: "correct horse"}
+
+	_, err := newTestDirectory(t, conn).Authenticate(context.Background(), "jane@example.com", "correct horse")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if len(conn.binds) != 1 {
+		t.Fatalf("expected only the service bind, got %v", conn.binds)
+	}
+}
+
+func TestAuthenticateRejectsUnknownUser(t *testing.T) {
+	conn := &fakeConn{password: "correct horse"}
+
+	_, err := newTestDirectory(t, conn).Authenticate(context.Background(), "nobody@example.com", "correct horse")
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9@._-]+`)
+
+type fileMailer struct {
+	dir string
+}
+
+// NewFileMailer writes every message as an .eml file into dir, which most
+// mail clients can open. It is meant for development.
+func NewFileMailer(dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &fileMailer{dir: dir}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg *Message) error {
+	raw, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	recipient := "unknown"
+	if len(msg.To) > 0 {
+		recipient = unsafeFileChars.ReplaceAllString(msg.To[0], "_")
+	}
+	name := fmt.Sprintf("%s-%s-%s.eml", time.Now().Format("20060102-150405.000"), recipient, randomHex(3))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		return fmt.Errorf("failed to write mail file: %w", err)
+	}
+
+	log.Printf("📧 Mail to=%v subject=%q written to %s", msg.To, msg.Subject, path)
+	return nil
+}
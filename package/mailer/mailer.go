@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+type Message struct {
+	From     string
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+type logMailer struct{}
+
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+// Send logs only the envelope. Bodies carry verification links and other
+// one-time tokens that must not end up in logs.
+func (m *logMailer) Send(ctx context.Context, msg *Message) error {
+	log.Printf("📧 Mail to=%s subject=%q", strings.Join(msg.To, ","), msg.Subject)
+	return nil
+}
@@ -0,0 +1,86 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Bytes encodes the message as RFC 5322 mail. A message with both bodies
+// becomes multipart/alternative so clients pick the richest one they
+// support.
+func (m *Message) Bytes() ([]byte, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", m.From, err)
+	}
+	to := make([]string, 0, len(m.To))
+	for _, recipient := range m.To {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", recipient, err)
+		}
+		to = append(to, address.String())
+	}
+
+	var buf bytes.Buffer
+	writeHeader(&buf, "From", from.String())
+	writeHeader(&buf, "To", strings.Join(to, ", "))
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", messageID(from.Address))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	switch {
+	case m.HTMLBody != "" && m.TextBody != "":
+		boundary := randomHex(16)
+		writeHeader(&buf, "Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+		buf.WriteString("\r\n")
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		writePart(&buf, "text/plain", m.TextBody)
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		writePart(&buf, "text/html", m.HTMLBody)
+		fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	case m.HTMLBody != "":
+		writePart(&buf, "text/html", m.HTMLBody)
+	default:
+		writePart(&buf, "text/plain", m.TextBody)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, name, value string) {
+	fmt.Fprintf(buf, "%s: %s\r\n", name, value)
+}
+
+func writePart(buf *bytes.Buffer, contentType, body string) {
+	writeHeader(buf, "Content-Type", contentType+"; charset=utf-8")
+	writeHeader(buf, "Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	writer := quotedprintable.NewWriter(buf)
+	writer.Write([]byte(body))
+	writer.Close()
+	buf.WriteString("\r\n")
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", randomHex(12), domain)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,137 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Templates holds mail templates with a variant per locale, read from a
+// tree of the form
+//
+//	layout.html
+//	<locale>/<name>.html
+//
+// Each template defines "subject", "text" and "content" blocks; layout.html
+// defines "html", which wraps "content". Every locale directory is parsed
+// with the shared layout.
+type Templates struct {
+	sets          map[string]*template.Template
+	defaultLocale string
+}
+
+func ParseTemplates(fsys fs.FS, defaultLocale string) (*Templates, error) {
+	layout, err := fs.ReadFile(fsys, "layout.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mail layout: %w", err)
+	}
+
+	files, err := fs.Glob(fsys, "*/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Templates{sets: make(map[string]*template.Template), defaultLocale: normalizeLocale(defaultLocale)}
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		set, err := template.New(file).Parse(string(layout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse mail layout: %w", err)
+		}
+		if _, err := set.Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("failed to parse mail template %s: %w", file, err)
+		}
+		for _, block := range []string{"subject", "text", "html"} {
+			if set.Lookup(block) == nil {
+				return nil, fmt.Errorf("mail template %s does not define %q", file, block)
+			}
+		}
+
+		locale := normalizeLocale(path.Dir(file))
+		name := strings.TrimSuffix(path.Base(file), ".html")
+		t.sets[locale+"/"+name] = set
+	}
+
+	return t, nil
+}
+
+// Render fills the variant of name for locale, falling back from a
+// regional locale such as "id-ID" to its language and then to the default
+// locale. From and To are left for the caller.
+func (t *Templates) Render(name, locale string, data any) (*Message, error) {
+	set, err := t.lookup(name, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := execute(set, "subject", data)
+	if err != nil {
+		return nil, err
+	}
+	text, err := execute(set, "text", data)
+	if err != nil {
+		return nil, err
+	}
+	htmlBody, err := execute(set, "html", data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subject and text are written through html/template too, so undo its
+	// escaping for these plain-text parts.
+	return &Message{
+		Subject:  strings.Join(strings.Fields(html.UnescapeString(subject)), " "),
+		TextBody: strings.TrimSpace(html.UnescapeString(text)) + "\n",
+		HTMLBody: htmlBody,
+	}, nil
+}
+
+// RenderText fills a single plain-text block of the variant of name for
+// locale, such as a one-line summary shown outside of mail.
+func (t *Templates) RenderText(name, locale, block string, data any) (string, error) {
+	set, err := t.lookup(name, locale)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := execute(set, block, data)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " "), nil
+}
+
+func (t *Templates) lookup(name, locale string) (*template.Template, error) {
+	locale = normalizeLocale(locale)
+	candidates := []string{locale}
+	if language, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, language)
+	}
+	candidates = append(candidates, t.defaultLocale)
+
+	for _, candidate := range candidates {
+		if set, ok := t.sets[candidate+"/"+name]; ok {
+			return set, nil
+		}
+	}
+	return nil, fmt.Errorf("mail template %q not found for locale %q", name, locale)
+}
+
+func execute(set *template.Template, block string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := set.ExecuteTemplate(&buf, block, data); err != nil {
+		return "", fmt.Errorf("failed to render mail %s: %w", block, err)
+	}
+	return buf.String(), nil
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
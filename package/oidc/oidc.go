@@ -0,0 +1,258 @@
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Discovery holds the parts of the provider's
+// /.well-known/openid-configuration document this client uses.
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type Tokens struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// IDToken is a verified ID token. Claims holds every claim so callers can
+// read provider-specific ones such as groups.
+type IDToken struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Claims            jwt.MapClaims
+}
+
+var ErrInvalidIDToken = errors.New("id token is invalid")
+
+const clockLeeway = time.Minute
+
+type Provider struct {
+	config     Config
+	discovery  Discovery
+	keys       *remoteKeySet
+	httpClient *http.Client
+}
+
+// NewProvider fetches the discovery document and checks that it belongs to
+// the configured issuer.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	issuer := strings.TrimSuffix(cfg.IssuerURL, "/")
+
+	var discovery Discovery
+	if err := getJSON(ctx, httpClient, issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("failed to load OIDC discovery document: %w", err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("OIDC issuer mismatch: configured %q, provider reports %q", issuer, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document is missing required endpoints")
+	}
+
+	return &Provider{
+		config:     cfg,
+		discovery:  discovery,
+		keys:       newRemoteKeySet(discovery.JWKSURI, httpClient),
+		httpClient: httpClient,
+	}, nil
+}
+
+// LazyProvider runs discovery on first use, so the API starts even while
+// the identity provider is unreachable. Failed discovery is retried on the
+// next call.
+type LazyProvider struct {
+	config   Config
+	mu       sync.Mutex
+	provider *Provider
+}
+
+func NewLazyProvider(cfg Config) *LazyProvider {
+	return &LazyProvider{config: cfg}
+}
+
+func (lp *LazyProvider) Get(ctx context.Context) (*Provider, error) {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+
+	if lp.provider == nil {
+		provider, err := NewProvider(ctx, lp.config)
+		if err != nil {
+			return nil, err
+		}
+		lp.provider = provider
+	}
+
+	return lp.provider, nil
+}
+
+// AuthCodeURL builds the authorization request for the code flow with PKCE.
+func (p *Provider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	scopes := p.config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(p.discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return p.discovery.AuthorizationEndpoint + separator + query.Encode()
+}
+
+// Exchange redeems an authorization code at the token endpoint.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*Tokens, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Tokens
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return nil, fmt.Errorf("token endpoint returned %s: %s %s", resp.Status, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	return &body.Tokens, nil
+}
+
+// VerifyIDToken checks the signature against the provider JWKS, the issuer,
+// audience, expiry and that the nonce matches the one sent with the
+// authorization request.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.keys.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		jwt.WithIssuer(p.discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	tokenNonce, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if audience, _ := claims.GetAudience(); len(audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientID {
+			return nil, fmt.Errorf("%w: azp %q is not this client", ErrInvalidIDToken, azp)
+		}
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+
+	idToken := &IDToken{
+		Issuer:  p.discovery.Issuer,
+		Subject: subject,
+		Claims:  claims,
+	}
+	idToken.Email, _ = claims["email"].(string)
+	idToken.EmailVerified, _ = claims["email_verified"].(bool)
+	idToken.Name, _ = claims["name"].(string)
+	idToken.PreferredUsername, _ = claims["preferred_username"].(string)
+
+	return idToken, nil
+}
+
+// StringsClaim reads a claim that providers send either as a list or as a
+// single (possibly comma-separated) string, such as groups.
+func (t *IDToken) StringsClaim(name string) []string {
+	switch value := t.Claims[name].(type) {
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	case string:
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -2,6 +2,7 @@ package securities
 
 import (
 	"errors"
+	"sync"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -35,6 +36,20 @@ func VerifyPassword(hashedPassword, password string) error {
 	return nil
 }
 
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// DummyVerifyPassword spends the same bcrypt work as VerifyPassword so
+// lookups for unknown accounts can't be told apart by response time.
+func DummyVerifyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
 func GetHashCost(hashedPassword string) (int, error) {
 	if hashedPassword == "" {
 		return 0, errors.New("hash cannot be empty")
@@ -1,6 +1,9 @@
 package securities
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"testcase/internal/modules/user/entities"
 	"time"
@@ -9,11 +12,25 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	TokenTypeMFA     = "mfa"
+	TokenTypeStepUp  = "step_up"
+)
+
+const (
+	StepUpScopeDocumentAction = "document.action"
+)
+
 type JWTPayload struct {
-	UserID   uuid.UUID              `json:"user_id"`
-	Username string                 `json:"username"`
-	Role     entities.RoleEnum      `json:"role"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	UserID        uuid.UUID              `json:"user_id"`
+	TenantID      uuid.UUID              `json:"tenant_id"`
+	Username      string                 `json:"username"`
+	Role          entities.RoleEnum      `json:"role"`
+	EmailVerified bool                   `json:"email_verified"`
+	SessionID     string                 `json:"sid,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
 
 type TokenPair struct {
@@ -23,64 +40,86 @@ type TokenPair struct {
 
 type JWTClaims struct {
 	*JWTPayload
+	TokenType  string `json:"typ"`
+	Scope      string `json:"scope,omitempty"`
+	ResourceID string `json:"resource_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
+type JWTOptions struct {
+	Issuer   string
+	Audience []string
+	Leeway   time.Duration
+}
+
+var (
+	ErrTokenMalformed = errors.New("token is malformed")
+	ErrTokenExpired   = errors.New("token is expired")
+	ErrTokenNotActive = errors.New("token is not valid yet")
+	ErrTokenSignature = errors.New("token signature is invalid")
+	ErrTokenWrongType = errors.New("token has the wrong type")
+	ErrTokenClaims    = errors.New("token claims are invalid")
+)
+
 type JWTManager struct {
 	secretKey        string
 	refreshSecretKey string
 	tokenExpiry      time.Duration
 	refreshExpiry    time.Duration
-	apiKey           *string
+	keySet           *KeySet
+	options          JWTOptions
 }
 
-func NewJWTManager(secretKey string, refreshSecretKey string, tokenExpiry, refreshExpiry time.Duration, apiKey string) *JWTManager {
+// NewJWTManager signs access tokens with the key set when one is given and
+// falls back to HS256 with secretKey otherwise. Refresh and purpose tokens
+// are only consumed by this service and always use HMAC.
+func NewJWTManager(secretKey string, refreshSecretKey string, tokenExpiry, refreshExpiry time.Duration, keySet *KeySet, options JWTOptions) *JWTManager {
 	return &JWTManager{
 		secretKey:        secretKey,
 		refreshSecretKey: refreshSecretKey,
 		tokenExpiry:      tokenExpiry,
 		refreshExpiry:    refreshExpiry,
-		apiKey:           &apiKey,
+		keySet:           keySet,
+		options:          options,
 	}
 }
 
 func (jm *JWTManager) GenerateToken(jwtPayload *JWTPayload) (string, error) {
-	claims := &JWTClaims{
-		JWTPayload: jwtPayload,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(jm.tokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "pln-api",
-			Subject:   fmt.Sprintf("user:%d", jwtPayload.UserID),
-		},
+	claims := jm.newClaims(jwtPayload, TokenTypeAccess, uuid.NewString(), jm.tokenExpiry)
+
+	if jm.keySet != nil {
+		signingKey := jm.keySet.SigningKey()
+		token := jwt.NewWithClaims(signingKey.Method, claims)
+		token.Header["kid"] = signingKey.ID
+		return token.SignedString(signingKey.PrivateKey)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jm.secretKey))
+	return jm.signHMAC(claims)
 }
 
-func (jm *JWTManager) GenerateRefreshToken(jwtPayload *JWTPayload) (string, error) {
-	claims := &JWTClaims{
-		JWTPayload: jwtPayload,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(jm.refreshExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "pln-api",
-			Subject:   fmt.Sprintf("refresh:%d", jwtPayload.UserID),
-		},
-	}
+func (jm *JWTManager) GenerateRefreshToken(jwtPayload *JWTPayload, tokenID string) (string, error) {
+	return jm.signHMAC(jm.newClaims(jwtPayload, TokenTypeRefresh, tokenID, jm.refreshExpiry))
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jm.refreshSecretKey))
+func (jm *JWTManager) GenerateMFAToken(jwtPayload *JWTPayload, expiry time.Duration) (string, error) {
+	return jm.signHMAC(jm.newClaims(jwtPayload, TokenTypeMFA, uuid.NewString(), expiry))
+}
+
+func (jm *JWTManager) GenerateStepUpToken(jwtPayload *JWTPayload, scope, resourceID string, expiry time.Duration) (string, error) {
+	claims := jm.newClaims(jwtPayload, TokenTypeStepUp, uuid.NewString(), expiry)
+	claims.Scope = scope
+	claims.ResourceID = resourceID
+
+	return jm.signHMAC(claims)
 }
 
-func (jm *JWTManager) GenerateTokenPair(jwtPayload *JWTPayload) (string, string, error) {
+func (jm *JWTManager) GenerateTokenPair(jwtPayload *JWTPayload, refreshTokenID string) (string, string, error) {
 	accessToken, err := jm.GenerateToken(jwtPayload)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err := jm.GenerateRefreshToken(jwtPayload)
+	refreshToken, err := jm.GenerateRefreshToken(jwtPayload, refreshTokenID)
 	if err != nil {
 		return "", "", err
 	}
@@ -89,67 +128,145 @@ func (jm *JWTManager) GenerateTokenPair(jwtPayload *JWTPayload) (string, string,
 }
 
 func (jm *JWTManager) ValidateToken(tokenString, tokenType string) (*JWTClaims, error) {
-	var secret string
-	if tokenType == "refresh" {
-		secret = jm.refreshSecretKey
-	} else {
-		secret = jm.secretKey
-	}
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+	return jm.ValidateAndExtract(tokenString, tokenType)
+}
 
+func (jm *JWTManager) ExtractClaims(tokenString string) (*JWTClaims, error) {
+	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, &JWTClaims{})
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+	if claims, ok := token.Claims.(*JWTClaims); ok {
 		return claims, nil
 	}
 
-	return nil, fmt.Errorf("invalid token")
+	return nil, fmt.Errorf("invalid token claims")
 }
 
-func (jm *JWTManager) ExtractClaims(tokenString string) (*JWTClaims, error) {
-	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, &JWTClaims{})
+// ValidateAndExtract verifies signature, issuer, audience, exp/nbf (with the
+// configured leeway) and the typ claim. Errors wrap one of the ErrToken*
+// sentinels so callers can tell expired tokens from malformed ones.
+func (jm *JWTManager) ValidateAndExtract(tokenString, tokenType string) (*JWTClaims, error) {
+	parserOptions := []jwt.ParserOption{
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(jm.options.Leeway),
+	}
+	if jm.options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(jm.options.Issuer))
+	}
+	if len(jm.options.Audience) > 0 {
+		parserOptions = append(parserOptions, jwt.WithAudience(jm.options.Audience...))
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, jm.keyFunc(tokenType), parserOptions...)
 	if err != nil {
-		return nil, err
+		return nil, classifyTokenError(err)
 	}
 
-	if claims, ok := token.Claims.(*JWTClaims); ok {
-		return claims, nil
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid || claims.JWTPayload == nil {
+		return nil, ErrTokenClaims
+	}
+	if claims.TokenType != tokenType {
+		return nil, fmt.Errorf("%w: expected %s, got %q", ErrTokenWrongType, tokenType, claims.TokenType)
+	}
+	// Tokens issued before multi-tenancy carry no tenant; make their holders
+	// sign in again rather than guess one.
+	if claims.TenantID == uuid.Nil {
+		return nil, fmt.Errorf("%w: missing tenant_id", ErrTokenClaims)
 	}
 
-	return nil, fmt.Errorf("invalid token claims")
+	return claims, nil
 }
 
-func (jm *JWTManager) ValidateAndExtract(tokenString, tokenType string) (*JWTClaims, error) {
-	var secret string
-	if tokenType == "refresh" {
-		secret = jm.refreshSecretKey
-	} else {
-		secret = jm.secretKey
+func classifyTokenError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return fmt.Errorf("%w: %v", ErrTokenExpired, err)
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return fmt.Errorf("%w: %v", ErrTokenNotActive, err)
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return fmt.Errorf("%w: %v", ErrTokenSignature, err)
+	default:
+		return fmt.Errorf("%w: %v", ErrTokenClaims, err)
+	}
+}
+
+func (jm *JWTManager) newClaims(jwtPayload *JWTPayload, tokenType, tokenID string, expiry time.Duration) *JWTClaims {
+	now := time.Now()
+	claims := &JWTClaims{
+		JWTPayload: jwtPayload,
+		TokenType:  tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    jm.options.Issuer,
+			Subject:   fmt.Sprintf("user:%s", jwtPayload.UserID),
+		},
 	}
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+	if len(jm.options.Audience) > 0 {
+		claims.Audience = jwt.ClaimStrings(jm.options.Audience)
+	}
+
+	return claims
+}
+
+func (jm *JWTManager) signHMAC(claims *JWTClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jm.signingKey(claims.TokenType))
+}
+
+func (jm *JWTManager) keyFunc(tokenType string) jwt.Keyfunc {
+	if jm.keySet != nil && tokenType == TokenTypeAccess {
+		return func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			key, ok := jm.keySet.VerificationKey(kid)
+			if !ok {
+				return nil, fmt.Errorf("unknown signing key: %q", kid)
+			}
+			if token.Method.Alg() != key.Method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return key.PublicKey, nil
+		}
+	}
+
+	secret := jm.signingKey(tokenType)
+	return func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(secret), nil
-	})
-	if err != nil {
-		return nil, err
+		return secret, nil
 	}
-	claims, ok := token.Claims.(*JWTClaims)
-	if !ok || !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+}
+
+func (jm *JWTManager) JWKS() *JWKS {
+	if jm.keySet == nil {
+		return &JWKS{Keys: []JWK{}}
 	}
-	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
-		return nil, fmt.Errorf("token expired")
+	return jm.keySet.JWKS()
+}
+
+// signingKey returns the HMAC key for a token type. Short-lived purpose
+// tokens use a key derived from the access secret so they can never be
+// accepted where an access token is expected.
+func (jm *JWTManager) signingKey(tokenType string) []byte {
+	switch tokenType {
+	case TokenTypeRefresh:
+		return []byte(jm.refreshSecretKey)
+	case TokenTypeAccess:
+		return []byte(jm.secretKey)
+	default:
+		mac := hmac.New(sha256.New, []byte(jm.secretKey))
+		mac.Write([]byte(tokenType))
+		return mac.Sum(nil)
 	}
-	return claims, nil
 }
 
 func (jm *JWTManager) IsTokenExpired(tokenString string) bool {
@@ -158,7 +275,7 @@ func (jm *JWTManager) IsTokenExpired(tokenString string) bool {
 		return true
 	}
 
-	return claims.ExpiresAt.Before(time.Now())
+	return claims.ExpiresAt == nil || claims.ExpiresAt.Before(time.Now())
 }
 
 func (jm *JWTManager) GetTokenExpiry() time.Duration {
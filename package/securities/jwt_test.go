@@ -0,0 +1,144 @@
+package securities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func newTestJWTManager(options JWTOptions) *JWTManager {
+	return NewJWTManager("access-secret", "refresh-secret", time.Minute, time.Hour, nil, options)
+}
+
+func newTestPayload() *JWTPayload {
+	return &JWTPayload{UserID: uuid.New(), TenantID: uuid.New(), Username: "alice"}
+}
+
+var testJWTOptions = JWTOptions{Issuer: "testcase", Audience: []string{"testcase-api"}, Leeway: 30 * time.Second}
+
+// signAccessClaims signs claims with the access key, letting a test forge
+// a token with the given registered claims and typ.
+func signAccessClaims(t *testing.T, jm *JWTManager, edit func(claims *JWTClaims)) string {
+	t.Helper()
+
+	claims := jm.newClaims(newTestPayload(), TokenTypeAccess, uuid.NewString(), time.Minute)
+	edit(claims)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jm.signingKey(TokenTypeAccess))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestValidateAndExtractAcceptsIssuedTokens(t *testing.T) {
+	jm := newTestJWTManager(testJWTOptions)
+	payload := newTestPayload()
+
+	access, refresh, err := jm.GenerateTokenPair(payload, "refresh-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := jm.ValidateAndExtract(access, TokenTypeAccess)
+	if err != nil {
+		t.Fatalf("expected the access token to validate, got %v", err)
+	}
+	if claims.UserID != payload.UserID || claims.Issuer != "testcase" || len(claims.Audience) != 1 || claims.Audience[0] != "testcase-api" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+
+	claims, err = jm.ValidateAndExtract(refresh, TokenTypeRefresh)
+	if err != nil || claims.ID != "refresh-id" {
+		t.Fatalf("expected the refresh token to validate, got %+v (%v)", claims, err)
+	}
+}
+
+func TestValidateAndExtractChecksIssuerAndAudience(t *testing.T) {
+	issued, err := newTestJWTManager(testJWTOptions).GenerateToken(newTestPayload())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]JWTOptions{
+		"other issuer":   {Issuer: "other", Audience: testJWTOptions.Audience},
+		"other audience": {Issuer: testJWTOptions.Issuer, Audience: []string{"other-api"}},
+	}
+	for name, options := range cases {
+		if _, err := newTestJWTManager(options).ValidateAndExtract(issued, TokenTypeAccess); !errors.Is(err, ErrTokenClaims) {
+			t.Errorf("%s: expected ErrTokenClaims, got %v", name, err)
+		}
+	}
+
+	unscoped, _ := newTestJWTManager(JWTOptions{}).GenerateToken(newTestPayload())
+	if _, err := newTestJWTManager(testJWTOptions).ValidateAndExtract(unscoped, TokenTypeAccess); !errors.Is(err, ErrTokenClaims) {
+		t.Errorf("expected a token without iss and aud to be rejected, got %v", err)
+	}
+}
+
+func TestValidateAndExtractChecksTokenType(t *testing.T) {
+	jm := newTestJWTManager(testJWTOptions)
+
+	mislabelled := signAccessClaims(t, jm, func(claims *JWTClaims) { claims.TokenType = TokenTypeRefresh })
+	if _, err := jm.ValidateAndExtract(mislabelled, TokenTypeAccess); !errors.Is(err, ErrTokenWrongType) {
+		t.Errorf("expected ErrTokenWrongType, got %v", err)
+	}
+
+	mfa, _ := jm.GenerateMFAToken(newTestPayload(), time.Minute)
+	if _, err := jm.ValidateAndExtract(mfa, TokenTypeAccess); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("expected an MFA token to fail as an access token, got %v", err)
+	}
+	refresh, _ := jm.GenerateRefreshToken(newTestPayload(), "refresh-id")
+	if _, err := jm.ValidateAndExtract(refresh, TokenTypeAccess); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("expected a refresh token to fail as an access token, got %v", err)
+	}
+}
+
+func TestValidateAndExtractAppliesLeeway(t *testing.T) {
+	jm := newTestJWTManager(testJWTOptions)
+	now := time.Now()
+
+	cases := []struct {
+		name string
+		edit func(claims *JWTClaims)
+		want error
+	}{
+		{"nbf within leeway", func(c *JWTClaims) { c.NotBefore = jwt.NewNumericDate(now.Add(10 * time.Second)) }, nil},
+		{"nbf beyond leeway", func(c *JWTClaims) { c.NotBefore = jwt.NewNumericDate(now.Add(time.Minute)) }, ErrTokenNotActive},
+		{"exp within leeway", func(c *JWTClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-10 * time.Second)) }, nil},
+		{"exp beyond leeway", func(c *JWTClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) }, ErrTokenExpired},
+		{"no exp", func(c *JWTClaims) { c.ExpiresAt = nil }, ErrTokenClaims},
+		{"no tenant", func(c *JWTClaims) { c.TenantID = uuid.Nil }, ErrTokenClaims},
+	}
+	for _, tc := range cases {
+		_, err := jm.ValidateAndExtract(signAccessClaims(t, jm, tc.edit), TokenTypeAccess)
+		if tc.want == nil && err != nil {
+			t.Errorf("%s: expected the token to validate, got %v", tc.name, err)
+		}
+		if tc.want != nil && !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestValidateAndExtractRejectsForgedTokens(t *testing.T) {
+	jm := newTestJWTManager(testJWTOptions)
+
+	other := NewJWTManager("other-secret", "refresh-secret", time.Minute, time.Hour, nil, testJWTOptions)
+	forged, _ := other.GenerateToken(newTestPayload())
+	if _, err := jm.ValidateAndExtract(forged, TokenTypeAccess); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("expected a token signed with another key to be rejected, got %v", err)
+	}
+
+	claims := jm.newClaims(newTestPayload(), TokenTypeAccess, uuid.NewString(), time.Minute)
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if _, err := jm.ValidateAndExtract(unsigned, TokenTypeAccess); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("expected an unsigned token to be rejected, got %v", err)
+	}
+
+	if _, err := jm.ValidateAndExtract("not-a-token", TokenTypeAccess); !errors.Is(err, ErrTokenMalformed) {
+		t.Errorf("expected ErrTokenMalformed, got %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+package securities
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type VerificationKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	PublicKey crypto.PublicKey
+}
+
+type SigningKey struct {
+	VerificationKey
+	PrivateKey crypto.Signer
+}
+
+// KeySet holds the active signing key plus every key still accepted for
+// verification, so a new key can be rolled out before the old one retires.
+type KeySet struct {
+	signing      *SigningKey
+	verification map[string]*VerificationKey
+	order        []string
+}
+
+func NewKeySet(signing *SigningKey, verification ...*VerificationKey) *KeySet {
+	ks := &KeySet{
+		signing:      signing,
+		verification: make(map[string]*VerificationKey),
+	}
+
+	ks.add(&signing.VerificationKey)
+	for _, key := range verification {
+		ks.add(key)
+	}
+
+	return ks
+}
+
+func (ks *KeySet) add(key *VerificationKey) {
+	if _, exists := ks.verification[key.ID]; !exists {
+		ks.order = append(ks.order, key.ID)
+	}
+	ks.verification[key.ID] = key
+}
+
+func (ks *KeySet) SigningKey() *SigningKey {
+	return ks.signing
+}
+
+func (ks *KeySet) VerificationKey(kid string) (*VerificationKey, bool) {
+	key, ok := ks.verification[kid]
+	return key, ok
+}
+
+func (ks *KeySet) JWKS() *JWKS {
+	jwks := &JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		jwk, err := publicJWK(ks.verification[kid])
+		if err != nil {
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
+
+// LoadKeySet reads the signing key and any additional verification keys
+// (given as kid => PEM path) from disk.
+func LoadKeySet(signingKeyFile, signingKeyID string, verificationKeyFiles map[string]string) (*KeySet, error) {
+	privateKey, err := loadPrivateKey(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKey, err := newSigningKey(signingKeyID, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var verificationKeys []*VerificationKey
+	for kid, path := range verificationKeyFiles {
+		publicKey, err := loadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := newVerificationKey(kid, publicKey)
+		if err != nil {
+			return nil, err
+		}
+		verificationKeys = append(verificationKeys, key)
+	}
+
+	return NewKeySet(signingKey, verificationKeys...), nil
+}
+
+func newSigningKey(kid string, privateKey crypto.Signer) (*SigningKey, error) {
+	verificationKey, err := newVerificationKey(kid, privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{VerificationKey: *verificationKey, PrivateKey: privateKey}, nil
+}
+
+func newVerificationKey(kid string, publicKey crypto.PublicKey) (*VerificationKey, error) {
+	method, err := signingMethodFor(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &VerificationKey{ID: kid, Method: method, PublicKey: publicKey}
+	if key.ID == "" {
+		jwk, err := publicJWK(key)
+		if err != nil {
+			return nil, err
+		}
+		key.ID = jwkThumbprint(jwk)
+	}
+
+	return key, nil
+}
+
+func signingMethodFor(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, errors.New("only P-256 ECDSA keys are supported")
+		}
+		return jwt.SigningMethodES256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", publicKey)
+	}
+}
+
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("private key %s cannot sign", path)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q in %s", block.Type, path)
+	}
+}
+
+// loadPublicKey accepts a public key or, for convenience, a private key
+// whose public half is used.
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		signer, err := loadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return signer.Public(), nil
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	return block, nil
+}
+
+func publicJWK(key *VerificationKey) (JWK, error) {
+	jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+	switch pub := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64URL(pub.N.Bytes())
+		jwk.E = base64URL(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		ecdh, err := pub.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+		raw := ecdh.Bytes()
+		size := (len(raw) - 1) / 2
+		jwk.Kty = "EC"
+		jwk.Crv = "P-256"
+		jwk.X = base64URL(raw[1 : 1+size])
+		jwk.Y = base64URL(raw[1+size:])
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64URL(pub)
+	default:
+		return JWK{}, fmt.Errorf("unsupported key type %T", key.PublicKey)
+	}
+
+	return jwk, nil
+}
+
+// jwkThumbprint computes the RFC 7638 thumbprint used as the default kid.
+func jwkThumbprint(jwk JWK) string {
+	var members map[string]string
+	switch jwk.Kty {
+	case "RSA":
+		members = map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N}
+	case "EC":
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X, "y": jwk.Y}
+	default:
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X}
+	}
+
+	// encoding/json sorts map keys, which gives the required member order.
+	canonical, _ := json.Marshal(members)
+	sum := sha256.Sum256(canonical)
+	return base64URL(sum[:])
+}
+
+func base64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,10 @@
+package securities
+
+import "context"
+
+// PermissionChecker resolves what a role may do. Roles are looked up on
+// every check so permission changes apply to tokens already issued.
+type PermissionChecker interface {
+	RoleHasPermissions(ctx context.Context, role string, permissions ...string) (bool, error)
+	RolesWithPermission(ctx context.Context, permission string) ([]string, error)
+}
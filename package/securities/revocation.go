@@ -0,0 +1,120 @@
+package securities
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevocationStore persists revocation entries until they expire. Keys are
+// namespaced by RevocationList, so a store only needs to save and look up
+// opaque strings.
+type RevocationStore interface {
+	Revoke(ctx context.Context, key string, revokedAt, expiresAt time.Time) error
+	RevokedAt(ctx context.Context, key string) (*time.Time, error)
+}
+
+type memoryRevocationEntry struct {
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+type memoryRevocationStore struct {
+	mu        sync.RWMutex
+	entries   map[string]memoryRevocationEntry
+	lastSweep time.Time
+}
+
+// NewMemoryRevocationStore keeps entries in process memory. It is only
+// suitable for a single instance; use a database-backed store otherwise.
+func NewMemoryRevocationStore() RevocationStore {
+	return &memoryRevocationStore{
+		entries: make(map[string]memoryRevocationEntry),
+	}
+}
+
+func (s *memoryRevocationStore) Revoke(ctx context.Context, key string, revokedAt, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, entry := range s.entries {
+			if !entry.expiresAt.After(now) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if existing, ok := s.entries[key]; ok && existing.revokedAt.After(revokedAt) {
+		revokedAt = existing.revokedAt
+	}
+	s.entries[key] = memoryRevocationEntry{revokedAt: revokedAt, expiresAt: expiresAt}
+
+	return nil
+}
+
+func (s *memoryRevocationStore) RevokedAt(ctx context.Context, key string) (*time.Time, error) {
+	s.mu.RLock()
+	entry, ok := s.entries[key]
+	s.mu.RUnlock()
+
+	if !ok || !entry.expiresAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	return &entry.revokedAt, nil
+}
+
+// RevocationList rejects access tokens before they expire. Tokens can be
+// revoked per session (sid) or per user, where every token the user was
+// issued before the revocation time is rejected.
+type RevocationList struct {
+	store       RevocationStore
+	tokenExpiry time.Duration
+	leeway      time.Duration
+}
+
+// NewRevocationList keeps each entry for as long as an affected token could
+// still pass validation: its expiry plus the clock-skew leeway.
+func NewRevocationList(store RevocationStore, tokenExpiry, leeway time.Duration) *RevocationList {
+	return &RevocationList{
+		store:       store,
+		tokenExpiry: tokenExpiry,
+		leeway:      leeway,
+	}
+}
+
+func (rl *RevocationList) RevokeSession(ctx context.Context, sessionID string) error {
+	now := time.Now()
+	return rl.store.Revoke(ctx, "sid:"+sessionID, now, now.Add(rl.tokenExpiry+rl.leeway))
+}
+
+func (rl *RevocationList) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now()
+	return rl.store.Revoke(ctx, "user:"+userID.String(), now, now.Add(rl.tokenExpiry+rl.leeway))
+}
+
+func (rl *RevocationList) IsRevoked(ctx context.Context, claims *JWTClaims) (bool, error) {
+	if claims.SessionID != "" {
+		revokedAt, err := rl.store.RevokedAt(ctx, "sid:"+claims.SessionID)
+		if err != nil || revokedAt != nil {
+			return revokedAt != nil, err
+		}
+	}
+
+	revokedAt, err := rl.store.RevokedAt(ctx, "user:"+claims.UserID.String())
+	if err != nil || revokedAt == nil {
+		return false, err
+	}
+
+	// iat only has second precision, so a token issued in the same second as
+	// the revocation is treated as revoked.
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+	return !claims.IssuedAt.Time.After(revokedAt.Truncate(time.Second)), nil
+}
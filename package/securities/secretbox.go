@@ -0,0 +1,57 @@
+package securities
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// EncryptSecret seals a secret that has to be recovered later (unlike
+// passwords and API keys, which are only ever compared by hash) with
+// AES-256-GCM under a key derived from encryptionKey.
+func EncryptSecret(encryptionKey, plaintext string) (string, error) {
+	aead, err := newSecretAEAD(encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.New("failed to generate nonce")
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func DecryptSecret(encryptionKey, ciphertext string) (string, error) {
+	aead, err := newSecretAEAD(encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("invalid encrypted secret")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt secret")
+	}
+
+	return string(plaintext), nil
+}
+
+func newSecretAEAD(encryptionKey string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(encryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,37 @@
+package securities
+
+import "testing"
+
+func TestEncryptSecretRoundTrip(t *testing.T) {
+	sealed, err := EncryptSecret("key", "seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed == "seed" {
+		t.Fatal("expected the secret to be sealed")
+	}
+
+	opened, err := DecryptSecret("key", sealed)
+	if err != nil || opened != "seed" {
+		t.Fatalf("expected the secret back, got %q (%v)", opened, err)
+	}
+
+	again, _ := EncryptSecret("key", "seed")
+	if again == sealed {
+		t.Fatal("expected a fresh nonce for every seal")
+	}
+}
+
+func TestDecryptSecretRejectsWrongKeyAndGarbage(t *testing.T) {
+	sealed, _ := EncryptSecret("key", "seed")
+
+	if _, err := DecryptSecret("other-key", sealed); err == nil {
+		t.Error("expected a different key to fail")
+	}
+	if _, err := DecryptSecret("key", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"); err == nil {
+		t.Error("expected a plaintext value to fail")
+	}
+	if _, err := DecryptSecret("key", "c2hvcnQ="); err == nil {
+		t.Error("expected a value shorter than the nonce to fail")
+	}
+}
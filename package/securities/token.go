@@ -0,0 +1,26 @@
+package securities
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+func GenerateRandomToken(length int) (string, error) {
+	if length <= 0 {
+		return "", errors.New("token length must be positive")
+	}
+
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.New("failed to generate random token")
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
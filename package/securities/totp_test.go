@@ -0,0 +1,77 @@
+package securities
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed from RFC 6238 appendix B in base32.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPCodeMatchesRFC6238(t *testing.T) {
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range cases {
+		got, err := GenerateTOTPCode(rfc6238Secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("at %d: expected %s, got %s", unix, want, got)
+		}
+	}
+}
+
+func TestValidateTOTPCodeAcceptsNeighbouringSteps(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current := now.Unix() / totpPeriod
+
+	for offset := int64(-1); offset <= 1; offset++ {
+		code, _ := GenerateTOTPCode(rfc6238Secret, now.Add(time.Duration(offset*totpPeriod)*time.Second))
+		step, ok := ValidateTOTPCode(rfc6238Secret, code, now)
+		if !ok || step != current+offset {
+			t.Errorf("offset %d: expected step %d, got %d (%v)", offset, current+offset, step, ok)
+		}
+	}
+
+	stale, _ := GenerateTOTPCode(rfc6238Secret, now.Add(-2*totpPeriod*time.Second))
+	if _, ok := ValidateTOTPCode(rfc6238Secret, stale, now); ok {
+		t.Fatal("expected a code two steps old to be rejected")
+	}
+}
+
+func TestValidateTOTPCodeRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	cases := map[string][2]string{
+		"short code":     {rfc6238Secret, "28708"},
+		"long code":      {rfc6238Secret, "2870820"},
+		"invalid secret": {"not base32!", "287082"},
+	}
+	for name, input := range cases {
+		if _, ok := ValidateTOTPCode(input[0], input[1], now); ok {
+			t.Errorf("%s: expected the code to be rejected", name)
+		}
+	}
+}
+
+func TestIsTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsTOTPSecret(secret) {
+		t.Fatal("expected a generated secret to be recognised")
+	}
+
+	encrypted, err := EncryptSecret("mfa-key", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsTOTPSecret(encrypted) {
+		t.Fatal("expected an encrypted secret not to be mistaken for a plaintext one")
+	}
+}
@@ -0,0 +1,35 @@
+package securities
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const webhookSignaturePrefix = "sha256="
+
+// SignWebhook signs an outgoing webhook body as
+//
+//	sha256=HEX(HMAC-SHA256(secret, TIMESTAMP + "." + BODY))
+//
+// Including the timestamp lets receivers reject replayed deliveries.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyWebhookSignature(secret, timestamp string, body []byte, signature string) bool {
+	expected := SignWebhook(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
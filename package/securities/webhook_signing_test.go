@@ -0,0 +1,38 @@
+package securities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWebhookSignatureRoundTrip(t *testing.T) {
+	body := []byte(`{"type":"document.approved"}`)
+	signature := SignWebhook("whsec-test", "1700000000", body)
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Fatalf("expected a sha256= prefix, got %s", signature)
+	}
+	if !VerifyWebhookSignature("whsec-test", "1700000000", body, signature) {
+		t.Fatal("expected the signature to verify")
+	}
+	if !VerifyWebhookSignature("whsec-test", "1700000000", body, strings.ToUpper(signature)) {
+		t.Fatal("expected the hex digest to be compared case-insensitively")
+	}
+}
+
+func TestWebhookSignatureRejectsChanges(t *testing.T) {
+	body := []byte(`{"type":"document.approved"}`)
+	signature := SignWebhook("whsec-test", "1700000000", body)
+
+	cases := map[string]bool{
+		"other secret":    VerifyWebhookSignature("whsec-other", "1700000000", body, signature),
+		"other timestamp": VerifyWebhookSignature("whsec-test", "1700000001", body, signature),
+		"other body":      VerifyWebhookSignature("whsec-test", "1700000000", []byte(`{"type":"document.rejected"}`), signature),
+		"no prefix":       VerifyWebhookSignature("whsec-test", "1700000000", body, strings.TrimPrefix(signature, "sha256=")),
+	}
+	for name, verified := range cases {
+		if verified {
+			t.Errorf("%s: expected the signature to be rejected", name)
+		}
+	}
+}